@@ -1,16 +1,39 @@
 package main
 
 import (
+	"flag"
 	"log"
+	"os"
 
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/app"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/config"
 )
 
+var (
+	// migrateOnly - apply pending migrations of database and exit without start of server
+	migrateOnly = flag.Bool("migrate-only", false, "apply pending migrations of database and exit")
+
+	// migrateStatus - print applied and pending migrations of database and exit
+	migrateStatus = flag.Bool("migrate-status", false, "print applied and pending migrations of database and exit")
+
+	// memory - store tasks in memory of process like TODO_DBFILE=":memory:", tasks are lost on exit
+	memory = flag.Bool("memory", false, "store tasks in memory without database, tasks are lost on exit")
+)
+
 func main() {
+	flag.Parse()
 	cfg, err := config.NewConfig("./init/.env")
 	if err != nil {
 		log.Fatalf("main: error - %v", err)
 	}
+	if *memory {
+		cfg.DataBaseDataSourceName = config.DataBaseMemory
+	}
+	if *migrateOnly || *migrateStatus {
+		if err := app.Migrate(cfg, *migrateOnly, os.Stdout); err != nil {
+			log.Fatalf("main: error - %v", err)
+		}
+		return
+	}
 	app.Run(cfg)
 }
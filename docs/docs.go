@@ -27,16 +27,27 @@
 
 // package main ~> .cmd/app/main.go
 // logic of application
+/*
+flags of command line (without flags - start of server)
+ * --migrate-only   - apply pending migrations of database, print applied and exit
+ * --migrate-status - print applied and pending migrations of database and exit
+ * --memory         - store tasks in memory without database like TODO_DBFILE=":memory:", tasks are lost on exit
+*/
 
 // package model ~> ../internal/app
 /*
  - app.go
 main object of application
  * struct    - Sheduler    - contain all interfaces of application
- * func      - NewSheduler
+ * func      - NewSheduler - one clock for task service and clock service (lib/clock/clock.go)
  ------------------------------------------------------------------------------------------------------
  - run.go
- * func - Run  - start and close of application
+ * func - Run        - start and close of application
+ * func - openStores - tasks in memory (TODO_DBFILE=":memory:") or database with migrations and 'stores'
+ * func - stores     - store of tasks and store of password by driver of database
+ ------------------------------------------------------------------------------------------------------
+ - migrate.go
+ * func - Migrate - open database, apply pending migrations or print status of all (call from main by flags)
 */
 
 // package config ~> ../internal/config
@@ -47,6 +58,7 @@ rules for create config
  * func   - NewConfig
  * func   - loadConfig       - member of Config - read file and fill the config
  * func   - setConfig        - member of Config - set extension, ser Reader to 'viper.ReadConfig'
+ * func   - InMemory         - member of Config - tasks in memory of process (TODO_DBFILE=":memory:")
  * func   - ValidConfig      - member of Config - check of config on valid, call all 'validSomeFiled'
  * func   - validDataBase    - member of Config
  * func   - validServe       - member of Config
@@ -65,9 +77,19 @@ property of file for config onject
 /*
  - task.go
 describes property of Task - object stored in the database
- * struct      - TaskModel
+ * struct      - TaskModel    - Algorithm - name of algorithm for Repeat (lib/nextdate/registry.go)
+                                 Mode      - repeat from calendar ("schedule") or from day of completion ("completion")
+                                 Time      - optional time of day in format '15:04'
+                                 Exdates   - exception dates of series "20241231,20250107" (lib/nextdate/exdate.go)
  * 4 interface - TaskModel object maintenance in repository
  ------------------------------------------------------------------------------------------------------
+ - completion.go
+record of history when task is done, kept after task is deleted
+ * const     - CompletionNoteLen - max length of note
+ * struct    - CompletionModel   - ID of task, date and time of occurrence, time of done (UTC, RFC3339), note
+ * interface - TaskComplete      - save completion and new data of task (update or delete) in one transaction
+ * interface - TaskHistory       - completions of task by ID in order of done
+ ------------------------------------------------------------------------------------------------------
 describe property of Login
  - login.go
  * struct    - LoginModel
@@ -75,7 +97,7 @@ describe property of Login
 */
 
 // packege source ~> ../internal/database
-// SQLite - modernc.org/sqlite"
+// SQLite - modernc.org/sqlite" (default), PostgreSQL - github.com/lib/pq (TODO_DBDRIVER="postgres")
 /*
  - transaction.go
 wrapper to '*sql.DB' and '*sql.Tx'
@@ -90,13 +112,80 @@ wrapper to '*sql.DB' and '*sql.Tx'
 wrapper to 'dbTX' from transaction.go
  * struct - Source        - contain dbTX
  * func   - NewSource
- * func   - Init function - get property from config for initialize database
+ * func   - OpenDB        - open connection by driver without migrations, for SQLite create file of database if not exists
+ * func   - InitDB        - OpenDB and apply pending migrations (call from Run)
+ ------------------------------------------------------------------------------------------------------
+ - dialect.go
+ * struct - dialect   - driver, migrations and query of exist table for one database
+ * func   - dialectOf - dialect by driver from config, empty - SQLite
  ------------------------------------------------------------------------------------------------------
  - schema.go
- * table(s) for database in format string
+ * var   - sqliteMigrations   - ordered idempotent versions of schema: table 'scheduler', columns added after first release,
+                                table 'login', full-text search, table 'task_completions'
+ * var   - postgresMigrations - same versions in syntax of PostgreSQL
+ * const - searchTable        - FTS5 table 'scheduler_fts' of title and comment with triggers of sync
+ ------------------------------------------------------------------------------------------------------
+ - migration.go
+version of schema in table 'schema_version' (version, description, applied_at)
+ * struct - Migration  - version, description and time of apply for print, pending - empty time
+ * func   - execSQL    - step of migration as SQL query
+ * func   - addColumn  - step of migration, add column if missing
+ * func   - noop       - step of migration without changes, for version not needed by driver
+ * func   - Migrations - all migrations with time of apply, database is not changed
+ * func   - Migrate    - apply pending migrations in order, each in own transaction with record of version
  ------------------------------------------------------------------------------------------------------
  - query.go
  * describe logic of interfaces Task (look: package model ~> ../internal/model/task.go)
+ * list of tasks is ordered by date and time (task for whole day is first)
+ * FindTaskList       - search by word is only full-text (search.go), word without letters and digits -> nil
+ * struct - textSyntax - operators of text differ between drivers: LIKE/ILIKE, order of title by bytes
+ * findTaskList       - search, filters and order of TaskProperty composed into one query, values only as args
+                        list is ordered by key of TaskProperty, page after cursor by key of last task (relevance - only first page)
+                        search by word: full-text search is filter (SQLite), without it - LIKE/ILIKE (PostgreSQL)
+ * likeOf             - condition of search of part of word in title, comment or both
+ * queryConditions    - conditions of structured search: words by LIKE (NOT for negative), flag of repeat or FREQ of RRULE, dates
+ * afterCursor        - condition of tasks after key of last task of page
+ * orderOf            - ORDER BY by key and direction of TaskProperty, only known columns
+ * FindRepeatTaskList - recurring tasks with date before date of search, candidates for projected occurrence
+ * FindAgendaTaskList - tasks of window and recurring tasks with date before end of window (agenda)
+ ------------------------------------------------------------------------------------------------------
+ - history.go
+ * func   - CompleteTask       - member of Source - insert completion and update or delete task in one Transaction, task not exist -> ErrDataBaseNotFound
+ * func   - FindCompletionList - member of Source - completions of task ordered by ID, nothing found -> nil
+ ------------------------------------------------------------------------------------------------------
+ - search.go
+full-text search of SQLite (FTS5): case of all letters is ignored, prefix of word, ordered by relevance
+ * func   - matchQuery     - expression of MATCH from word of search, every term is prefix and required, only in column of filter
+ * const  - searchJoin     - JOIN of tasks by MATCH with relevance 'bm25' (title weighs more) for findTaskList
+ ------------------------------------------------------------------------------------------------------
+ - postgres.go
+ * struct - PostgresSource - Source for PostgreSQL, search by word ignores case ('ILIKE')
+ * func   - NewPostgresSource
+ ------------------------------------------------------------------------------------------------------
+ - memory.go
+ * struct - MemorySource - tasks in memory of process with same rules as query.go, safe for concurrent use
+ * func   - NewMemorySource
+ * func   - FindTaskList - same rules as query.go by TaskProperty: Matches, IsAfterCursor, Less, TaskQuery.MatchesTerms
+ * func   - CompleteTask, FindCompletionList - history of done tasks like history.go, under one lock
+ * func   - contains     - word is part of text as LIKE '%word%'
+ * func   - filter       - matched tasks ordered by date, time and ID
+ * func   - like         - operator LIKE: '%', '_', case of all letters is ignored like FTS5 of SQLite and ILIKE of PostgreSQL
+ ------------------------------------------------------------------------------------------------------
+ - login.go
+password of user in table 'login', shared by all instances of application (PostgreSQL)
+ * struct - LoginSource  - implement 'model.LoginRead' like 'datauser.UserData'
+ * func   - NewLoginSource
+ * func   - SeedPassword - password from config only for database without password, empty - nothing
+*/
+
+// package dbtest ~> ../internal/database/dbtest
+// test database of PostgreSQL for tests of stores (database, app)
+/*
+ - postgres.go
+ * const  - PostgresEnv    - TODO_TEST_POSTGRES - connection string of test database
+ * func   - Postgres       - connection string to own schema of test "todo_test_<random>", schema is dropped after test
+                             empty variable or database is not available -> test is skipped
+ * func   - withSearchPath - connection string with "search_path" (URL or key=value form)
 */
 
 // package datauser ~> ../internal/datauser
@@ -111,6 +200,19 @@ implementatain of 'LoginRead interface' use object 'UserData'
  * func   - PasswordExist - member of UserData - compare lenght of password with 0
 */
 
+// package clock ~> ../internal/lib/clock
+// source of "now" for services
+/*
+ - clock.go
+ * interface - Clock    - Now() time.Time
+ * func      - New      - debug clock if TODO_DEBUG_CLOCK=true, othercase system clock (call from Run)
+ * struct    - System   - time.Now
+ * type      - Fixed    - always the same moment (for tests)
+ * struct    - Debug    - frozen time or offset from time of base clock, safe for concurrent use
+ * func      - NewDebug
+ * func      - Freeze, Shift, Reset, State - member of Debug
+*/
+
 // package jwtsign ~> ../internal/lib/jwtsign
 // contain 'secretkey' for create, parse 'jwt.Token'
 /*
@@ -127,13 +229,106 @@ implementatain of 'LoginRead interface' use object 'UserData'
 /*
  - nextdate.go
  * type - NextDateFunc    - func(now time.Time, dstart string, repeat string) (string, error) - describes logic of algorithm for find date in model.TaskModel
- * func - NextDate        - main function for algorithm (find flag and call selected function by flag)
+ * func - NextDate        - main function for algorithm (parse rule and call selected function by flag)
+ * func - nextDateByRule  - select function by flag of 'Rule'
  * func - nextDateByDay   - create date by day(s) (UNIX - method)
- * func - numberOfDays    - find count of days for func nextDateByDay
- * func - nextDateByWeek  - date by day of week
- * func - daysOfWeek      - find UNIQUE days for func nextDateByWeek
+ * func - nextDateByWeek  - date by day of week (jump to week in phase, no more than two weeks are checked)
  * func - nextDateByMonth - date by 1. number of day or 2. number of month(s) with number of day(s)
- * func - monthAndDay     - find days and month for func nextDateByMonth
+ * func   - nextDateInMonths  - jump to month in phase and calculate day(s) of month, number of checked months not depend on 'now'
+ * func   - existInMonths     - day(s) exist in month(s): "m 31 2" - wrong rule at parse time
+ * func   - weekNumber, monthNumber - number of week, month for check phase of interval
+ * func   - nextDateByNthWeekday - date by ordinal weekday(s) of month: n 2#2 - second tuesday, n 5#-1 1,4 - last friday of january, april
+ * func   - dayOfOrdinalWeekday  - day of month for ordinal weekday, calculated from first day of month
+ * struct - endCondition      - optional end of series: "until date" or "count number" (tail of repeat)
+ * func   - ReduceCount       - decrease "count" after task is done (call from ReduceCountOf)
+ ------------------------------------------------------------------------------------------------------
+ - rule.go
+grammar: flag[/interval] [argument(s)] [clause value]... - "w/2 1,4 until 20250101 shift next"
+ * struct - ParseError   - wrong repeat with position (byte from 0) and reason, errors.Is(err, ErrNextDateWrongRepeat)
+ * struct - Rule         - parsed repeat: flag, interval, days, weekdays, months, end condition, shift
+ * func   - ParseRule    - check repeat once and create 'Rule' (parseFlag, parseArgs, parseClauses)
+ * func   - splitFields  - split repeat by space or list by comma with positions
+ * method - Next         - next date of 'Rule' after now (end condition is not checked) ------------------------------------------------------------------------------------------------------
+ - workday.go
+working day - not saturday, not sunday and not holiday
+ * var  - holidays              - non-exported global variable (set only in start application)
+ * func - NewHolidayCalendar    - read calendar file from config (TODO_HOLIDAYS): .ics (DTSTART of events) or list of dates
+ * func - IsWorkday             - check date by weekend and holidays
+ * func - shiftNextDate         - clause "shift next|prev" - move date of rule to workday
+ * func - nextDateByBusinessDay - date by working days: b 3 - every 3 working days
+ * func - addWorkdays           - working day number 'n' after date: full weeks and remainder (addWeekdays), then holidays
+ * func - workdaysBetween       - number of working days between dates without loop by days (weekdaysUpTo)
+ * func - WorkdayDate           - move date of task to workday if rule work only with working days (call from executeDate)
+ ------------------------------------------------------------------------------------------------------
+ - rrule.go
+iCalendar RRULE (RFC 5545): FREQ, INTERVAL, BYDAY, BYMONTHDAY, BYMONTH, COUNT, UNTIL
+ * func   - IsRRule        - check repeat is written in RRULE syntax (FREQ=... or RRULE:FREQ=...)
+ * func   - RRule          - algorithm 'NextDateFunc' for RRULE
+ * func   - NormalizeRRule - canonical form of RRULE (call from TaskDecode)
+ * func   - ToRRule        - write rule of custom grammar as RRULE (flag 'b' and clause "shift" have no RRULE form)
+ * struct - rrule          - parsed RRULE, parseRRule - check all parts
+ * method - next           - jump to period in phase of INTERVAL, days of period are checked by BYDAY, BYMONTHDAY, BYMONTH
+                              no date in 400 years -> ErrNextDateWrongRepeat
+ * method - canMatch       - BYDAY, BYMONTHDAY and BYMONTH fit some date of 28 years, never -> error of parseRRule
+ ------------------------------------------------------------------------------------------------------
+ - registry.go
+algorithms of type 'NextDateFunc' by name: "nextdate" - custom grammar, "rrule" - RFC 5545
+ * func - Register        - add algorithm by name (before start of application)
+ * func - Lookup          - algorithm by name, ErrNextDateUnknownAlgorithm
+ * func - Algorithms      - sorted names of registered algorithms
+ * func - DetectAlgorithm - name by syntax of repeat (RRULE) or default
+ * func - Validate        - check syntax of repeat by algorithm and return normalized repeat (call from TaskDecode)
+ * func - IsBuiltIn       - algorithm is "nextdate" or "rrule", algorithm added by 'Register' has no "count" and working days
+ * func - ReduceCountOf   - ReduceCount by algorithm, algorithm added by 'Register' -> repeat without change (call from DoneTask)
+ * func - WithoutCountOf  - repeat without "count" by algorithm, exception date is not done (call from executeExdate)
+ ------------------------------------------------------------------------------------------------------
+ - occurrences.go
+ * const - MaxOccurrences - limit of dates in one list
+ * func  - NextDates      - next 'n' dates of series, algorithm by syntax of repeat
+ * func  - Occurrences    - dates of series not before 'now' by algorithm: 'n' dates or all dates of window (call from PreviewTask)
+ * func  - OccursOn       - date is one of dates of series from first date (call from ReadTaskList)
+ * func  - Between        - dates of series in window from..to, "count" from first date (call from AgendaTask)
+   Occurrences, OccursOn, Between take name of algorithm, series of algorithm added by 'Register' is ended only by algorithm
+ ------------------------------------------------------------------------------------------------------
+ - describe.go
+text of repeat for user: "m -1,15 1,4,7,10" -> "on the 15th and the last day of Jan, Apr, Jul and Oct"
+ * func      - Describe    - sentence of valid repeat (custom grammar or RRULE) in language "en" or "ru"
+ * func      - IsLanguage  - description is supported in language
+ * struct    - description - rule of custom grammar or RRULE in one form (sorted days, months, end, shift)
+ * interface - language    - rules of sentence, implemented by 'english' and 'russian'
+ ------------------------------------------------------------------------------------------------------
+ - hourly.go
+rule 'h': "h 4" - every 4 hours, "h 4 09:00-18:00" - every 4 hours of window, date of rule for 'NextDateFunc' - next day
+ * func - IsHourly          - repeat of custom grammar with flag 'h'
+ * func - StartTime         - first occurrence not before now (call from executeTime)
+ * func - NextTime          - date and time of next occurrence after now (call from updateTimeAfterDone)
+ * func - NextTimeAfterDone - date and time of next occurrence from moment of completion
+ ------------------------------------------------------------------------------------------------------
+ - mode.go
+mode of repeat: "schedule" - dates fixed to calendar, "completion" - series restarted from day of completion
+ * func - IsMode            - mode is supported (empty - "schedule")
+ * func - NextDateAfterDone - next date of series from day of completion (call from updateDateAfterDone)
+ ------------------------------------------------------------------------------------------------------
+ - exdate.go
+exception dates of series: "20241231,20250107" - skipped occurrences, not counted in "count"
+ * func - NormalizeExdates - check dates (not more MaxExdates) and write sorted list without repeats (call from TaskDecode)
+ * func - SplitExdates     - list of dates from stored form (call from TaskEncode)
+ * func - Except           - algorithm 'NextDateFunc' which skips exception dates (call from taskAlgorithm)
+ * func - ExceptDate       - date of task is exception date -> next date of series from its start (call from executeExdate)
+ * func - withoutCount     - repeat without end condition "count" (custom grammar and RRULE)
+ * func - ExceptTime       - rule 'h' - occurrence at exception date -> first occurrence of next day without exception
+*/
+
+// package timezone ~> ../internal/lib/timezone
+// time zone in which "today" of tasks is counted
+/*
+ - timezone.go
+ * var  - location     - non-exported global variable, default time zone (set only in start application)
+ * func - NewLocation  - set location from config (TODO_TIMEZONE): IANA name or offset "+03:00", empty - local time zone
+ * func - Load         - time zone by IANA name or offset
+ * func - WithLocation - put time zone of request into context.Context (call from middleware 'Timezone')
+ * func - Location     - time zone of context or default
+ * func - In           - time ("now" of clock) in time zone of context (call from usecase and deserializer)
 */
 
 // packege server ~> ../internal/server
@@ -153,14 +348,29 @@ create content for ResponseWriter
  * interface - TaskCreateCase
  \_ 'CreateTask' - take 'model.TaskModel' and return '*serializer.TaskIDResponse',error
  * interface - TaskReadCase
- |_ 'ReadTask'   - take 'uint' ID of task and return '*serializer.TaskResponse'
+ |_ 'ReadTask'   - take 'uint' ID of task, '*entity.TaskFormat' and return '*serializer.TaskResponse'
  \_ ReadTaskList - take '*entity.TaskProperty' see (../internal/services/entity/taskproperty.go) and return '*serializer.TaslListResponse',error
  * interface - TaskUpdateCase
  \_ 'UpdateTask' - take 'model.TaskModel' for update in some store and return only error
  * interface - TaskDeleteCase
  \_ 'DeleteTask' - take 'uint' for delete task by ID and return only error
  * interface - TaskDoneCase
- \_ 'DoneTask' - take 'uint' for update status(update or delete) task by ID and return only error
+ \_ 'DoneTask' - take 'uint' and note, update status(update or delete) task by ID, save completion to history and return only error
+ * interface - TaskHistoryCase
+ \_ 'ReadTaskHistory' - take 'uint' ID of task and return '*serializer.HistoryResponse',error
+ * interface - TaskAlgorithmCase
+ \_ 'DefaultAlgorithm' - name of default algorithm from config for check of repeat in decoders
+ * interface - TaskSkipCase
+ \_ 'SkipTask' - take 'uint', move recurring task by ID past its current occurrence without done, return only error
+ * interface - TaskPreviewCase
+ \_ 'PreviewTask' - take '*entity.TaskPreview' (stored task or ad-hoc rule) and return '*serializer.TaskPreviewResponse',error
+ * interface - TaskAgendaCase
+ \_ 'AgendaTask' - take '*entity.TaskAgenda' (window of days) and return '*serializer.AgendaResponse',error
+ * interface - ClockCase
+ \_ 'Now' - "now" of application in time zone of request
+ * interface - ClockDebugCase
+ |_ 'ReadClock'   - return '*serializer.ClockResponse',error
+ \_ 'ChangeClock' - take '*entity.ClockSetting' (freeze, offset or reset) and return '*serializer.ClockResponse',error
  * interface - LoginValidPasswordCase
  |_ 'CreateToken' - take 'model.LoginModel' for create 'jwt.Token' after return '*serializer.TokenResponse', error
  \_ 'UserExist'   - check login exist in application, return bool,error
@@ -179,6 +389,14 @@ create content for ResponseWriter
  * func      - AuthZ          - describes biz logic of autorization
  checks 'token' in cookie by key 'token', after parse token and check for validity, find field in token by key 'content' after prints received line
  ------------------------------------------------------------------------------------------------------
+ - clockcase.go
+ * interface - ClockService    - contain ClockCase and ClockDebugCase
+ * struct    - clockService    - have a clock.Clock, the same as in taskService
+ * func      - NewClockService
+ * func      - Now             - time of clock in time zone of request
+ * func      - ReadClock       - "now" of server, frozen or offset of debug clock
+ * func      - ChangeClock     - freeze, move or reset debug clock, othercase ErrCaseClockNotDebug
+ ------------------------------------------------------------------------------------------------------
  - logincase.go
  * interface - LoginService    - contain all business logic interfaces LoginCase
  * interface - MultiLogin      - all interfaces of 'model.LoginRead' work with store
@@ -192,10 +410,15 @@ create content for ResponseWriter
  * interface - MultiTask   - all interfaces of 'model.TaskModel work with store
  * struct    - taskService
 1. taskRepository logic -> work with MultiTask (internal/database/)
-2. have a algorithm 'nextdate.NextDateFunc' (lib/nextdate/nextdate.go) for find next date of Task
+2. have a name of default algorithm 'nextdate.NextDateFunc' (lib/nextdate/registry.go) for find next date of Task
+3. have a clock.Clock - source of "now" (lib/clock/clock.go)
  * func      - NewTaskService
+ * func      - now                 - time of clock in time zone of request
  * func      - setNextDate         - get name of algorithm from config and return function of type 'nextdate.NextDateFunc'
+ * func      - DefaultAlgorithm    - name of algorithm from config, decoders check repeat by it
+ * func      - taskAlgorithm       - set name of algorithm (from task, by syntax of repeat or default) and mode of repeat to task and return algorithm
  * func      - CreateTask          - logic of create task (more information insade package)
+ * func      - executeTime         - finds time of task with rule 'h' when a task was created or updated
  * func      - executeDate         - finds date when a task was created or updated (details in package)
  * func      - ReadTask            - logic of read Task by ID from database and create object for Response
  * func      - UpdateTask          - rules for update Task By ID
@@ -204,8 +427,21 @@ create content for ResponseWriter
 1. use func 'updateDateAfterDone' see bellow (more details in package)
 2. if rules for repeat Task is empty - delete Task from store
 3. othercase update task in database
- * func      - updateDateAfterDone - finds date when a task was done
+4. completion (date and time of occurrence, time of done, note) and new data of task are saved by 'complete' in one transaction
+ * func      - complete            - save '*entity.TaskDone' to store, task not exist -> ErrCaseTaskNotFound
+ * func      - ReadTaskHistory     - completions of task, history of deleted task is kept, unknown task -> ErrCaseTaskNotFound
+ * func      - updateDateAfterDone - finds date when a task was done (from calendar of task or from today by mode of repeat)
+ * func      - updateTimeAfterDone - finds date and time when a task with rule 'h' was done
+ * func      - executeExdate       - date of task is exception date -> next occurrence from start of series (create, update, done, skip)
+ * func      - SkipTask            - move task to next occurrence, "count" is not reduced, series ended - delete task
+ * func      - nextOccurrence      - occurrence after date (and time of rule 'h') of task, used only in SkipTask
  * func      - ReadTaskList        - create Task List for response, by rules:(*entity.TaskProperty) see (/service/entity/taskproperty.go)
+                                   full page -> cursor of next page from last task (/service/entity/taskcursor.go), order by relevance - no cursor
+ * func      - projectTasks        - recurring tasks with occurrence on date of search, used only in ReadTaskList
+ * func      - hourlyOn            - first occurrence of rule 'h' in day (search by date, agenda)
+ * func      - AgendaTask          - occurrences of tasks by days of window:(*entity.TaskAgenda), ordered by time and ID
+ * func      - occurrencesOf       - stored date and dates of series of task in window, used only in AgendaTask
+ * func      - PreviewTask         - dates of series for stored task or ad-hoc rule by rules:(*entity.TaskPreview) see (lib/nextdate/occurrences.go)
 ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 */
 
@@ -213,11 +449,44 @@ create content for ResponseWriter
 /*
  - taskdecode.go
  * struct - TaskDecode    - create TaskModel from Request
- * func   - NewTaskDecode
+ * func   - NewTaskDecode - take name of default algorithm from config (ALGORITHM_TASK_DATE), same as in usecase
  * func   - Model         - return TaskModel from LoginDecode
- * func   - Decode        - parse TaskDecode and create TaskModel
+ * func   - Decode        - parse TaskDecode and create TaskModel (repeat in RRULE syntax is checked and normalized, algorithm is checked in registry, mode, time and exception dates are checked)
  * func   - executeDate   - rules for find 'data' when create new Task
  ------------------------------------------------------------------------------------------------------
+ - donedecode.go
+ * struct - DoneDecode    - optional body of /api/task/done {"note":"..."}, empty body - without note
+ * func   - NewDoneDecode
+ * func   - Decode        - parse body, note longer than model.CompletionNoteLen -> error
+ ------------------------------------------------------------------------------------------------------
+ - previewdecode.go
+ * struct - PreviewDecode - params of URL query: id or date, repeat, algorithm; now, n, to, describe
+ * func   - NewPreviewDecode - take "now" of clock for request without param 'now' and name of default algorithm from config
+ * func   - Entity        - return *entity.TaskPreview
+ * func   - Decode        - check params (full error list) and create TaskPreview
+ * func   - rule          - check ad-hoc rule same as TaskDecode
+ ------------------------------------------------------------------------------------------------------
+ - tasklistdecode.go
+ * struct - TaskListDecode - params of URL query: search, limit (1..entity.MaxTaskLimit), cursor (next_cursor of response),
+                             from, to, kind, overdue, in, sort, order
+ * func   - NewTaskListDecode - today of clock for 'overdue'
+ * func   - Entity        - return *entity.TaskProperty
+ * func   - Decode        - check params (full error list) and create TaskProperty after cursor with TaskFilter and TaskQuery
+ * func   - checkValue    - value of param is one of allowed, othercase 'unknown value'
+ ------------------------------------------------------------------------------------------------------
+ - agendadecode.go
+ * const  - DefaultAgendaDays, MaxAgendaDays - window without 'to' (week) and limit of window (quarter)
+ * struct - AgendaDecode  - params of URL query: from (empty - today of clock), to (empty - week from 'from')
+ * func   - NewAgendaDecode - take "now" of clock for request without param 'from'
+ * func   - Entity        - return *entity.TaskAgenda
+ * func   - Decode        - check params (full error list) and create TaskAgenda
+ ------------------------------------------------------------------------------------------------------
+ - clockdecode.go
+ * struct - ClockDecode   - body of /api/debug/clock: now (freeze) or offset, empty - reset
+ * func   - NewClockDecode
+ * func   - Entity        - return *entity.ClockSetting
+ * func   - Decode        - check fields (full error list) and create ClockSetting
+ ------------------------------------------------------------------------------------------------------
  - /deserializer/logindecode.go
  * struct - LoginDecode   - create LoginModel from Request
  * func   - NewLoginDecode
@@ -235,29 +504,135 @@ create content for ResponseWriter
  ------------------------------------------------------------------------------------------------------
  - taskencode.go
  * struct - TaskResponse     - object contain one Task for Response
- * struct - TaskEncode       - contain start data for TaskResponse
+ * struct - TaskEncode       - contain start data for TaskResponse and optional fields by '*entity.TaskFormat' (rrule)
  * func   - Response         - member of TokenEncode create TaskResponse
  * struct - TaslListResponse - object contain array of Task for Response
- * struct - TaskListEncode   - contain array of TaskModel
+ * struct - TaskListEncode   - contain array of TaskModel and ID of tasks with projected occurrence ("projected":true),
+                               cursor of next page ("next_cursor"), empty - last page or order by relevance
  * func   - Response         - member of TaskListEncode create array of TaskResponse
  * struct - TaskIDResponse   - Task ID Transfer Rules
  * strcut - TaskIDEncode     - have a positive number of Task
  * func   - Response         - member of TaskIDEncode create TaskIDResponse
+ ------------------------------------------------------------------------------------------------------
+ - previewencode.go
+ * struct - TaskPreviewResponse - ID of stored task (optional), dates of series and description (optional)
+ * struct - TaskPreviewEncode   - contain ID and dates
+ * func   - Response            - member of TaskPreviewEncode create TaskPreviewResponse
+ ------------------------------------------------------------------------------------------------------
+ - historyencode.go
+ * struct - HistoryResponse    - ID of task and completions, without completions - empty list
+ * struct - CompletionResponse - date, time, "done_at" and note of completion
+ * struct - HistoryEncode      - contain ID of task and CompletionModel list
+ * func   - Response           - member of HistoryEncode create HistoryResponse
+ ------------------------------------------------------------------------------------------------------
+ - agendaencode.go
+ * struct - AgendaResponse    - days of agenda, day without tasks - empty list
+ * struct - AgendaDayResponse - date and occurrences of tasks
+ * struct - AgendaEncode      - contain days, occurrences by day and stored date of task ("projected":true for other dates)
+ * func   - Response          - member of AgendaEncode create AgendaResponse
+ ------------------------------------------------------------------------------------------------------
+ - clockencode.go
+ * struct - ClockResponse - "now" of server, frozen, offset
+ * struct - ClockEncode   - contain time, frozen and offset
+ * func   - Response      - member of ClockEncode create ClockResponse
 */
 
 // package entity ~> ../internal/services/entity
 /*
  - taskproperty.go
 rules for find 'model.TaskModel' array in database
- * struct - TaskProperty    - characteristics of Task(s) and lenght array []TaskModel from query (database)
+ * struct - TaskProperty    - characteristics of Task(s) and lenght array []TaskModel from query (database), page after cursor
+ * const  - DefaultTaskLimit, MaxTaskLimit - limit without param and max limit of page
  * func   - NewTaskProperty - call parseProperty setLimit
- * func   - setLimit        - member TaskProperty - find limit        (LIMIT)
+ * func   - After           - member TaskProperty - page after cursor, nil - first page
+ * func   - setLimit        - member TaskProperty - find limit        (LIMIT), zero - default
  * func   - parseProperty   - member TaskProperty - find word or date (WHERE)
  * func   - IsDate          - member TaskProperty - find by date
  * func   - IsWord          - member TaskProperty - find by word
  * func   - PassDate        - member TaskProperty
  * func   - PassWord        - member TaskProperty
  * func   - PassLimite      - member TaskProperty
+ * func   - PassCursor      - member TaskProperty
+ * func   - Filter          - member TaskProperty - conditions and order of TaskFilter, nil - without conditions
+ * func   - PassFilter      - member TaskProperty
+ * func   - Query           - member TaskProperty - structured search instead of word, nil - plain search
+ * func   - PassQuery       - member TaskProperty
+ * func   - IsRelevance     - member TaskProperty - search by word without key of order
+ * func   - SortKey         - member TaskProperty - key of order, default - date
+ * func   - Less            - member TaskProperty - order of two tasks by key and direction, same key - by id
+ * func   - IsAfterCursor   - member TaskProperty - task is on page after cursor (relevance - always, cursor is ignored)
+ * func   - Matches         - member TaskProperty - task fits date of search, filters, repeat and dates of TaskQuery
+ ------------------------------------------------------------------------------------------------------
+ - taskquery.go
+structured search of list (/api/tasks?search=title:report repeat:w before:2025-06-01 -draft)
+ * var    - ErrEntityInvalidQuery - wrong structured search
+ * struct - QueryError      - position and reason of wrong term, Unwrap -> ErrEntityInvalidQuery
+ * const  - RepeatAny, RepeatNone - values of key 'repeat' besides flag of rule
+ * var    - repeatFreqs     - FREQ of RRULE for flag: d - DAILY, w - WEEKLY, m - MONTHLY, y - YEARLY
+ * struct - TaskTerm        - word, field (title, comment or both), negative
+ * struct - TaskQuery       - terms, flag of repeat, window of dates
+ * func   - ParseTaskQuery  - compile search, plain word or date -> nil (never error)
+ * func   - isStructured    - term is negation or known key
+ * func   - splitQuery      - terms between spaces outside quotes, quote without pair -> QueryError
+ * func   - cutKey          - key of ASCII letters and value
+ * func   - unquote, parseQueryDate
+ * func   - MatchesTerms    - member TaskQuery - task fits all terms by 'contains' of store
+ * func   - matches         - member TaskQuery - task fits repeat and dates
+ * func   - matchesRepeat   - member TaskQuery - rule starts with flag or RRULE has FREQ of flag
+ * func   - PassTerms, PassRepeat, PassFreq, PassAfter, PassBefore - member TaskQuery
+ * func   - PassWord, PassField, IsNegative - member TaskTerm
+ ------------------------------------------------------------------------------------------------------
+ - taskfilter.go
+filters and order of list of tasks (/api/tasks?from=&to=&kind=&overdue=&in=&sort=&order=)
+ * const  - KindRecurring, KindOnce         - kind of task by repeat
+ * const  - FieldTitle, FieldComment        - field of search by word
+ * const  - SortDate, SortTitle, SortID     - keys of order
+ * struct - TaskFilter      - window of dates, overdue, kind, field of search, key and direction of order
+ * func   - NewTaskFilter
+ * func   - PassFrom, PassTo, PassBefore, PassKind, PassField, PassSort, IsDesc - member TaskFilter
+ ------------------------------------------------------------------------------------------------------
+ - taskcursor.go
+position of next page in list of tasks (/api/tasks?cursor=)
+ * struct - TaskCursor      - date, time, id, title of last task of page (order by relevance has no cursor)
+ * func   - NewTaskCursor
+ * func   - ParseTaskCursor - cursor from string of Encode, wrong string -> false
+ * func   - Encode          - member TaskCursor - opaque string for response (base64)
+ * func   - PassTask        - member TaskCursor - keys of last task for compare (TaskProperty.Less)
+ * func   - PassDate, PassTime, PassTitle, PassID - member TaskCursor
+ ------------------------------------------------------------------------------------------------------
+ - taskformat.go
+optional fields of task in response (/api/task?id=1&rrule=true&describe=en)
+ * struct - TaskFormat    - rrule - add repeat rule in RRULE syntax, describe - language of description of repeat
+ * func   - NewTaskFormat
+ * func   - IsRRule       - member TaskFormat
+ * func   - IsDescribe    - member TaskFormat
+ * func   - PassLanguage  - member TaskFormat
+ ------------------------------------------------------------------------------------------------------
+ - taskpreview.go
+rules for find dates of series (/api/nextdates?id=1&n=5)
+ * struct - TaskPreview    - stored task (id) or ad-hoc rule (task), now, end of window, limit, language of description
+ * func   - NewTaskPreview
+ * func   - IsTask         - member TaskPreview - stored task
+ * func   - PassID, PassTask, PassNow, PassTo, PassLimit, PassLanguage - member TaskPreview
+ ------------------------------------------------------------------------------------------------------
+ - taskdone.go
+completion of task for store (model.TaskComplete)
+ * struct - TaskDone       - completion and new data of task, nil - last occurrence, task is deleted
+ * func   - NewTaskDone
+ * func   - PassCompletion, PassNext, IsLast - member TaskDone
+ ------------------------------------------------------------------------------------------------------
+ - taskagenda.go
+window of days for agenda (/api/agenda?from=20240101&to=20240107)
+ * struct - TaskAgenda     - first and last day of window
+ * func   - NewTaskAgenda
+ * func   - PassFrom, PassTo - member TaskAgenda
+ * func   - Days           - member TaskAgenda - all days of window
+ ------------------------------------------------------------------------------------------------------
+ - clocksetting.go
+change of debug clock (/api/debug/clock)
+ * struct - ClockSetting   - freeze time or offset, both zero - reset
+ * func   - NewClockSetting
+ * func   - IsFreeze, PassFreeze, PassOffset - member ClockSetting
 */
 
 // packege transport ~> ../internal/transport
@@ -277,9 +652,18 @@ describes middleware functions
  * func      - AuthZ         - take next('http.HandlerFunc') and check with help 'rulesForAuthZ'
 1. if login !exist -> call next
 2. othercase check password -> cal next (details inside file - middleware.go)
+ * func      - Timezone      - time zone of request from query param 'tz' or header 'X-Timezone', unknown zone -> 400
  ------------------------------------------------------------------------------------------------------
  - route.go
 describe application handlers
+ * func - TaskRetriveList - page of tasks (/api/tasks?search=word&limit=20&cursor=next_cursor&sort=title), wrong params -> 400
+ * func - TestNextDate   - next date of repeat as text (/api/nextdate), without 'now' - "now" of clock
+ * func - TaskDone       - done task with optional note in body (/api/task/done?id=1), wrong body -> 400
+ * func - TaskHistory    - completions of task (/api/task/history?id=1), unknown task -> 404
+ * func - TaskSkip       - skip current occurrence of recurring task (/api/task/skip?id=1)
+ * func - TaskAgenda     - occurrences of tasks by days (/api/agenda?from=20240101&to=20240107), without 'from' - today of clock
+ * func - ClockRetrieve, ClockChange, ClockReset - debug clock (/api/debug/clock), routes only with TODO_DEBUG_CLOCK=true
+ * func - DescribeRepeat - repeat as sentence in "en" or "ru" (/api/nextdate/describe?repeat=d+3&lang=ru)
  ------------------------------------------------------------------------------------------------------
  - handler.go
 rules for create route group
@@ -304,6 +688,7 @@ universal utilities
  * func      - EncodeJSON         - rules for create body to Response
  * func      - BeginningOfMonth   - work with time.Time
  * func      - ReduceTimeToDay    - time.Time
+ * func      - ReduceTimeToDayUTC - date of time.Time in its time zone as day in UTC
  * func      - HashData           - hashes some string string
  * func      - ReadCookie         - find cookie.Value by key
  * func      - CleanCookie        - end all cookies life
@@ -5,6 +5,7 @@ package app
 
 import (
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/config"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/lib/clock"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/services/usecase"
 )
 
@@ -15,13 +16,16 @@ type Sheduler struct {
 	usecase.LoginService
 
 	usecase.AuthService
+
+	usecase.ClockService
 }
 
 func NewSheduler(
 	cfg *config.Config,
 	taskStore usecase.MultiTask,
-	loginStore usecase.MultiLogin) (Sheduler, error) {
-	taskService, err := usecase.NewTaskService(cfg, taskStore)
+	loginStore usecase.MultiLogin,
+	clk clock.Clock) (Sheduler, error) {
+	taskService, err := usecase.NewTaskService(cfg, taskStore, clk)
 	if err != nil {
 		return Sheduler{}, err
 	}
@@ -31,5 +35,6 @@ func NewSheduler(
 		TaskService:  taskService,
 		LoginService: loginService,
 		AuthService:  authService,
+		ClockService: usecase.NewClockService(clk),
 	}, nil
 }
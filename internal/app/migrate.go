@@ -0,0 +1,58 @@
+// migrate - work with migrations of database without start of server (--migrate-only, --migrate-status)
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"text/tabwriter"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/config"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/database"
+)
+
+// Migrate - open database and write migrations to 'out'
+//
+// apply = true  -> apply pending migrations, write applied
+// apply = false -> write all migrations, pending are marked, tables are not changed
+// tasks in memory (config.DataBaseMemory) have no schema -> write it and return
+func Migrate(cfg *config.Config, apply bool, out io.Writer) error {
+	if cfg.InMemory() {
+		_, err := fmt.Fprintln(out, "tasks in memory have no migrations")
+		return err
+	}
+	db, err := database.OpenDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("app: sql.DB.Close error - %v", err)
+		}
+	}()
+	ctx := context.Background()
+	var list []database.Migration
+	if apply {
+		list, err = database.Migrate(ctx, db, cfg.DataBaseDriver)
+	} else {
+		list, err = database.Migrations(ctx, db, cfg.DataBaseDriver)
+	}
+	if err != nil {
+		return fmt.Errorf("app: migration error - %w", err)
+	}
+	if apply && len(list) == 0 {
+		_, err := fmt.Fprintln(out, "no pending migrations")
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tAPPLIED\tDESCRIPTION")
+	for _, m := range list {
+		appliedAt := m.AppliedAt
+		if appliedAt == "" {
+			appliedAt = "pending"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", m.Version, appliedAt, m.Description)
+	}
+	return w.Flush()
+}
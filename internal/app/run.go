@@ -2,39 +2,52 @@
 package app
 
 import (
+	"context"
+	"database/sql"
+	"fmt"
 	"log"
+	"time"
 
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/config"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/database"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/datauser"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/lib/clock"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/lib/jwtsign"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/lib/nextdate"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/lib/timezone"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/services/usecase"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/transport"
 )
 
+const ctxTimeSavePassword = 10 * time.Second
+
 // 1. set secretkey for jwt.Token  -> 'jwtsign.NewSecretKey'
-// 2. open database                -> 'database.InitDB'
-// 3. create Sheduler heart of app -> 'NewSheduler'
-// 4. create server and router     -> `transport.NewTransport`
-// 5. start (close inside)         -> `Start`
+// 2. load calendar of holidays    -> 'nextdate.NewHolidayCalendar'
+// 3. set default time zone        -> 'timezone.NewLocation'
+// 4. stores of task and login     -> 'openStores' memory or database by driver
+// 5. create Sheduler heart of app -> 'NewSheduler' with clock 'clock.New'
+// 6. create server and router     -> `transport.NewTransport`
+// 7. start (close inside)         -> `Start`
 func Run(cfg *config.Config) {
 	if err := jwtsign.NewSecretKey(cfg); err != nil {
 		log.Fatalf("app: error - %v", err)
 	}
 
-	db, err := database.InitDB(cfg)
+	if err := nextdate.NewHolidayCalendar(cfg); err != nil {
+		log.Fatalf("app: error - %v", err)
+	}
+
+	if err := timezone.NewLocation(cfg); err != nil {
+		log.Fatalf("app: error - %v", err)
+	}
+
+	taskStore, loginStore, closeStores, err := openStores(cfg)
 	if err != nil {
 		log.Fatalf("app: error - %v", err)
 	}
-	defer func() {
-		if err := db.Close(); err != nil {
-			log.Printf("app: sql.DB.Close error - %v", err)
-		}
-	}()
+	defer closeStores()
 
-	sheduler, err := NewSheduler(
-		cfg,
-		database.NewSource(db),
-		datauser.NewUserData(cfg))
+	sheduler, err := NewSheduler(cfg, taskStore, loginStore, clock.New(cfg))
 	if err != nil {
 		log.Fatalf("app: error - %v", err)
 	}
@@ -45,3 +58,47 @@ func Run(cfg *config.Config) {
 		log.Printf("app: error - %v", err)
 	}
 }
+
+// openStores - stores of application and function for close them
+//
+// config.DataBaseMemory -> 'database.MemorySource', tasks are lost on exit
+// othercase             -> open database with migrations 'database.InitDB' and 'stores'
+func openStores(cfg *config.Config) (usecase.MultiTask, usecase.MultiLogin, func(), error) {
+	if cfg.InMemory() {
+		log.Print("app: tasks are stored in memory and will be lost on exit")
+		return database.NewMemorySource(), datauser.NewUserData(cfg), func() {}, nil
+	}
+	db, err := database.InitDB(cfg)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	closeDB := func() {
+		if err := db.Close(); err != nil {
+			log.Printf("app: sql.DB.Close error - %v", err)
+		}
+	}
+	taskStore, loginStore, err := stores(cfg, db)
+	if err != nil {
+		closeDB()
+		return nil, nil, nil, err
+	}
+	return taskStore, loginStore, closeDB, nil
+}
+
+// stores - store of tasks and store of password by driver of database
+//
+// SQLite     -> 'database.Source' and password from config 'datauser.UserData'
+// PostgreSQL -> 'database.PostgresSource' and password in table 'login' shared by instances,
+// password from config only seeds table without password see 'database.LoginSource.SeedPassword'
+func stores(cfg *config.Config, db *sql.DB) (usecase.MultiTask, usecase.MultiLogin, error) {
+	if cfg.DataBaseDriver != config.DriverPostgres {
+		return database.NewSource(db), datauser.NewUserData(cfg), nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeSavePassword)
+	defer cancel()
+	loginStore := database.NewLoginSource(db)
+	if err := loginStore.SeedPassword(ctx, cfg.UserPassword); err != nil {
+		return nil, nil, fmt.Errorf("app: seed password error - %w", err)
+	}
+	return database.NewPostgresSource(db), loginStore, nil
+}
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/config"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/database"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/database/dbtest"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+)
+
+// instances with one PostgreSQL - password of first instance is kept
+func TestStores_Postgres(t *testing.T) {
+	asserts := assert.New(t)
+	requires := require.New(t)
+
+	first := &config.Config{
+		DataBaseDriver:         config.DriverPostgres,
+		DataBaseDataSourceName: dbtest.Postgres(t),
+		UserPassword:           "qwert12345",
+	}
+	db, err := database.InitDB(first)
+	requires.NoError(err, "run_test: InitDB error")
+	defer db.Close()
+
+	_, _, err = stores(first, db)
+	requires.NoError(err)
+
+	second := *first
+	second.UserPassword = ""
+	_, loginStore, err := stores(&second, db)
+	requires.NoError(err)
+
+	ctx := context.Background()
+	asserts.True(loginStore.PasswordExist(ctx), "empty password doesn't turn off authorization")
+	asserts.True(loginStore.ValidLogin(ctx, model.LoginModel{Password: "qwert12345"}), "password of first instance")
+}
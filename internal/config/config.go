@@ -19,11 +19,28 @@ var (
 
 	// ErrConfigPortNoNumeric - if the expected field does not contain only positive numbers
 	ErrConfigPortNoNumeric = errors.New(" no numeric")
+
+	// ErrConfigUnknownDriver - driver of database is not 'DriverSQLite' or 'DriverPostgres'
+	ErrConfigUnknownDriver = errors.New("unknown driver")
+)
+
+// drivers of database, empty - DriverSQLite
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
 )
 
+// DataBaseMemory - value of 'TODO_DBFILE' for tasks in memory of process without database
+const DataBaseMemory = ":memory:"
+
 type Config struct {
+	// file of database for DriverSQLite, connection string for DriverPostgres,
+	// 'DataBaseMemory' - tasks in memory of process for any driver
 	DataBaseDataSourceName string `mapstructure:"TODO_DBFILE"`
 
+	// driver of database: "sqlite" (may be empty) or "postgres" -> (/internal/database/database.go)
+	DataBaseDriver string `mapstructure:"TODO_DBDRIVER"`
+
 	ServerPort string `mapstructure:"TODO_PORT"`
 
 	// data for taskServcie see (/internal/services/usecase/taslcase.go)
@@ -38,6 +55,15 @@ type Config struct {
 	// path for file executeble in application
 	PathFilesWeb string `mapstructure:"PATH_DIR_WEB"`
 
+	// calendar of holidays for working day rules (may be empty) -> (/internal/lib/nextdate/workday.go)
+	HolidaysFile string `mapstructure:"TODO_HOLIDAYS"`
+
+	// default time zone of "today" for tasks (may be empty - zone of server) -> (/internal/lib/timezone/timezone.go)
+	Timezone string `mapstructure:"TODO_TIMEZONE"`
+
+	// allow to freeze or move "now" of server by /api/debug/clock -> (/internal/lib/clock/clock.go)
+	DebugClock bool `mapstructure:"TODO_DEBUG_CLOCK"`
+
 	// options - contain data about the file being analyzed (parse) see (internal/config/options.go)
 	options
 }
@@ -78,10 +104,14 @@ func (cfg *Config) loadConfig() error {
 var envNames = []string{
 	"TODO_PORT",
 	"TODO_DBFILE",
+	"TODO_DBDRIVER",
 	"ALGORITHM_TASK_DATE",
 	"TODO_PASSWORD",
 	"TODO_SECRET_KEY",
 	"PATH_DIR_WEB",
+	"TODO_HOLIDAYS",
+	"TODO_TIMEZONE",
+	"TODO_DEBUG_CLOCK",
 }
 
 // setConfig - set extension of parse file from 'options'
@@ -102,6 +132,11 @@ func (cfg *Config) setConfig(in io.Reader) error {
 	return viper.Unmarshal(cfg)
 }
 
+// InMemory - tasks are stored in memory of process, database is not opened
+func (cfg *Config) InMemory() bool {
+	return cfg.DataBaseDataSourceName == DataBaseMemory
+}
+
 // ValidConfig - Checking the config for validity
 //
 // create common.Message - for create full infor about possible erros -> see (pkg/common/common.go)
@@ -123,6 +158,9 @@ func (cfg *Config) validDataBase(msgErr common.Message) {
 	if cfg.DataBaseDataSourceName == "" {
 		msgErr["source"] = ErrConfigEmpty
 	}
+	if driver := cfg.DataBaseDriver; driver != "" && driver != DriverSQLite && driver != DriverPostgres {
+		msgErr["driver"] = ErrConfigUnknownDriver
+	}
 }
 
 func (cfg *Config) validServe(msgErr common.Message) {
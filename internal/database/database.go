@@ -8,6 +8,7 @@ import (
 	"log"
 	"os"
 
+	_ "github.com/lib/pq"
 	_ "modernc.org/sqlite"
 
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/config"
@@ -22,44 +23,55 @@ func NewSource(db *sql.DB) Source {
 	return Source{store: dbTX{DB: db}}
 }
 
-// InitDB - create a database connection
+// OpenDB - create a database connection without migrations
 //
-// 1. get location of file.db
-// 2. check file, if not exists -> create database file and install = true
-// 3. sql.Open
-// 4. if install = true -> create table(s)
-func InitDB(cfg *config.Config) (*sql.DB, error) {
-	install := false
-	if _, err := os.Stat(cfg.DataBaseDataSourceName); os.IsNotExist(err) {
-		if err := common.CreatePathWithFile(cfg.DataBaseDataSourceName); err != nil {
-			return nil, fmt.Errorf("database: file.db create error - %w", err)
+// 1. SQLite: get location of file.db, if not exists -> create database file
+// PostgreSQL: 'cfg.DataBaseDataSourceName' is connection string, database must exist
+// 2. sql.Open by driver of config see (dialect.go) and Ping
+func OpenDB(cfg *config.Config) (*sql.DB, error) {
+	d := dialectOf(cfg.DataBaseDriver)
+	if d.driver == config.DriverSQLite {
+		if _, err := os.Stat(cfg.DataBaseDataSourceName); os.IsNotExist(err) {
+			if err := common.CreatePathWithFile(cfg.DataBaseDataSourceName); err != nil {
+				return nil, fmt.Errorf("database: file.db create error - %w", err)
+			}
 		}
-		install = true
 	}
-	db, err := sql.Open("sqlite", cfg.DataBaseDataSourceName)
+	db, err := sql.Open(d.driver, cfg.DataBaseDataSourceName)
 	if err != nil {
 		return nil, fmt.Errorf("database: sql.Open error - %w", err)
 	}
 	if err := db.Ping(); err != nil {
-		func() {
-			if err := db.Close(); err != nil {
-				log.Printf("database: sql.DB.Close error - %v", err)
-			}
-		}()
+		closeDB(db)
 		return nil, fmt.Errorf("database: error - %v", err)
 	}
-	if install {
-		ctx, cancel := context.WithTimeout(context.Background(), ctxTimeTableCreate)
-		defer cancel()
-		_, err := db.ExecContext(ctx, schema)
-		if err != nil {
-			func() {
-				if err := db.Close(); err != nil {
-					log.Printf("database: sql.DB.Close error - %v", err)
-				}
-			}()
-			return nil, fmt.Errorf("database: schema init error - %w", err)
-		}
+	return db, nil
+}
+
+// InitDB - create a database connection and apply pending migrations
+//
+// 1. open database see 'OpenDB'
+// 2. apply pending migrations of schema see (migration.go), new database file get all of them
+func InitDB(cfg *config.Config) (*sql.DB, error) {
+	db, err := OpenDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeTableCreate)
+	defer cancel()
+	done, err := Migrate(ctx, db, cfg.DataBaseDriver)
+	if err != nil {
+		closeDB(db)
+		return nil, fmt.Errorf("database: schema migration error - %w", err)
+	}
+	for _, m := range done {
+		log.Printf("database: migration %d applied - %s", m.Version, m.Description)
 	}
 	return db, nil
 }
+
+func closeDB(db *sql.DB) {
+	if err := db.Close(); err != nil {
+		log.Printf("database: sql.DB.Close error - %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"log"
@@ -15,6 +16,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/config"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/database/dbtest"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/services/entity"
 )
@@ -25,6 +27,8 @@ func newTask() model.TaskModel {
 		Title:   "first",
 		Comment: "ololo",
 		Repeat:  "d 1",
+
+		Algorithm: "nextdate",
 	}
 }
 
@@ -39,9 +43,19 @@ func updateTask() model.TaskModel {
 
 var LastID = uint(0)
 
+// taskStore - queries of task, implemented by 'Source', 'PostgresSource' and 'MemorySource'
+type taskStore interface {
+	model.TaskCreate
+	model.TaskRead
+	model.TaskUpdate
+	model.TaskDelete
+	model.TaskComplete
+	model.TaskHistory
+}
+
 var dataForQuery = []struct {
 	descriptiom string
-	init        func(ctx context.Context, s Source, data any) (any, error)
+	init        func(ctx context.Context, s taskStore, data any) (any, error)
 	ctxTimeOut  time.Duration
 	data        any
 	expectedRes any
@@ -50,7 +64,7 @@ var dataForQuery = []struct {
 }{
 	{
 		descriptiom: `update task - wrong ID`,
-		init: func(ctx context.Context, s Source, data any) (any, error) {
+		init: func(ctx context.Context, s taskStore, data any) (any, error) {
 			return nil, s.NewDataTask(ctx, data)
 		},
 		ctxTimeOut:  100 * time.Second,
@@ -61,7 +75,7 @@ var dataForQuery = []struct {
 	},
 	{
 		descriptiom: `new task - valid`,
-		init: func(ctx context.Context, s Source, data any) (any, error) {
+		init: func(ctx context.Context, s taskStore, data any) (any, error) {
 			return s.SaveOneTask(ctx, data)
 		},
 		ctxTimeOut:  100 * time.Second,
@@ -72,7 +86,7 @@ var dataForQuery = []struct {
 	},
 	{
 		descriptiom: `find task - valid`,
-		init: func(ctx context.Context, s Source, data any) (any, error) {
+		init: func(ctx context.Context, s taskStore, data any) (any, error) {
 			return s.FindOneTask(ctx, data)
 		},
 		ctxTimeOut:  100 * time.Second,
@@ -83,7 +97,7 @@ var dataForQuery = []struct {
 	},
 	{
 		descriptiom: `wrong find task`,
-		init: func(ctx context.Context, s Source, data any) (any, error) {
+		init: func(ctx context.Context, s taskStore, data any) (any, error) {
 			return s.FindOneTask(ctx, data)
 		},
 		ctxTimeOut:  100 * time.Second,
@@ -94,7 +108,7 @@ var dataForQuery = []struct {
 	},
 	{
 		descriptiom: `update task - valid`,
-		init: func(ctx context.Context, s Source, data any) (any, error) {
+		init: func(ctx context.Context, s taskStore, data any) (any, error) {
 			return nil, s.NewDataTask(ctx, data)
 		},
 		ctxTimeOut:  100 * time.Second,
@@ -105,7 +119,7 @@ var dataForQuery = []struct {
 	},
 	{
 		descriptiom: `task list - valid by date`,
-		init: func(ctx context.Context, s Source, data any) (any, error) {
+		init: func(ctx context.Context, s taskStore, data any) (any, error) {
 			return s.FindTaskList(ctx, data)
 		},
 		ctxTimeOut:  100 * time.Second,
@@ -116,19 +130,52 @@ var dataForQuery = []struct {
 	},
 	{
 		descriptiom: `task list - valid by word`,
-		init: func(ctx context.Context, s Source, data any) (any, error) {
+		init: func(ctx context.Context, s taskStore, data any) (any, error) {
 			return s.FindTaskList(ctx, data)
 		},
 		ctxTimeOut: 100 * time.Second,
-		// first, not it's second -> first, no(t i)t's second
-		data:        entity.NewTaskProperty("t i", 123),
+		// first, not it's second -> first, (not it)'s second: prefix of words (SQLite) or part of text (LIKE)
+		data:        entity.NewTaskProperty("not it", 123),
 		expectedRes: []model.TaskModel{updateTask()},
 		err:         nil,
 		msg:         `find array task, no error`,
 	},
+	{
+		descriptiom: `task list - valid by word in other case`,
+		init: func(ctx context.Context, s taskStore, data any) (any, error) {
+			return s.FindTaskList(ctx, data)
+		},
+		ctxTimeOut:  100 * time.Second,
+		data:        entity.NewTaskProperty("OLOLO", 123),
+		expectedRes: []model.TaskModel{updateTask()},
+		err:         nil,
+		msg:         `find array task by word case-insensitive, no error`,
+	},
+	{
+		descriptiom: `recurring task list - valid before date`,
+		init: func(ctx context.Context, s taskStore, data any) (any, error) {
+			return s.FindRepeatTaskList(ctx, data)
+		},
+		ctxTimeOut:  100 * time.Second,
+		data:        entity.NewTaskProperty("03.01.3000", 123),
+		expectedRes: []model.TaskModel{updateTask()},
+		err:         nil,
+		msg:         `find array of recurring task, no error`,
+	},
+	{
+		descriptiom: `recurring task list - empty on date of task`,
+		init: func(ctx context.Context, s taskStore, data any) (any, error) {
+			return s.FindRepeatTaskList(ctx, data)
+		},
+		ctxTimeOut:  100 * time.Second,
+		data:        entity.NewTaskProperty("02.01.3000", 123),
+		expectedRes: []model.TaskModel(nil),
+		err:         nil,
+		msg:         `find empty array of recurring task, no error`,
+	},
 	{
 		descriptiom: `task delete - valid`,
-		init: func(ctx context.Context, s Source, data any) (any, error) {
+		init: func(ctx context.Context, s taskStore, data any) (any, error) {
 			return nil, s.ExpirationTask(ctx, data)
 		},
 		ctxTimeOut:  100 * time.Second,
@@ -139,7 +186,7 @@ var dataForQuery = []struct {
 	},
 	{
 		descriptiom: `task delete - invalid ID not exist`,
-		init: func(ctx context.Context, s Source, data any) (any, error) {
+		init: func(ctx context.Context, s taskStore, data any) (any, error) {
 			return nil, s.ExpirationTask(ctx, data)
 		},
 		ctxTimeOut:  100 * time.Second,
@@ -164,11 +211,39 @@ func TestDataBase(t *testing.T) {
 		err := db.Close()
 		asserts.NoError(err, "database_test: DB Close error")
 	}()
-	ctx := context.Background()
-	_, err = db.ExecContext(ctx, `DELETE FROM scheduler;`)
+	_, err = db.Exec(`DELETE FROM scheduler;`)
 	requires.NoError(err, "delete data from table error")
+	testQueries(t, NewSource(db))
+}
+
+func TestDataBase_Postgres(t *testing.T) {
+	db := openPostgres(t)
+	defer db.Close()
+
+	_, err := Migrate(context.Background(), db, config.DriverPostgres)
+	require.NoError(t, err, "database_test: migration error")
+	testQueries(t, NewPostgresSource(db))
+}
+
+func TestDataBase_Memory(t *testing.T) {
+	testQueries(t, NewMemorySource())
+}
+
+// openPostgres - connection to own empty schema of test in database from 'dbtest.PostgresEnv',
+// empty variable or database is not available -> test is skipped, schema is dropped after test
+func openPostgres(t *testing.T) *sql.DB {
+	db, err := OpenDB(&config.Config{DataBaseDataSourceName: dbtest.Postgres(t), DataBaseDriver: config.DriverPostgres})
+	require.NoError(t, err, "database_test: OpenDB error")
+	return db
+}
 
-	source := NewSource(db)
+// testQueries - run 'dataForQuery' by 'source' without tasks
+func testQueries(t *testing.T, source taskStore) {
+	asserts := assert.New(t)
+	requires := require.New(t)
+
+	ctx := context.Background()
+	LastID = 0
 
 	for i, test := range dataForQuery {
 		log.Printf("\t%d %s", i+1, test.descriptiom)
@@ -226,3 +301,385 @@ func TestDataBase(t *testing.T) {
 		}
 	}
 }
+
+func TestDataBase_Pages(t *testing.T) {
+	db, err := InitDB(&config.Config{DataBaseDataSourceName: filepath.Join(t.TempDir(), "pages.db")})
+	require.NoError(t, err, "database_test: InitDB error")
+	defer db.Close()
+
+	testPages(t, NewSource(db))
+}
+
+func TestDataBase_Pages_Postgres(t *testing.T) {
+	db := openPostgres(t)
+	defer db.Close()
+
+	_, err := Migrate(context.Background(), db, config.DriverPostgres)
+	require.NoError(t, err, "database_test: migration error")
+	testPages(t, NewPostgresSource(db))
+}
+
+func TestDataBase_Pages_Memory(t *testing.T) {
+	testPages(t, NewMemorySource())
+}
+
+// testPages - walk through list by cursor of last task: all tasks once in order of date, time, id
+// task saved during walk before cursor doesn't shift pages, order by relevance has only first page
+func testPages(t *testing.T, source taskStore) {
+	asserts := assert.New(t)
+	requires := require.New(t)
+
+	ctx := context.Background()
+	var expected []string
+	for _, task := range []model.TaskModel{
+		{Date: "30000102", Time: "10:00", Title: "page 5"},
+		{Date: "30000101", Title: "page 1"},
+		{Date: "30000102", Title: "page 3"},
+		{Date: "30000101", Title: "page 2"},
+		{Date: "30000102", Time: "10:00", Title: "page 6"},
+		{Date: "30000102", Time: "09:00", Title: "page 4"},
+		{Date: "30000103", Title: "page 7"},
+	} {
+		_, err := source.SaveOneTask(ctx, task)
+		requires.NoError(err)
+		expected = append(expected, task.Title)
+	}
+	sort.Strings(expected)
+
+	byDate := entity.NewTaskFilter(time.Time{}, time.Time{}, time.Time{}, "", "", entity.SortDate, false)
+	walk := func(search string, filter *entity.TaskFilter) []string {
+		var titles []string
+		var cursor *entity.TaskCursor
+		for page := 0; page < 10; page++ {
+			property := entity.NewTaskProperty(search, 3).After(cursor).Filter(filter)
+			tasks, err := source.FindTaskList(ctx, property)
+			requires.NoError(err, search)
+			for _, task := range tasks {
+				titles = append(titles, task.Title)
+			}
+			if len(tasks) < 3 {
+				return titles
+			}
+			cursor = entity.NewTaskCursor(tasks[len(tasks)-1])
+			if page == 0 && search == "" {
+				// before cursor of next page
+				_, err := source.SaveOneTask(ctx, model.TaskModel{Date: "30000101", Title: "late"})
+				requires.NoError(err)
+			}
+		}
+		requires.FailNow("too many pages " + search)
+		return nil
+	}
+
+	asserts.Equal(expected, walk("", nil), "all tasks by pages")
+	asserts.Equal([]string{"page 3", "page 4", "page 5", "page 6"}, walk("02.01.3000", nil), "tasks of date by pages")
+	asserts.Equal(expected, walk("page", byDate), "search by word by pages of date")
+	asserts.Equal(expected, walk("pag", byDate), "search of prefix of word by pages of date")
+
+	first, err := source.FindTaskList(ctx, entity.NewTaskProperty("page", 3))
+	requires.NoError(err)
+	requires.Len(first, 3)
+	again, err := source.FindTaskList(ctx, entity.NewTaskProperty("page", 3).After(entity.NewTaskCursor(first[2])))
+	requires.NoError(err)
+	asserts.Equal(first, again, "order by relevance - cursor is ignored")
+}
+
+func TestDataBase_Filters(t *testing.T) {
+	db, err := InitDB(&config.Config{DataBaseDataSourceName: filepath.Join(t.TempDir(), "filters.db")})
+	require.NoError(t, err, "database_test: InitDB error")
+	defer db.Close()
+
+	testFilters(t, NewSource(db))
+}
+
+func TestDataBase_Filters_Postgres(t *testing.T) {
+	db := openPostgres(t)
+	defer db.Close()
+
+	_, err := Migrate(context.Background(), db, config.DriverPostgres)
+	require.NoError(t, err, "database_test: migration error")
+	testFilters(t, NewPostgresSource(db))
+}
+
+func TestDataBase_Filters_Memory(t *testing.T) {
+	testFilters(t, NewMemorySource())
+}
+
+// testFilters - walk through list by pages of two tasks with filters and keys of order
+// pages of every key are found by cursor, tasks with same title are ordered by id
+// RRULE fits flag of structured search by FREQ in any case
+func testFilters(t *testing.T, source taskStore) {
+	asserts := assert.New(t)
+	requires := require.New(t)
+
+	ctx := context.Background()
+	ids := make([]uint, 0, 5)
+	for _, task := range []model.TaskModel{
+		{Date: "30000101", Title: "b milk", Comment: "shop"},
+		{Date: "30000102", Title: "a bread", Comment: "milk", Repeat: "d 1"},
+		{Date: "30000103", Title: "c tea", Repeat: "y"},
+		{Date: "30000104", Title: "b milk"},
+		{Date: "30000105", Title: "d water", Comment: "milk"},
+	} {
+		id, err := source.SaveOneTask(ctx, task)
+		requires.NoError(err)
+		ids = append(ids, id)
+	}
+	day := func(d int) time.Time {
+		return time.Date(3000, 1, d, 0, 0, 0, 0, time.UTC)
+	}
+	none := time.Time{}
+
+	walk := func(search string, filter *entity.TaskFilter) []uint {
+		var found []uint
+		var cursor *entity.TaskCursor
+		for page := 0; page < 10; page++ {
+			query, err := entity.ParseTaskQuery(search)
+			requires.NoError(err, search)
+			property := entity.NewTaskProperty(search, 2).After(cursor).Filter(filter).Query(query)
+			tasks, err := source.FindTaskList(ctx, property)
+			requires.NoError(err, search)
+			for _, task := range tasks {
+				found = append(found, task.ID)
+			}
+			if len(tasks) < 2 {
+				return found
+			}
+			cursor = entity.NewTaskCursor(tasks[len(tasks)-1])
+		}
+		requires.FailNow("too many pages " + search)
+		return nil
+	}
+
+	for _, test := range []struct {
+		search   string
+		filter   *entity.TaskFilter
+		expected []int
+		msg      string
+	}{
+		{"", entity.NewTaskFilter(day(2), day(4), none, "", "", "", false), []int{1, 2, 3}, "window of dates"},
+		{"", entity.NewTaskFilter(none, none, none, entity.KindRecurring, "", "", false), []int{1, 2}, "recurring"},
+		{"", entity.NewTaskFilter(none, none, none, entity.KindOnce, "", "", false), []int{0, 3, 4}, "once"},
+		{"", entity.NewTaskFilter(none, none, day(3), "", "", "", false), []int{0, 1}, "overdue"},
+		{"milk", entity.NewTaskFilter(none, none, none, "", entity.FieldTitle, entity.SortDate, false), []int{0, 3}, "title only"},
+		{"milk", entity.NewTaskFilter(none, none, none, "", entity.FieldComment, entity.SortDate, false), []int{1, 4}, "comment only"},
+		{"milk", entity.NewTaskFilter(none, none, none, "", "", entity.SortDate, true), []int{4, 3, 1, 0}, "search by date desc"},
+		{"mil", entity.NewTaskFilter(none, none, none, "", entity.FieldTitle, entity.SortID, true), []int{3, 0}, "prefix of word in title by id desc"},
+		{"", entity.NewTaskFilter(none, none, none, "", "", entity.SortTitle, false), []int{1, 0, 3, 2, 4}, "by title"},
+		{"", entity.NewTaskFilter(none, none, none, "", "", entity.SortTitle, true), []int{4, 2, 3, 0, 1}, "by title desc"},
+		{"", entity.NewTaskFilter(none, none, none, "", "", entity.SortID, true), []int{4, 3, 2, 1, 0}, "by id desc"},
+		{"", entity.NewTaskFilter(none, day(4), none, entity.KindOnce, "", entity.SortTitle, true), []int{3, 0}, "combined"},
+		{"title:milk -shop", nil, []int{3}, "structured search"},
+		{"MILK before:3000-01-05 -title:milk", nil, []int{1}, "word and date of structured search"},
+		{"repeat:d", nil, []int{1}, "flag of rule"},
+		{"repeat:any", nil, []int{1, 2}, "recurring by structured search"},
+		{"repeat:none ilk", entity.NewTaskFilter(none, none, none, "", "", entity.SortID, true), []int{4, 3, 0}, "once by structured search"},
+		{"after:3000-01-02 before:30000105 -comment:milk", nil, []int{2, 3}, "window of structured search"},
+	} {
+		expected := make([]uint, 0, len(test.expected))
+		for _, i := range test.expected {
+			expected = append(expected, ids[i])
+		}
+		asserts.Equal(expected, walk(test.search, test.filter), test.msg)
+	}
+
+	relevance, err := source.FindTaskList(ctx, entity.NewTaskProperty("milk", 10).
+		Filter(entity.NewTaskFilter(none, none, none, "", entity.FieldComment, "", false)))
+	requires.NoError(err)
+	requires.Len(relevance, 2, "comment only by relevance")
+	asserts.ElementsMatch([]uint{ids[1], ids[4]}, []uint{relevance[0].ID, relevance[1].ID})
+
+	rrule, err := source.SaveOneTask(ctx, model.TaskModel{Date: "30000106", Title: "e juice", Repeat: "rrule:interval=2;freq=weekly"})
+	requires.NoError(err)
+	asserts.Equal([]uint{rrule}, walk("repeat:w", nil), "RRULE by FREQ of flag")
+	asserts.Equal([]uint{ids[1]}, walk("repeat:d", nil), "RRULE with other FREQ")
+	asserts.Equal([]uint{ids[1], ids[2], rrule}, walk("repeat:any", nil), "RRULE is recurring")
+}
+
+func TestDataBase_History(t *testing.T) {
+	db, err := InitDB(&config.Config{DataBaseDataSourceName: filepath.Join(t.TempDir(), "history.db")})
+	require.NoError(t, err, "database_test: InitDB error")
+	defer db.Close()
+
+	testHistory(t, NewSource(db))
+}
+
+func TestDataBase_History_Postgres(t *testing.T) {
+	db := openPostgres(t)
+	defer db.Close()
+
+	_, err := Migrate(context.Background(), db, config.DriverPostgres)
+	require.NoError(t, err, "database_test: migration error")
+	testHistory(t, NewPostgresSource(db))
+}
+
+func TestDataBase_History_Memory(t *testing.T) {
+	testHistory(t, NewMemorySource())
+}
+
+// testHistory - completion is saved with new data of task or with delete of task
+// task not exist -> ErrDataBaseNotFound and completion is not saved
+func testHistory(t *testing.T, source taskStore) {
+	asserts := assert.New(t)
+	requires := require.New(t)
+
+	ctx := context.Background()
+	task := model.TaskModel{Date: "30000101", Time: "09:00", Title: "run", Repeat: "d 1 count 2"}
+	id, err := source.SaveOneTask(ctx, task)
+	requires.NoError(err)
+	task.ID = id
+
+	first := model.CompletionModel{TaskID: id, Date: "30000101", Time: "09:00", DoneAt: "3000-01-01T10:00:00Z", Note: "5 km"}
+	next := task
+	next.Date, next.Repeat = "30000102", "d 1 count 1"
+	requires.NoError(source.CompleteTask(ctx, entity.NewTaskDone(first, &next)))
+
+	stored, err := source.FindOneTask(ctx, id)
+	requires.NoError(err)
+	asserts.Equal(next, stored, "new data of task")
+
+	last := model.CompletionModel{TaskID: id, Date: "30000102", Time: "09:00", DoneAt: "3000-01-02T09:05:00Z"}
+	requires.NoError(source.CompleteTask(ctx, entity.NewTaskDone(last, nil)))
+	_, err = source.FindOneTask(ctx, id)
+	asserts.ErrorIs(err, ErrDataBaseNotFound, "task is deleted")
+
+	unknown := model.CompletionModel{TaskID: id, Date: "30000103", DoneAt: "3000-01-03T09:00:00Z"}
+	asserts.ErrorIs(source.CompleteTask(ctx, entity.NewTaskDone(unknown, nil)), ErrDataBaseNotFound)
+
+	completions, err := source.FindCompletionList(ctx, id)
+	requires.NoError(err)
+	requires.Len(completions, 2, "history of deleted task without completion of unknown task")
+	for i, expected := range []model.CompletionModel{first, last} {
+		asserts.NotZero(completions[i].ID)
+		completions[i].ID = 0
+		asserts.Equal(expected, completions[i])
+	}
+
+	completions, err = source.FindCompletionList(ctx, id+1)
+	requires.NoError(err)
+	asserts.Empty(completions)
+}
+
+func TestInitDB_AddColumns(t *testing.T) {
+	requires := require.New(t)
+
+	// table of first version without new columns
+	dsn := filepath.Join(t.TempDir(), "old.db")
+	old, err := sql.Open("sqlite", dsn)
+	requires.NoError(err, "database_test: sql.Open error")
+	_, err = old.Exec(`
+CREATE TABLE scheduler
+(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    date VARCHAR(8) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    comment VARCHAR(2048) NULL,
+    repeat VARCHAR(128) NOT NULL CHECK (LENGTH(repeat) <= 128)
+);
+INSERT INTO scheduler (date, title, comment, repeat) VALUES ('20240101', 'old', '', 'd 1');`)
+	requires.NoError(err, "database_test: old schema error")
+	requires.NoError(old.Close())
+
+	db, err := InitDB(&config.Config{DataBaseDataSourceName: dsn})
+	requires.NoError(err, "database_test: InitDB error")
+	defer db.Close()
+
+	task, err := NewSource(db).FindOneTask(context.Background(), uint(1))
+	requires.NoError(err, "database_test: find old task error")
+	requires.Equal("old", task.Title)
+	requires.Empty(task.Algorithm, "old task without algorithm")
+
+	count := 0
+	err = db.QueryRow(`SELECT COUNT(*) FROM scheduler_fts WHERE scheduler_fts MATCH $1;`, matchQuery("OLD", "")).Scan(&count)
+	requires.NoError(err, "database_test: full-text search error")
+	requires.Equal(1, count, "old task is in index of full-text search")
+}
+
+func TestMigrate(t *testing.T) {
+	db, err := OpenDB(&config.Config{DataBaseDataSourceName: filepath.Join(t.TempDir(), "new.db")})
+	require.NoError(t, err, "database_test: OpenDB error")
+	defer db.Close()
+
+	testMigrate(t, db, config.DriverSQLite)
+}
+
+func TestMigrate_Postgres(t *testing.T) {
+	db := openPostgres(t)
+	defer db.Close()
+
+	testMigrate(t, db, config.DriverPostgres)
+}
+
+// testMigrate - all migrations of 'driver' on database without tables, then nothing to apply
+func testMigrate(t *testing.T, db *sql.DB, driver string) {
+	asserts := assert.New(t)
+	requires := require.New(t)
+
+	ctx := context.Background()
+	migrations := dialectOf(driver).migrations
+
+	list, err := Migrations(ctx, db, driver)
+	requires.NoError(err)
+	requires.Len(list, len(migrations))
+	for i, m := range list {
+		asserts.Equal(i+1, m.Version, "versions in order")
+		asserts.Empty(m.AppliedAt, "pending migration")
+	}
+
+	done, err := Migrate(ctx, db, driver)
+	requires.NoError(err)
+	asserts.Len(done, len(migrations), "all migrations of new database")
+
+	done, err = Migrate(ctx, db, driver)
+	requires.NoError(err)
+	asserts.Empty(done, "nothing to apply")
+
+	list, err = Migrations(ctx, db, driver)
+	requires.NoError(err)
+	for _, m := range list {
+		asserts.NotEmpty(m.AppliedAt, "applied migration")
+	}
+
+	// table of last version is ready
+	_, err = NewSource(db).SaveOneTask(ctx, newTask())
+	asserts.NoError(err)
+}
+
+func TestLoginSource(t *testing.T) {
+	db, err := InitDB(&config.Config{DataBaseDataSourceName: filepath.Join(t.TempDir(), "login.db")})
+	require.NoError(t, err, "database_test: InitDB error")
+	defer db.Close()
+
+	testLoginSource(t, NewLoginSource(db))
+}
+
+func TestLoginSource_Postgres(t *testing.T) {
+	db := openPostgres(t)
+	defer db.Close()
+
+	_, err := Migrate(context.Background(), db, config.DriverPostgres)
+	require.NoError(t, err, "database_test: migration error")
+	testLoginSource(t, NewLoginSource(db))
+}
+
+// testLoginSource - password is seeded once, other password or empty password don't change it
+func testLoginSource(t *testing.T, source LoginSource) {
+	asserts := assert.New(t)
+	requires := require.New(t)
+
+	ctx := context.Background()
+
+	requires.NoError(source.SeedPassword(ctx, ""))
+	asserts.False(source.PasswordExist(ctx), "without password")
+
+	requires.NoError(source.SeedPassword(ctx, "qwert12345"))
+	asserts.True(source.PasswordExist(ctx))
+	asserts.True(source.ValidLogin(ctx, model.LoginModel{Password: "qwert12345"}))
+	asserts.False(source.ValidLogin(ctx, model.LoginModel{Password: "123456789"}))
+
+	requires.NoError(source.SeedPassword(ctx, "123456789"), "other password")
+	asserts.True(source.ValidLogin(ctx, model.LoginModel{Password: "qwert12345"}), "password is not changed")
+
+	requires.NoError(source.SeedPassword(ctx, ""), "empty password")
+	asserts.True(source.PasswordExist(ctx), "password is not removed")
+}
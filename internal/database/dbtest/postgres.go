@@ -0,0 +1,65 @@
+// dbtest - test database of PostgreSQL for tests of stores (database, app)
+//
+// connection string from 'PostgresEnv', every test works in own schema "todo_test_<random>",
+// schema is dropped after test, tables of database from 'PostgresEnv' are not touched
+package dbtest
+
+import (
+	"database/sql"
+	"fmt"
+	"math/rand/v2"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	_ "github.com/lib/pq"
+)
+
+// PostgresEnv - connection string of test database of PostgreSQL
+const PostgresEnv = "TODO_TEST_POSTGRES"
+
+// Postgres - connection string to new empty schema of test
+// empty variable or database is not available -> test is skipped
+func Postgres(t *testing.T) string {
+	t.Helper()
+	dsn := os.Getenv(PostgresEnv)
+	if dsn == "" {
+		t.Skipf("dbtest: %s is empty - test of PostgreSQL is skipped", PostgresEnv)
+	}
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Skipf("dbtest: PostgreSQL is not available - %v", err)
+	}
+	if err := admin.Ping(); err != nil {
+		admin.Close()
+		t.Skipf("dbtest: PostgreSQL is not available - %v", err)
+	}
+	schema := fmt.Sprintf("todo_test_%x", rand.Uint64())
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema + `;`); err != nil {
+		admin.Close()
+		t.Fatalf("dbtest: create schema error - %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE;`); err != nil {
+			t.Errorf("dbtest: drop schema error - %v", err)
+		}
+		admin.Close()
+	})
+	return withSearchPath(dsn, schema)
+}
+
+// withSearchPath - 'dsn' with run-time parameter "search_path" (URL or key=value form of lib/pq)
+func withSearchPath(dsn, schema string) string {
+	if !strings.HasPrefix(dsn, "postgres://") && !strings.HasPrefix(dsn, "postgresql://") {
+		return dsn + " search_path=" + schema
+	}
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return dsn
+	}
+	query := u.Query()
+	query.Set("search_path", schema)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
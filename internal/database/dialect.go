@@ -0,0 +1,45 @@
+// dialect - differences of SQL between drivers of database
+//
+// queries of tasks (query.go) are same for all drivers: placeholders $1, RETURNING
+// only schema and check of tables differ
+package database
+
+import "github.com/Ekvo/yandex-practicum-go-final-project/internal/config"
+
+type dialect struct {
+	// driver - name of driver for 'sql.Open'
+	driver string
+
+	// migrations - versions of schema see (schema.go)
+	migrations []migration
+
+	// tableExists - number of tables with name $1
+	tableExists string
+}
+
+var dialects = map[string]dialect{
+	config.DriverSQLite: {
+		driver:     "sqlite",
+		migrations: sqliteMigrations,
+		tableExists: `
+SELECT COUNT(*)
+FROM sqlite_master
+WHERE type = 'table' AND name = $1;`,
+	},
+	config.DriverPostgres: {
+		driver:     "postgres",
+		migrations: postgresMigrations,
+		tableExists: `
+SELECT COUNT(*)
+FROM information_schema.tables
+WHERE table_schema = current_schema() AND table_name = $1;`,
+	},
+}
+
+// dialectOf - dialect by name of driver from config, empty - SQLite
+func dialectOf(driver string) dialect {
+	if d, ok := dialects[driver]; ok {
+		return d
+	}
+	return dialects[config.DriverSQLite]
+}
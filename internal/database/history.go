@@ -0,0 +1,105 @@
+// history - completions of tasks in table 'task_completions', see (schema.go)
+//
+// task_id has no foreign key: history of task is kept after task is deleted
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/services/entity"
+)
+
+// CompleteTask - get 'ptr' of type 'entity.TaskDone' from 'data'
+// use -> Transaction(ctx fucn(ctx)error)error
+//
+// save completion, then new date, time and repeat of task or delete task (entity.TaskDone.IsLast)
+// task not exist -> ErrDataBaseNotFound, completion is not saved
+func (s Source) CompleteTask(ctx context.Context, data any) error {
+	done := data.(*entity.TaskDone)
+	completion := done.PassCompletion()
+	completeTask := func(ctx context.Context) error {
+		_, err := s.store.Tx.ExecContext(ctx, `
+INSERT INTO task_completions (task_id,
+                              date,
+                              time,
+                              done_at,
+                              note)
+VALUES ($1, $2, $3, $4, $5);`,
+			completion.TaskID, // 1
+			completion.Date,   // 2
+			completion.Time,   // 3
+			completion.DoneAt, // 4
+			completion.Note,   // 5
+		)
+		if err != nil {
+			return err
+		}
+		id := uint(0)
+		if done.IsLast() {
+			err = s.store.Tx.QueryRowContext(ctx, `
+DELETE
+from scheduler
+WHERE id = $1
+RETURNING id;`, completion.TaskID).Scan(&id)
+		} else {
+			next := done.PassNext()
+			err = s.store.Tx.QueryRowContext(ctx, `
+UPDATE scheduler
+SET date    = $2,
+    time    = $3,
+    repeat  = $4
+WHERE id = $1
+RETURNING id;`,
+				next.ID,     //1
+				next.Date,   //2
+				next.Time,   //3
+				next.Repeat, //4
+			).Scan(&id)
+		}
+		if err != nil && errors.Is(err, sql.ErrNoRows) {
+			return ErrDataBaseNotFound
+		}
+		return err
+	}
+	return s.store.Transaction(ctx, completeTask)
+}
+
+// FindCompletionList - get ID of task from 'data', completions in order of completion
+// nothing found -> nil
+func (s Source) FindCompletionList(ctx context.Context, data any) ([]model.CompletionModel, error) {
+	taskID := data.(uint)
+	rows, err := s.store.DB.QueryContext(ctx, `
+SELECT id, task_id, date, time, done_at, note
+FROM task_completions
+WHERE task_id = $1
+ORDER BY id ASC;`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("history: rows.Close error - %v", err)
+		}
+	}()
+	var completions []model.CompletionModel
+	for rows.Next() {
+		var completion model.CompletionModel
+		err := rows.Scan(
+			&completion.ID,
+			&completion.TaskID,
+			&completion.Date,
+			&completion.Time,
+			&completion.DoneAt,
+			&completion.Note,
+		)
+		if err != nil {
+			return nil, err
+		}
+		completions = append(completions, completion)
+	}
+	return completions, rows.Err()
+}
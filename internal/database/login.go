@@ -0,0 +1,67 @@
+// login - store for user password in table 'login' of database
+//
+// password is shared by all instances of application with one database (PostgreSQL)
+// the .env file only seeds password of database without password during application startup, see 'SeedPassword'
+// change or removal of password is action of administrator of database (UPDATE login / DELETE FROM login)
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+)
+
+type LoginSource struct {
+	store dbTX
+}
+
+func NewLoginSource(db *sql.DB) LoginSource {
+	return LoginSource{store: dbTX{DB: db}}
+}
+
+// SeedPassword - set password of user only if database has no password, stored password is not changed
+// empty -> nothing, instance without password can't turn off authorization of other instances
+func (s LoginSource) SeedPassword(ctx context.Context, password string) error {
+	if password == "" {
+		return nil
+	}
+	_, err := s.store.DB.ExecContext(ctx, `
+INSERT INTO login (id, password)
+VALUES (1, $1)
+ON CONFLICT (id) DO NOTHING;`, password)
+	return err
+}
+
+// password - stored password, without row - empty
+func (s LoginSource) password(ctx context.Context) (string, error) {
+	password := ""
+	err := s.store.DB.QueryRowContext(ctx, `
+SELECT password
+FROM login
+WHERE id = 1;`).Scan(&password)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return password, err
+}
+
+func (s LoginSource) ValidLogin(ctx context.Context, login model.LoginModel) bool {
+	password, err := s.password(ctx)
+	if err != nil {
+		log.Printf("login: password error - %v", err)
+		return false
+	}
+	return password == login.Password
+}
+
+func (s LoginSource) PasswordExist(ctx context.Context) bool {
+	password, err := s.password(ctx)
+	if err != nil {
+		log.Printf("login: password error - %v", err)
+		return false
+	}
+	return len(password) > 0
+}
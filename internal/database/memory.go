@@ -0,0 +1,247 @@
+// memory - store of tasks in memory of process, without database
+package database
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"unicode"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/services/entity"
+)
+
+// MemorySource - implement task interfaces of model like 'Source' (query.go)
+// tasks are lost on exit -> for demo and tests (TODO_DBFILE=":memory:" or flag --memory)
+//
+// safe for concurrent use, ID of new task is next after last saved like AUTOINCREMENT
+type MemorySource struct {
+	mu     sync.RWMutex
+	lastID uint
+	tasks  map[uint]model.TaskModel
+
+	// completions - history of all tasks in order of completion, ID is index + 1
+	completions []model.CompletionModel
+}
+
+func NewMemorySource() *MemorySource {
+	return &MemorySource{tasks: make(map[uint]model.TaskModel)}
+}
+
+// SaveOneTask - save copy of task with new ID and return ID
+func (s *MemorySource) SaveOneTask(ctx context.Context, data any) (uint, error) {
+	newTask := data.(model.TaskModel)
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastID++
+	newTask.ID = s.lastID
+	s.tasks[newTask.ID] = newTask
+	return newTask.ID, nil
+}
+
+// FindOneTask - task by ID from 'data', not exist -> ErrDataBaseNotFound
+func (s *MemorySource) FindOneTask(ctx context.Context, data any) (model.TaskModel, error) {
+	taskID := data.(uint)
+	if err := ctx.Err(); err != nil {
+		return model.TaskModel{}, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	task, ok := s.tasks[taskID]
+	if !ok {
+		return model.TaskModel{}, ErrDataBaseNotFound
+	}
+	return task, nil
+}
+
+// NewDataTask - replace task with same ID, not exist -> ErrDataBaseNotFound
+func (s *MemorySource) NewDataTask(ctx context.Context, data any) error {
+	newTask := data.(model.TaskModel)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tasks[newTask.ID]; !ok {
+		return ErrDataBaseNotFound
+	}
+	s.tasks[newTask.ID] = newTask
+	return nil
+}
+
+// ExpirationTask - remove task by ID, not exist -> ErrDataBaseNotFound
+func (s *MemorySource) ExpirationTask(ctx context.Context, data any) error {
+	taskID := data.(uint)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tasks[taskID]; !ok {
+		return ErrDataBaseNotFound
+	}
+	delete(s.tasks, taskID)
+	return nil
+}
+
+// CompleteTask - get 'ptr' of type 'entity.TaskDone' from 'data' see 'Source.CompleteTask'
+// completion and new data of task are saved under one lock
+func (s *MemorySource) CompleteTask(ctx context.Context, data any) error {
+	done := data.(*entity.TaskDone)
+	completion := done.PassCompletion()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, ok := s.tasks[completion.TaskID]
+	if !ok {
+		return ErrDataBaseNotFound
+	}
+	if done.IsLast() {
+		delete(s.tasks, task.ID)
+	} else {
+		next := done.PassNext()
+		task.Date, task.Time, task.Repeat = next.Date, next.Time, next.Repeat
+		s.tasks[task.ID] = task
+	}
+	completion.ID = uint(len(s.completions)) + 1
+	s.completions = append(s.completions, completion)
+	return nil
+}
+
+// FindCompletionList - get ID of task from 'data', completions in order of completion, nothing found -> nil
+func (s *MemorySource) FindCompletionList(ctx context.Context, data any) ([]model.CompletionModel, error) {
+	taskID := data.(uint)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var completions []model.CompletionModel
+	for _, completion := range s.completions {
+		if completion.TaskID == taskID {
+			completions = append(completions, completion)
+		}
+	}
+	return completions, nil
+}
+
+// FindTaskList - get 'ptr' of type 'services.TaskProperty' from 'data'
+// same rules as query of 'Source' without full-text search (search.go):
+// word -> LIKE '%word%' by title or comment (or field of filter), date and filters -> 'TaskProperty.Matches',
+// words of structured search -> LIKE '%word%' see 'entity.TaskQuery.MatchesTerms',
+// ordered by key of 'TaskProperty', page after cursor (order by relevance - only first page), no more than limit
+func (s *MemorySource) FindTaskList(ctx context.Context, data any) ([]model.TaskModel, error) {
+	property := data.(*entity.TaskProperty)
+	pattern := "%" + property.PassWord() + "%"
+	field := property.PassFilter().PassField()
+	tasks, err := s.filter(ctx, func(task model.TaskModel) bool {
+		if property.IsWord() {
+			title := field != entity.FieldComment && like(task.Title, pattern)
+			comment := field != entity.FieldTitle && like(task.Comment, pattern)
+			if !title && !comment {
+				return false
+			}
+		}
+		if search := property.PassQuery(); search != nil && !search.MatchesTerms(task, contains) {
+			return false
+		}
+		return property.Matches(task) && property.IsAfterCursor(task)
+	})
+	sort.Slice(tasks, func(i, j int) bool {
+		return property.Less(tasks[i], tasks[j])
+	})
+	if limit := property.PassLimit(); uint(len(tasks)) > limit {
+		tasks = tasks[:limit]
+	}
+	if len(tasks) == 0 {
+		return nil, err
+	}
+	return tasks, err
+}
+
+// FindRepeatTaskList - get 'ptr' of type 'services.TaskProperty' from 'data'
+// tasks with repeat and date before date of 'TaskProperty' see 'Source.FindRepeatTaskList'
+func (s *MemorySource) FindRepeatTaskList(ctx context.Context, data any) ([]model.TaskModel, error) {
+	property := data.(*entity.TaskProperty)
+	date := property.PassDate().Format(model.DateFormat)
+	return s.filter(ctx, func(task model.TaskModel) bool {
+		return task.Repeat != "" && task.Date < date
+	})
+}
+
+// FindAgendaTaskList - get 'ptr' of type 'services.TaskAgenda' from 'data'
+// tasks of window and tasks with repeat before end of window see 'Source.FindAgendaTaskList'
+func (s *MemorySource) FindAgendaTaskList(ctx context.Context, data any) ([]model.TaskModel, error) {
+	agenda := data.(*entity.TaskAgenda)
+	from := agenda.PassFrom().Format(model.DateFormat)
+	to := agenda.PassTo().Format(model.DateFormat)
+	return s.filter(ctx, func(task model.TaskModel) bool {
+		return task.Date <= to && (task.Date >= from || task.Repeat != "")
+	})
+}
+
+// filter - copies of tasks matched by 'match' in order of "ORDER BY date ASC, time ASC"
+// tasks with same date and time are ordered by ID, nothing found -> nil like 'scanTaskList'
+func (s *MemorySource) filter(ctx context.Context, match func(task model.TaskModel) bool) ([]model.TaskModel, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	var tasks []model.TaskModel
+	for _, task := range s.tasks {
+		if match(task) {
+			tasks = append(tasks, task)
+		}
+	}
+	s.mu.RUnlock()
+	sort.Slice(tasks, func(i, j int) bool {
+		if tasks[i].Date != tasks[j].Date {
+			return tasks[i].Date < tasks[j].Date
+		}
+		if tasks[i].Time != tasks[j].Time {
+			return tasks[i].Time < tasks[j].Time
+		}
+		return tasks[i].ID < tasks[j].ID
+	})
+	return tasks, nil
+}
+
+// contains - 'word' is part of 'text' as LIKE '%word%'
+func contains(text, word string) bool {
+	return like(text, "%"+word+"%")
+}
+
+// like - operator LIKE without ESCAPE
+// '%' - any sequence of characters, '_' - one character,
+// case of all letters is ignored like full-text search of SQLite (FTS5 unicode61) and ILIKE of PostgreSQL
+func like(s, pattern string) bool {
+	str, pat := []rune(s), []rune(pattern)
+	i, j := 0, 0
+
+	// position of last '%' in 'pat' and position in 'str' matched with it
+	star, next := -1, 0
+	for i < len(str) {
+		switch {
+		case j < len(pat) && pat[j] == '%':
+			star, next = j, i
+			j++
+		case j < len(pat) && (pat[j] == '_' || unicode.ToLower(pat[j]) == unicode.ToLower(str[i])):
+			i++
+			j++
+		case star != -1:
+			next++
+			i, j = next, star+1
+		default:
+			return false
+		}
+	}
+	for j < len(pat) && pat[j] == '%' {
+		j++
+	}
+	return j == len(pat)
+}
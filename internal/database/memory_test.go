@@ -0,0 +1,156 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/services/entity"
+)
+
+func TestMemorySource_FindTaskList(t *testing.T) {
+	requires := require.New(t)
+	ctx := context.Background()
+
+	s := NewMemorySource()
+	for _, task := range []model.TaskModel{
+		{Date: "30000103", Title: "третья", Comment: "Молоко"},
+		{Date: "30000102", Time: "18:00", Title: "evening walk"},
+		{Date: "30000102", Title: "Buy 100% juice"},
+		{Date: "30000102", Time: "09:00", Title: "morning walk"},
+		{Date: "30000101", Title: "first", Comment: "buy bread"},
+	} {
+		_, err := s.SaveOneTask(ctx, task)
+		requires.NoError(err)
+	}
+
+	titles := func(tasks []model.TaskModel) []string {
+		var res []string
+		for _, task := range tasks {
+			res = append(res, task.Title)
+		}
+		return res
+	}
+
+	for _, test := range []struct {
+		property *entity.TaskProperty
+		expected []string
+		msg      string
+	}{
+		{
+			property: entity.NewTaskProperty("", 50),
+			expected: []string{"first", "Buy 100% juice", "morning walk", "evening walk", "третья"},
+			msg:      "all tasks ordered by date and time",
+		},
+		{
+			property: entity.NewTaskProperty("02.01.3000", 50),
+			expected: []string{"Buy 100% juice", "morning walk", "evening walk"},
+			msg:      "by date, whole day is first",
+		},
+		{
+			property: entity.NewTaskProperty("BUY", 50),
+			expected: []string{"first", "Buy 100% juice"},
+			msg:      "by word in title or comment, ASCII case is ignored",
+		},
+		{
+			property: entity.NewTaskProperty("молоко", 50),
+			expected: []string{"третья"},
+			msg:      "case of cyrillic is ignored in comment like full-text search",
+		},
+		{
+			property: entity.NewTaskProperty("ТРЕТЬ", 50),
+			expected: []string{"третья"},
+			msg:      "case of cyrillic is ignored in title like full-text search",
+		},
+		{
+			property: entity.NewTaskProperty("m_rning", 50),
+			expected: []string{"morning walk"},
+			msg:      "'_' is one character like LIKE",
+		},
+		{
+			property: entity.NewTaskProperty("0%j", 50),
+			expected: []string{"Buy 100% juice"},
+			msg:      "'%' is any sequence like LIKE",
+		},
+	} {
+		tasks, err := s.FindTaskList(ctx, test.property)
+		requires.NoError(err, test.msg)
+		assert.Equal(t, test.expected, titles(tasks), test.msg)
+	}
+
+	// limit after order: 5 tasks above and 10 later
+	for i := 0; i < 10; i++ {
+		_, err := s.SaveOneTask(ctx, model.TaskModel{Date: "30000110", Title: "later"})
+		requires.NoError(err)
+	}
+	tasks, err := s.FindTaskList(ctx, entity.NewTaskProperty("", 10))
+	requires.NoError(err)
+	requires.Len(tasks, 10, "limit of list")
+	assert.Equal(t, "first", tasks[0].Title)
+	assert.Equal(t, "третья", tasks[4].Title)
+	assert.Equal(t, uint(6), tasks[5].ID, "same date and time are ordered by ID")
+	assert.Equal(t, uint(10), tasks[9].ID, "same date and time are ordered by ID")
+}
+
+func TestMemorySource_Concurrent(t *testing.T) {
+	asserts := assert.New(t)
+	ctx := context.Background()
+
+	s := NewMemorySource()
+	const count = 40
+	ids := make(chan uint, count)
+	wg := sync.WaitGroup{}
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id, err := s.SaveOneTask(ctx, model.TaskModel{Date: "30000101", Title: "task"})
+			asserts.NoError(err)
+			_, err = s.FindTaskList(ctx, entity.NewTaskProperty("task", 50))
+			asserts.NoError(err)
+			ids <- id
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	unique := make(map[uint]bool)
+	for id := range ids {
+		unique[id] = true
+	}
+	asserts.Len(unique, count, "ID of every task is unique")
+
+	tasks, err := s.FindTaskList(ctx, entity.NewTaskProperty("", 50))
+	asserts.NoError(err)
+	asserts.Len(tasks, count)
+}
+
+func TestLike(t *testing.T) {
+	for _, test := range []struct {
+		s, pattern string
+		expected   bool
+	}{
+		{"", "%", true},
+		{"", "%%", true},
+		{"", "_", false},
+		{"abc", "abc", true},
+		{"abc", "ABC", true},
+		{"abc", "ab", false},
+		{"abc", "%b%", true},
+		{"abc", "a%c", true},
+		{"abc", "a_c", true},
+		{"abc", "a__c", false},
+		{"aXbXc", "%x%c", true},
+		{"mississippi", "%iss%ppi", true},
+		{"mississippi", "%iss%ppx", false},
+		{"Ёлка", "ёлка", true},
+		{"ЁЛКА", "%лк_", true},
+		{"ёлка", "_лка", true},
+	} {
+		assert.Equal(t, test.expected, like(test.s, test.pattern), test.s+" LIKE "+test.pattern)
+	}
+}
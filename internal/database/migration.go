@@ -0,0 +1,159 @@
+// migration - versions of schema in table 'schema_version'
+//
+// version of database is max version of 'schema_version', empty table - 0
+// every pending migration is applied in own transaction with record of its version
+// list of migrations and check of tables by driver of database see (dialect.go)
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Migration - version of schema for print (--migrate-only)
+type Migration struct {
+	Version     int
+	Description string
+
+	// AppliedAt - time of apply (UTC), pending migration - empty
+	AppliedAt string
+}
+
+// migration - one step of schema, 'up' is executed inside transaction
+type migration struct {
+	version     int
+	description string
+	up          func(ctx context.Context, tx *sql.Tx) error
+}
+
+// execSQL - step of migration as SQL query
+func execSQL(query string) func(ctx context.Context, tx *sql.Tx) error {
+	return func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query)
+		return err
+	}
+}
+
+// addColumn - step of migration, add column to table if missing
+// SQLite has no 'ADD COLUMN IF NOT EXISTS', PostgreSQL migrations use it in 'execSQL'
+func addColumn(table, name, definition string) func(ctx context.Context, tx *sql.Tx) error {
+	return func(ctx context.Context, tx *sql.Tx) error {
+		exist := 0
+		err := tx.QueryRowContext(ctx, `
+SELECT COUNT(*)
+FROM pragma_table_info($1)
+WHERE name = $2;`, table, name).Scan(&exist)
+		if err != nil || exist != 0 {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s;", table, name, definition))
+		return err
+	}
+}
+
+// noop - step of migration without changes, for version that is not needed by driver
+func noop(context.Context, *sql.Tx) error {
+	return nil
+}
+
+const schemaVersion = `
+CREATE TABLE IF NOT EXISTS schema_version
+(
+    version INTEGER PRIMARY KEY,
+    description VARCHAR(255) NOT NULL,
+    applied_at VARCHAR(20) NOT NULL
+);`
+
+// Migrations - all migrations of application with time of apply, pending - empty 'AppliedAt'
+// without table 'schema_version' all migrations are pending, database is not changed
+// 'driver' - from config, empty - SQLite
+func Migrations(ctx context.Context, db *sql.DB, driver string) ([]Migration, error) {
+	d := dialectOf(driver)
+	migrations := d.migrations
+	exist := 0
+	if err := db.QueryRowContext(ctx, d.tableExists, "schema_version").Scan(&exist); err != nil {
+		return nil, err
+	}
+	list := make([]Migration, 0, len(migrations))
+	for _, m := range migrations {
+		list = append(list, Migration{Version: m.version, Description: m.description})
+	}
+	if exist == 0 {
+		return list, nil
+	}
+	rows, err := db.QueryContext(ctx, `
+SELECT version, applied_at
+FROM schema_version;`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("migration: rows.Close error - %v", err)
+		}
+	}()
+	applied := make(map[int]string)
+	for rows.Next() {
+		version, appliedAt := 0, ""
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for i := range list {
+		list[i].AppliedAt = applied[list[i].Version]
+	}
+	return list, nil
+}
+
+// Migrate - create table 'schema_version', apply pending migrations in order and return them
+func Migrate(ctx context.Context, db *sql.DB, driver string) ([]Migration, error) {
+	if _, err := db.ExecContext(ctx, schemaVersion); err != nil {
+		return nil, err
+	}
+	list, err := Migrations(ctx, db, driver)
+	if err != nil {
+		return nil, err
+	}
+	var done []Migration
+	for i, m := range dialectOf(driver).migrations {
+		if list[i].AppliedAt != "" {
+			continue
+		}
+		appliedAt := time.Now().UTC().Format(time.RFC3339)
+		if err := applyMigration(ctx, db, m, appliedAt); err != nil {
+			return done, fmt.Errorf("migration %d (%s) error - %w", m.version, m.description, err)
+		}
+		done = append(done, Migration{Version: m.version, Description: m.description, AppliedAt: appliedAt})
+	}
+	return done, nil
+}
+
+// applyMigration - step of migration and record of version in one transaction
+func applyMigration(ctx context.Context, db *sql.DB, m migration, appliedAt string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("migration: Rollback error - %v", err)
+		}
+	}()
+	if err := m.up(ctx, tx); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+INSERT INTO schema_version (version, description, applied_at)
+VALUES ($1, $2, $3);`, m.version, m.description, appliedAt)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
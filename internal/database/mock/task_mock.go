@@ -11,14 +11,16 @@ import (
 )
 
 type MockTaskStore struct {
-	id    *uint
-	tasks map[uint]model.TaskModel
+	id          *uint
+	tasks       map[uint]model.TaskModel
+	completions *[]model.CompletionModel
 }
 
 func NewMockTaskStore() MockTaskStore {
 	return MockTaskStore{
-		id:    new(uint),
-		tasks: make(map[uint]model.TaskModel),
+		id:          new(uint),
+		tasks:       make(map[uint]model.TaskModel),
+		completions: new([]model.CompletionModel),
 	}
 }
 
@@ -63,31 +65,100 @@ func (s MockTaskStore) ExpirationTask(ctx context.Context, data any) error {
 	return nil
 }
 
+func (s MockTaskStore) CompleteTask(_ context.Context, data any) error {
+	done := data.(*entity.TaskDone)
+	completion := done.PassCompletion()
+	task, ex := s.tasks[completion.TaskID]
+	if !ex {
+		return database.ErrDataBaseNotFound
+	}
+	if done.IsLast() {
+		delete(s.tasks, task.ID)
+	} else {
+		next := done.PassNext()
+		task.Date, task.Time, task.Repeat = next.Date, next.Time, next.Repeat
+		s.tasks[task.ID] = task
+	}
+	completion.ID = uint(len(*s.completions)) + 1
+	*s.completions = append(*s.completions, completion)
+	return nil
+}
+
+func (s MockTaskStore) FindCompletionList(_ context.Context, data any) ([]model.CompletionModel, error) {
+	taskID := data.(uint)
+	var completions []model.CompletionModel
+	for _, completion := range *s.completions {
+		if completion.TaskID == taskID {
+			completions = append(completions, completion)
+		}
+	}
+	return completions, nil
+}
+
 func (s MockTaskStore) FindTaskList(_ context.Context, data any) ([]model.TaskModel, error) {
 	property := data.(*entity.TaskProperty)
 	var arrOfTask []model.TaskModel
 
 	word := property.PassWord()
-	date := property.PassDate().UTC().Format(model.DateFormat)
+	field := property.PassFilter().PassField()
 	for _, task := range s.tasks {
 		if property.IsWord() {
-			if strings.Contains(task.Title, word) || strings.Contains(task.Comment, word) {
-				arrOfTask = append(arrOfTask, task)
+			title := field != entity.FieldComment && strings.Contains(task.Title, word)
+			comment := field != entity.FieldTitle && strings.Contains(task.Comment, word)
+			if !title && !comment {
+				continue
 			}
-		} else if property.IsDate() {
-			if task.Date == date {
-				arrOfTask = append(arrOfTask, task)
-			}
-		} else {
+		}
+		if search := property.PassQuery(); search != nil && !search.MatchesTerms(task, strings.Contains) {
+			continue
+		}
+		if property.Matches(task) && property.IsAfterCursor(task) {
 			arrOfTask = append(arrOfTask, task)
 		}
 	}
+	sort.Slice(arrOfTask, func(i, j int) bool {
+		return property.Less(arrOfTask[i], arrOfTask[j])
+	})
 	limit := property.PassLimit()
 	if len(arrOfTask) > int(limit) {
 		return arrOfTask[:limit], nil
 	}
+	return arrOfTask, nil
+}
+
+func (s MockTaskStore) FindRepeatTaskList(_ context.Context, data any) ([]model.TaskModel, error) {
+	property := data.(*entity.TaskProperty)
+	date := property.PassDate().UTC().Format(model.DateFormat)
+	var arrOfTask []model.TaskModel
+	for _, task := range s.tasks {
+		if task.Repeat != "" && task.Date < date {
+			arrOfTask = append(arrOfTask, task)
+		}
+	}
+	sort.Slice(arrOfTask, func(i, j int) bool {
+		if arrOfTask[i].Date != arrOfTask[j].Date {
+			return arrOfTask[i].Date < arrOfTask[j].Date
+		}
+		return arrOfTask[i].Time < arrOfTask[j].Time
+	})
+	return arrOfTask, nil
+}
+
+func (s MockTaskStore) FindAgendaTaskList(_ context.Context, data any) ([]model.TaskModel, error) {
+	agenda := data.(*entity.TaskAgenda)
+	from := agenda.PassFrom().UTC().Format(model.DateFormat)
+	to := agenda.PassTo().UTC().Format(model.DateFormat)
+	var arrOfTask []model.TaskModel
+	for _, task := range s.tasks {
+		if task.Date <= to && (task.Date >= from || task.Repeat != "") {
+			arrOfTask = append(arrOfTask, task)
+		}
+	}
 	sort.Slice(arrOfTask, func(i, j int) bool {
-		return arrOfTask[i].Date < arrOfTask[j].Date
+		if arrOfTask[i].Date != arrOfTask[j].Date {
+			return arrOfTask[i].Date < arrOfTask[j].Date
+		}
+		return arrOfTask[i].Time < arrOfTask[j].Time
 	})
 	return arrOfTask, nil
 }
@@ -0,0 +1,30 @@
+// postgres - storage of tasks in PostgreSQL
+//
+// queries of 'Source' (query.go) are valid for PostgreSQL, they are reused,
+// search by word is case-insensitive as LIKE of SQLite
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/services/entity"
+)
+
+type PostgresSource struct {
+	Source
+}
+
+func NewPostgresSource(db *sql.DB) PostgresSource {
+	return PostgresSource{Source: NewSource(db)}
+}
+
+// postgresText - LIKE of PostgreSQL is case-sensitive -> ILIKE
+// order of text depends on collation of database -> order of bytes by "C"
+var postgresText = textSyntax{like: "ILIKE", title: `title COLLATE "C"`}
+
+// FindTaskList - same as 'Source.FindTaskList' without full-text search, see 'postgresText'
+func (s PostgresSource) FindTaskList(ctx context.Context, data any) ([]model.TaskModel, error) {
+	return s.findTaskList(ctx, data.(*entity.TaskProperty), postgresText, "")
+}
@@ -29,13 +29,21 @@ func (s Source) SaveOneTask(ctx context.Context, data any) (uint, error) {
 INSERT INTO scheduler (date,
                        title,
                        comment,
-                       repeat)
-VALUES ($1, $2, $3, $4)
+                       repeat,
+                       algorithm,
+                       mode,
+                       time,
+                       exdates)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 RETURNING id;`,
-			newTask.Date,    // 1
-			newTask.Title,   // 2
-			newTask.Comment, // 3 // if empty need write null, but _test_ need ""
-			newTask.Repeat,  // 4
+			newTask.Date,      // 1
+			newTask.Title,     // 2
+			newTask.Comment,   // 3 // if empty need write null, but _test_ need ""
+			newTask.Repeat,    // 4
+			newTask.Algorithm, // 5
+			newTask.Mode,      // 6
+			newTask.Time,      // 7
+			newTask.Exdates,   // 8
 		).Scan(&newTask.ID)
 		return err
 	}
@@ -46,7 +54,7 @@ RETURNING id;`,
 func (s Source) FindOneTask(ctx context.Context, data any) (model.TaskModel, error) {
 	taskID := data.(uint)
 	row := s.store.DB.QueryRowContext(ctx, `
-SELECT *
+SELECT `+taskColumns+`
 FROM scheduler
 WHERE id = $1
 LIMIT 1;`, taskID)
@@ -57,6 +65,9 @@ LIMIT 1;`, taskID)
 	return task, err
 }
 
+// taskColumns - order of columns for 'scanTask'
+const taskColumns = "id, date, title, comment, repeat, algorithm, mode, time, exdates"
+
 func scanTask[T common.ScanSQL](r T) (model.TaskModel, error) {
 	var task model.TaskModel
 	err := r.Scan(
@@ -65,6 +76,10 @@ func scanTask[T common.ScanSQL](r T) (model.TaskModel, error) {
 		&task.Title,
 		&task.Comment,
 		&task.Repeat,
+		&task.Algorithm,
+		&task.Mode,
+		&task.Time,
+		&task.Exdates,
 	)
 	return task, err
 }
@@ -80,14 +95,22 @@ UPDATE scheduler
 SET date    = $2,
     title   = $3,
     comment = $4,
-    repeat  = $5
+    repeat  = $5,
+    algorithm = $6,
+    mode    = $7,
+    time    = $8,
+    exdates = $9
 WHERE id = $1
 RETURNING id;`,
-			newTask.ID,      //1
-			newTask.Date,    //2
-			newTask.Title,   //3
-			newTask.Comment, //4
-			newTask.Repeat,  //5
+			newTask.ID,        //1
+			newTask.Date,      //2
+			newTask.Title,     //3
+			newTask.Comment,   //4
+			newTask.Repeat,    //5
+			newTask.Algorithm, //6
+			newTask.Mode,      //7
+			newTask.Time,      //8
+			newTask.Exdates,   //9
 		).Scan(&id)
 		if err != nil && errors.Is(err, sql.ErrNoRows) {
 			return ErrDataBaseNotFound
@@ -118,27 +141,92 @@ RETURNING id;`, taskID).Scan(&id)
 
 // FindTaskList - get 'ptr' of type 'services.TaskProperty' from 'data' look (internal/services/taskproperty.go)
 //
-// add a command line to 'strings.Builder' if we find any characteristic from "TaskProperty" then append 'args'
-// args        - pass to sql.QueryContext
-// numberOfArg - marks the argument number in the query string
+// search by word -> only full-text search see (search.go), part of word is found as prefix of word,
+// word without letters and digits has no terms of full-text search -> nil
 func (s Source) FindTaskList(ctx context.Context, data any) ([]model.TaskModel, error) {
 	property := data.(*entity.TaskProperty)
+	if !property.IsWord() {
+		return s.findTaskList(ctx, property, sqliteText, "")
+	}
+	match := matchQuery(property.PassWord(), property.PassFilter().PassField())
+	if match == "" {
+		return nil, nil
+	}
+	return s.findTaskList(ctx, property, sqliteText, match)
+}
+
+// textSyntax - operators of text in query of 'findTaskList', differ between drivers
+type textSyntax struct {
+	// like - search of part of word, case-insensitive for ASCII
+	like string
+
+	// title - title in order of bytes as 'entity.TaskProperty.Less'
+	title string
+}
+
+var sqliteText = textSyntax{like: "LIKE", title: "title"}
+
+// findTaskList - query of 'FindTaskList', 'match' - expression of full-text search (filter of tasks),
+// empty - search by word is part of word by 'text.like' (stores without full-text search: PostgreSQL)
+// search, filters and order of 'property' are composed into one query, values are passed only as args
+// list is ordered by key of 'property', page after cursor is found by key of last task,
+// order by relevance (full-text search without key) - only first page see 'entity.TaskProperty.IsRelevance'
+//
+// add a condition to 'where' if we find any characteristic from "TaskProperty" then append 'args'
+// args - pass to sql.QueryContext, 'arg' returns placeholder of appended value
+func (s Source) findTaskList(
+	ctx context.Context,
+	property *entity.TaskProperty,
+	text textSyntax,
+	match string) ([]model.TaskModel, error) {
 	query := strings.Builder{}
-	args := make([]any, 0, 2)
-	numberOfArg := 1
-
-	query.WriteString("SELECT * FROM scheduler")
-	if property.IsWord() {
-		query.WriteString("\nWHERE title LIKE $1 OR comment LIKE $1")
-		args = append(args, fmt.Sprintf(`%%%s%%`, property.PassWord()))
-		numberOfArg++
-	} else if property.IsDate() {
-		query.WriteString("\nWHERE date = $1")
-		args = append(args, property.PassDate().Format(model.DateFormat))
-		numberOfArg++
-	}
-	query.WriteString(fmt.Sprintf("\nORDER BY date ASC\nLIMIT $%d;", numberOfArg))
-	args = append(args, property.PassLimit())
+	args := make([]any, 0, 10)
+	where := make([]string, 0, 6)
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	filter := property.PassFilter()
+
+	query.WriteString("SELECT " + taskColumns + " FROM scheduler")
+	if match != "" {
+		query.WriteString(fmt.Sprintf(searchJoin, arg(match)))
+	} else if property.IsWord() {
+		where = append(where, likeOf(filter.PassField(), text, arg(fmt.Sprintf(`%%%s%%`, property.PassWord()))))
+	}
+	if search := property.PassQuery(); search != nil {
+		where = append(where, queryConditions(search, text, arg)...)
+	}
+	if property.IsDate() {
+		where = append(where, "date = "+arg(property.PassDate().Format(model.DateFormat)))
+	}
+	if from := filter.PassFrom(); !from.IsZero() {
+		where = append(where, "date >= "+arg(from.Format(model.DateFormat)))
+	}
+	if to := filter.PassTo(); !to.IsZero() {
+		where = append(where, "date <= "+arg(to.Format(model.DateFormat)))
+	}
+	if before := filter.PassBefore(); !before.IsZero() {
+		where = append(where, "date < "+arg(before.Format(model.DateFormat)))
+	}
+	switch filter.PassKind() {
+	case entity.KindRecurring:
+		where = append(where, "repeat <> ''")
+	case entity.KindOnce:
+		where = append(where, "repeat = ''")
+	}
+	if cursor := property.PassCursor(); cursor != nil && !property.IsRelevance() {
+		where = append(where, afterCursor(property, text, cursor.PassTask(), arg))
+	}
+	if len(where) > 0 {
+		query.WriteString("\nWHERE " + strings.Join(where, " AND "))
+	}
+	query.WriteString("\nORDER BY ")
+	if match != "" && property.IsRelevance() {
+		query.WriteString("found.score ASC, ")
+	}
+	query.WriteString(orderOf(property, text))
+	query.WriteString(fmt.Sprintf("\nLIMIT %s;", arg(property.PassLimit())))
 
 	rows, err := s.store.DB.QueryContext(ctx, query.String(), args...)
 	if err != nil {
@@ -152,6 +240,131 @@ func (s Source) FindTaskList(ctx context.Context, data any) ([]model.TaskModel,
 	return scanTaskList(rows)
 }
 
+// likeOf - condition of search by 'pattern' in 'field' (entity.FieldTitle or entity.FieldComment), empty - both
+func likeOf(field string, text textSyntax, pattern string) string {
+	switch field {
+	case entity.FieldTitle:
+		return fmt.Sprintf("title %s %s", text.like, pattern)
+	case entity.FieldComment:
+		return fmt.Sprintf("comment %s %s", text.like, pattern)
+	}
+	return fmt.Sprintf("(title %[1]s %[2]s OR comment %[1]s %[2]s)", text.like, pattern)
+}
+
+// queryConditions - conditions of structured search see (internal/services/entity/taskquery.go)
+// word of term is part of text as search by word without full-text search
+func queryConditions(search *entity.TaskQuery, text textSyntax, arg func(value any) string) []string {
+	where := make([]string, 0, len(search.PassTerms())+3)
+	for _, term := range search.PassTerms() {
+		condition := likeOf(term.PassField(), text, arg(fmt.Sprintf(`%%%s%%`, term.PassWord())))
+		if term.IsNegative() {
+			condition = "NOT (" + condition + ")"
+		}
+		where = append(where, condition)
+	}
+	switch repeat := search.PassRepeat(); repeat {
+	case "":
+	case entity.RepeatAny:
+		where = append(where, "repeat <> ''")
+	case entity.RepeatNone:
+		where = append(where, "repeat = ''")
+	default:
+		condition := "substr(repeat, 1, 1) = " + arg(repeat)
+		if freq := search.PassFreq(); freq != "" {
+			condition = "(" + condition + " OR repeat " + text.like + " " + arg("%FREQ="+freq+"%") + ")"
+		}
+		where = append(where, condition)
+	}
+	if after := search.PassAfter(); !after.IsZero() {
+		where = append(where, "date > "+arg(after.Format(model.DateFormat)))
+	}
+	if before := search.PassBefore(); !before.IsZero() {
+		where = append(where, "date < "+arg(before.Format(model.DateFormat)))
+	}
+	return where
+}
+
+// afterCursor - condition of tasks after key of last task of page 'last' in order of 'property'
+func afterCursor(
+	property *entity.TaskProperty,
+	text textSyntax,
+	last model.TaskModel,
+	arg func(value any) string) string {
+	op := ">"
+	if property.PassFilter().IsDesc() {
+		op = "<"
+	}
+	switch property.SortKey() {
+	case entity.SortTitle:
+		return fmt.Sprintf("(%s, id) %s (%s, %s)", text.title, op, arg(last.Title), arg(last.ID))
+	case entity.SortID:
+		return fmt.Sprintf("id %s %s", op, arg(last.ID))
+	}
+	return fmt.Sprintf("(date, time, id) %s (%s, %s, %s)", op, arg(last.Date), arg(last.Time), arg(last.ID))
+}
+
+// orderOf - columns of ORDER BY by key of 'property', only known columns get to query
+func orderOf(property *entity.TaskProperty, text textSyntax) string {
+	direction := " ASC"
+	if property.PassFilter().IsDesc() {
+		direction = " DESC"
+	}
+	var columns []string
+	switch property.SortKey() {
+	case entity.SortTitle:
+		columns = []string{text.title, "id"}
+	case entity.SortID:
+		columns = []string{"id"}
+	default:
+		columns = []string{"date", "time", "id"}
+	}
+	return strings.Join(columns, direction+", ") + direction
+}
+
+// FindRepeatTaskList - get 'ptr' of type 'services.TaskProperty' from 'data'
+// tasks with repeat and date before date of 'TaskProperty', candidates for occurrence on this date
+// limit of 'TaskProperty' is not used - occurrence is checked after query
+func (s Source) FindRepeatTaskList(ctx context.Context, data any) ([]model.TaskModel, error) {
+	property := data.(*entity.TaskProperty)
+	rows, err := s.store.DB.QueryContext(ctx, `
+SELECT `+taskColumns+`
+FROM scheduler
+WHERE repeat != '' AND date < $1
+ORDER BY date ASC, time ASC;`, property.PassDate().Format(model.DateFormat))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("query: rows.Close error - %v", err)
+		}
+	}()
+	return scanTaskList(rows)
+}
+
+// FindAgendaTaskList - get 'ptr' of type 'services.TaskAgenda' from 'data'
+// tasks with date in window of 'TaskAgenda' and tasks with repeat and date before end of window
+// occurrences of recurring tasks are found after query
+func (s Source) FindAgendaTaskList(ctx context.Context, data any) ([]model.TaskModel, error) {
+	agenda := data.(*entity.TaskAgenda)
+	rows, err := s.store.DB.QueryContext(ctx, `
+SELECT `+taskColumns+`
+FROM scheduler
+WHERE date <= $2 AND (date >= $1 OR repeat != '')
+ORDER BY date ASC, time ASC;`,
+		agenda.PassFrom().Format(model.DateFormat),
+		agenda.PassTo().Format(model.DateFormat))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("query: rows.Close error - %v", err)
+		}
+	}()
+	return scanTaskList(rows)
+}
+
 func scanTaskList(rows *sql.Rows) ([]model.TaskModel, error) {
 	var tasks []model.TaskModel
 	for rows.Next() {
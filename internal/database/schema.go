@@ -1,12 +1,21 @@
-// schema - describes table(s) in database
+// schema - describes table(s) in database as ordered migrations
 package database
 
 import "time"
 
 const ctxTimeTableCreate = 10 * time.Second
 
-const (
-	schema = `
+// sqliteMigrations - versions of schema, applied in order by 'Migrate' (migration.go)
+//
+// every migration is idempotent: database file of old version without 'schema_version'
+// may already have table or column of migration
+// new migration is added only to end of list with next version, applied migration is never changed
+// versions and descriptions are same for all drivers (see 'postgresMigrations')
+var sqliteMigrations = []migration{
+	{
+		version:     1,
+		description: "create table scheduler",
+		up: execSQL(`
 CREATE TABLE IF NOT EXISTS scheduler
 (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -15,5 +24,150 @@ CREATE TABLE IF NOT EXISTS scheduler
     comment VARCHAR(2048) NULL,
     repeat VARCHAR(128) NOT NULL CHECK (LENGTH(repeat) <= 128)
 );
-CREATE INDEX IF NOT EXISTS date_id ON scheduler (date);`
-)
+CREATE INDEX IF NOT EXISTS date_id ON scheduler (date);`),
+	},
+	{
+		version:     2,
+		description: "add algorithm of repeat",
+		up:          addColumn("scheduler", "algorithm", "VARCHAR(16) NOT NULL DEFAULT ''"),
+	},
+	{
+		version:     3,
+		description: "add mode of repeat",
+		up:          addColumn("scheduler", "mode", "VARCHAR(16) NOT NULL DEFAULT ''"),
+	},
+	{
+		version:     4,
+		description: "add time of day",
+		up:          addColumn("scheduler", "time", "VARCHAR(5) NOT NULL DEFAULT ''"),
+	},
+	{
+		version:     5,
+		description: "add exception dates",
+		up:          addColumn("scheduler", "exdates", "VARCHAR(287) NOT NULL DEFAULT ''"),
+	},
+	{
+		version:     6,
+		description: "create table login",
+		up:          execSQL(loginTable),
+	},
+	{
+		version:     7,
+		description: "create full-text search of tasks",
+		up:          execSQL(searchTable),
+	},
+	{
+		version:     8,
+		description: "create history of completions",
+		up: execSQL(`
+CREATE TABLE IF NOT EXISTS task_completions
+(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id INTEGER NOT NULL,
+    date VARCHAR(8) NOT NULL,
+    time VARCHAR(5) NOT NULL DEFAULT '',
+    done_at VARCHAR(25) NOT NULL,
+    note VARCHAR(2048) NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS task_completions_task_id ON task_completions (task_id);`),
+	},
+}
+
+// searchTable - FTS5 index of title and comment (search.go), kept in sync with 'scheduler' by triggers
+// tokenizer 'unicode61' ignores case of all letters, tasks saved before are indexed by 'rebuild'
+const searchTable = `
+CREATE VIRTUAL TABLE IF NOT EXISTS scheduler_fts USING fts5
+(
+    title,
+    comment,
+    content = 'scheduler',
+    content_rowid = 'id',
+    tokenize = 'unicode61'
+);
+CREATE TRIGGER IF NOT EXISTS scheduler_fts_insert AFTER INSERT ON scheduler
+BEGIN
+    INSERT INTO scheduler_fts (rowid, title, comment) VALUES (new.id, new.title, new.comment);
+END;
+CREATE TRIGGER IF NOT EXISTS scheduler_fts_delete AFTER DELETE ON scheduler
+BEGIN
+    INSERT INTO scheduler_fts (scheduler_fts, rowid, title, comment) VALUES ('delete', old.id, old.title, old.comment);
+END;
+CREATE TRIGGER IF NOT EXISTS scheduler_fts_update AFTER UPDATE OF title, comment ON scheduler
+BEGIN
+    INSERT INTO scheduler_fts (scheduler_fts, rowid, title, comment) VALUES ('delete', old.id, old.title, old.comment);
+    INSERT INTO scheduler_fts (rowid, title, comment) VALUES (new.id, new.title, new.comment);
+END;
+INSERT INTO scheduler_fts (scheduler_fts) VALUES ('rebuild');`
+
+// loginTable - one row with password of user (login.go), same for all drivers
+const loginTable = `
+CREATE TABLE IF NOT EXISTS login
+(
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    password VARCHAR(255) NOT NULL
+);`
+
+// postgresMigrations - same versions as 'sqliteMigrations' in syntax of PostgreSQL
+// database of PostgreSQL has no old version, but migrations are idempotent too
+var postgresMigrations = []migration{
+	{
+		version:     1,
+		description: "create table scheduler",
+		up: execSQL(`
+CREATE TABLE IF NOT EXISTS scheduler
+(
+    id SERIAL PRIMARY KEY,
+    date VARCHAR(8) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    comment VARCHAR(2048) NULL,
+    repeat VARCHAR(128) NOT NULL CHECK (LENGTH(repeat) <= 128)
+);
+CREATE INDEX IF NOT EXISTS date_id ON scheduler (date);`),
+	},
+	{
+		version:     2,
+		description: "add algorithm of repeat",
+		up:          execSQL(`ALTER TABLE scheduler ADD COLUMN IF NOT EXISTS algorithm VARCHAR(16) NOT NULL DEFAULT '';`),
+	},
+	{
+		version:     3,
+		description: "add mode of repeat",
+		up:          execSQL(`ALTER TABLE scheduler ADD COLUMN IF NOT EXISTS mode VARCHAR(16) NOT NULL DEFAULT '';`),
+	},
+	{
+		version:     4,
+		description: "add time of day",
+		up:          execSQL(`ALTER TABLE scheduler ADD COLUMN IF NOT EXISTS time VARCHAR(5) NOT NULL DEFAULT '';`),
+	},
+	{
+		version:     5,
+		description: "add exception dates",
+		up:          execSQL(`ALTER TABLE scheduler ADD COLUMN IF NOT EXISTS exdates VARCHAR(287) NOT NULL DEFAULT '';`),
+	},
+	{
+		version:     6,
+		description: "create table login",
+		up:          execSQL(loginTable),
+	},
+	{
+		version:     7,
+		description: "create full-text search of tasks",
+		// PostgreSQL searches by 'ILIKE' (postgres.go), version is kept same as for SQLite
+		up: noop,
+	},
+	{
+		version:     8,
+		description: "create history of completions",
+		up: execSQL(`
+CREATE TABLE IF NOT EXISTS task_completions
+(
+    id SERIAL PRIMARY KEY,
+    task_id INTEGER NOT NULL,
+    date VARCHAR(8) NOT NULL,
+    time VARCHAR(5) NOT NULL DEFAULT '',
+    done_at VARCHAR(25) NOT NULL,
+    note VARCHAR(2048) NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS task_completions_task_id ON task_completions (task_id);`),
+	},
+}
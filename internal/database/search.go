@@ -0,0 +1,34 @@
+// search - full-text search of tasks in table 'scheduler_fts' of SQLite (FTS5), see (schema.go)
+package database
+
+import (
+	"strings"
+	"unicode"
+)
+
+// matchQuery - expression of FTS5 'MATCH' from word of search
+// every term is prefix and all terms are required: "купить мол" -> "купить"* "мол"*
+// 'field' - column of search (entity.FieldTitle or entity.FieldComment), empty - both
+// word without letters and digits -> empty
+func matchQuery(word, field string) string {
+	terms := strings.FieldsFunc(word, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	if len(terms) == 0 {
+		return ""
+	}
+	for i, term := range terms {
+		terms[i] = `"` + term + `"*`
+	}
+	if field != "" {
+		return "{" + field + "} : (" + strings.Join(terms, " ") + ")"
+	}
+	return strings.Join(terms, " ")
+}
+
+// searchJoin - only tasks matched by expression of placeholder %s (see 'matchQuery') with relevance 'found.score'
+// relevance is 'bm25' (less is better), term in title weighs twice as much as in comment
+const searchJoin = `
+         JOIN (SELECT rowid AS task_id, bm25(scheduler_fts, 2.0, 1.0) AS score
+               FROM scheduler_fts
+               WHERE scheduler_fts MATCH %s) AS found ON found.task_id = scheduler.id`
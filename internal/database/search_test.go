@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/config"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/services/entity"
+)
+
+func TestMatchQuery(t *testing.T) {
+	for _, test := range []struct {
+		word     string
+		field    string
+		expected string
+	}{
+		{"молоко", "", `"молоко"*`},
+		{"купить мол", "", `"купить"* "мол"*`},
+		{` "quote"  AND-or* `, "", `"quote"* "AND"* "or"*`},
+		{"100%", "", `"100"*`},
+		{"%_ ,", "", ""},
+		{"купить мол", entity.FieldTitle, `{title} : ("купить"* "мол"*)`},
+		{"%_ ,", entity.FieldComment, ""},
+	} {
+		assert.Equal(t, test.expected, matchQuery(test.word, test.field), test.word)
+	}
+}
+
+func TestSource_FullTextSearch(t *testing.T) {
+	asserts := assert.New(t)
+	requires := require.New(t)
+
+	db, err := InitDB(&config.Config{DataBaseDataSourceName: filepath.Join(t.TempDir(), "search.db")})
+	requires.NoError(err, "database_test: InitDB error")
+	defer db.Close()
+
+	ctx := context.Background()
+	s := NewSource(db)
+	ids := make(map[string]uint)
+	for _, task := range []model.TaskModel{
+		{Date: "30000101", Title: "Позвонить маме", Comment: "купить молоко по дороге"},
+		{Date: "30000105", Title: "Купить Молоко", Comment: "и хлеб"},
+		{Date: "30000103", Title: "Купить хлеб"},
+		{Date: "30000104", Title: "Молоток", Comment: "вернуть соседу"},
+		{Date: "30000102", Title: "Покупки"},
+	} {
+		id, err := s.SaveOneTask(ctx, task)
+		requires.NoError(err)
+		ids[task.Title] = id
+	}
+
+	titles := func(word string) []string {
+		tasks, err := s.FindTaskList(ctx, entity.NewTaskProperty(word, 10))
+		requires.NoError(err, word)
+		var res []string
+		for _, task := range tasks {
+			res = append(res, task.Title)
+		}
+		return res
+	}
+
+	// title weighs more than comment, short text more than long, then date,
+	// only full-text search: part of word inside of word is not found
+	asserts.Equal([]string{"Купить Молоко", "Позвонить маме"}, titles("МОЛОКО"), "case of cyrillic is ignored")
+	asserts.Equal([]string{"Купить хлеб", "Купить Молоко", "Позвонить маме"}, titles("куп"), "prefix of word, not inside of word")
+	asserts.Equal([]string{"Купить хлеб", "Купить Молоко"}, titles("купить хлеб"), "all words are required")
+	asserts.Equal([]string{"Молоток", "Купить Молоко", "Позвонить маме"}, titles("моло"), "prefix of word in title and comment")
+	asserts.Empty(titles("локо"), "without search of part of word by LIKE")
+	asserts.Empty(titles("%"), "word without letters and digits")
+	asserts.Empty(titles("кефир"))
+
+	// index follows changes of table
+	requires.NoError(s.NewDataTask(ctx, model.TaskModel{ID: ids["Молоток"], Date: "30000104", Title: "Отвёртка"}))
+	asserts.Equal([]string{"Отвёртка"}, titles("отвёртка"), "new title is found")
+	asserts.Empty(titles("соседу"), "old comment is not found")
+	requires.NoError(s.ExpirationTask(ctx, ids["Купить хлеб"]))
+	asserts.Equal([]string{"Купить Молоко"}, titles("хлеб"), "deleted task is not found")
+}
@@ -0,0 +1,92 @@
+// clock - source of "now" for services
+//
+// system clock - time.Now, used by default
+// debug clock  - "now" of server can be frozen or moved by offset (TODO_DEBUG_CLOCK=true),
+// only for tests and support investigations: month ends, leap years, end of series
+package clock
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/config"
+)
+
+// Clock - current time
+type Clock interface {
+	Now() time.Time
+}
+
+// New - debug clock if it is allowed in config, othercase system clock
+func New(cfg *config.Config) Clock {
+	if cfg.DebugClock {
+		return NewDebug(System{})
+	}
+	return System{}
+}
+
+// System - real time of server
+type System struct{}
+
+func (System) Now() time.Time {
+	return time.Now()
+}
+
+// Fixed - always the same moment
+type Fixed time.Time
+
+func (f Fixed) Now() time.Time {
+	return time.Time(f)
+}
+
+// Debug - clock with frozen time or offset from time of 'base'
+// safe for concurrent use
+type Debug struct {
+	base Clock
+
+	mu     sync.RWMutex
+	frozen time.Time
+	offset time.Duration
+}
+
+func NewDebug(base Clock) *Debug {
+	return &Debug{base: base}
+}
+
+// Now - frozen time if it is set, othercase time of 'base' + offset
+func (d *Debug) Now() time.Time {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if !d.frozen.IsZero() {
+		return d.frozen
+	}
+	return d.base.Now().Add(d.offset)
+}
+
+// Freeze - "now" stops at 't', offset is cleared
+func (d *Debug) Freeze(t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.frozen = t
+	d.offset = 0
+}
+
+// Shift - "now" goes with time of 'base' moved by 'offset', frozen time is cleared
+func (d *Debug) Shift(offset time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.frozen = time.Time{}
+	d.offset = offset
+}
+
+// Reset - "now" is time of 'base'
+func (d *Debug) Reset() {
+	d.Shift(0)
+}
+
+// State - is time frozen and offset from time of 'base'
+func (d *Debug) State() (bool, time.Duration) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return !d.frozen.IsZero(), d.offset
+}
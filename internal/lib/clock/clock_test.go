@@ -0,0 +1,44 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/config"
+)
+
+func TestNew(t *testing.T) {
+	asserts := assert.New(t)
+
+	asserts.IsType(System{}, New(&config.Config{}), "system clock by default")
+	asserts.IsType(&Debug{}, New(&config.Config{DebugClock: true}), "debug clock from config")
+}
+
+func TestDebug(t *testing.T) {
+	asserts := assert.New(t)
+
+	base := time.Date(2024, 2, 28, 23, 0, 0, 0, time.UTC)
+	debug := NewDebug(Fixed(base))
+	asserts.Equal(base, debug.Now(), "without changes - time of base")
+
+	debug.Shift(2 * time.Hour)
+	asserts.Equal(time.Date(2024, 2, 29, 1, 0, 0, 0, time.UTC), debug.Now(), "offset")
+	frozen, offset := debug.State()
+	asserts.False(frozen)
+	asserts.Equal(2*time.Hour, offset)
+
+	moment := time.Date(2023, 12, 31, 23, 59, 0, 0, time.UTC)
+	debug.Freeze(moment)
+	asserts.Equal(moment, debug.Now(), "frozen time")
+	frozen, offset = debug.State()
+	asserts.True(frozen)
+	asserts.Zero(offset, "offset is cleared by freeze")
+
+	debug.Reset()
+	asserts.Equal(base, debug.Now(), "after reset - time of base")
+	frozen, offset = debug.State()
+	asserts.False(frozen)
+	asserts.Zero(offset)
+}
@@ -0,0 +1,553 @@
+// describe - text of !_repeat_! string for user in Russian or English
+//
+// example: "m -1,15 1,4,7,10" -> "on the 15th and the last day of Jan, Apr, Jul and Oct"
+package nextdate
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/pkg/common"
+)
+
+// ErrNextDateUnknownLanguage - language of description is not supported
+var ErrNextDateUnknownLanguage = errors.New("unknown language")
+
+// languages of description
+const (
+	LanguageEnglish = "en"
+	LanguageRussian = "ru"
+)
+
+// language - rules of sentence for one language
+type language interface {
+	sentence(d description) string
+}
+
+var languages = map[string]language{
+	LanguageEnglish: english{},
+	LanguageRussian: russian{},
+}
+
+// description - rule of custom grammar or RRULE in one form
+//
+// days of month, ordinal weekdays and weekdays are sorted, empty - not used
+type description struct {
+	// unit - hour, day, businessDay, weak, month, year
+	unit  byte
+	every int
+
+	// window - hours of day for unit 'hour'
+	window timeWindow
+
+	weekdays    []time.Weekday
+	monthDays   []int
+	nthWeekdays []ordinalWeekday
+	months      []time.Month
+
+	end   endCondition
+	shift int
+}
+
+// IsLanguage - description is supported in language 'lang'
+func IsLanguage(lang string) bool {
+	_, ex := languages[lang]
+	return ex
+}
+
+// Describe - sentence of valid !_repeat_! string (custom grammar or RRULE) in language 'lang'
+// wrong !_repeat_! string -> *ParseError
+func Describe(repeat, lang string) (string, error) {
+	words, ex := languages[lang]
+	if !ex {
+		return "", ErrNextDateUnknownLanguage
+	}
+	if IsRRule(repeat) {
+		rule, err := parseRRule(repeat)
+		if err != nil {
+			return "", err
+		}
+		return words.sentence(rule.description()), nil
+	}
+	rule, err := ParseRule(repeat)
+	if err != nil {
+		return "", err
+	}
+	return words.sentence(rule.description()), nil
+}
+
+func (r *Rule) description() description {
+	d := description{unit: r.flag, every: r.every, end: r.end, shift: r.shift}
+	switch r.flag {
+	case day, businessDay:
+		d.every = r.days
+	case hour:
+		d.every, d.window = r.hours, r.window
+	case weak:
+		for weekday := range r.weekdays {
+			if r.weekdays[weekday] {
+				d.weekdays = append(d.weekdays, time.Weekday(weekday))
+			}
+		}
+	case month:
+		for monthDay := range r.monthDays {
+			d.monthDays = append(d.monthDays, monthDay)
+		}
+	case nthWeekday:
+		d.unit = month
+		for nth := range r.nthWeekdays {
+			d.nthWeekdays = append(d.nthWeekdays, nth)
+		}
+	}
+	d.months = monthsOf(r.months)
+	d.sort()
+	return d
+}
+
+func (r rrule) description() description {
+	d := description{every: r.interval, end: r.end}
+	switch r.freq {
+	case freqDaily:
+		d.unit = day
+	case freqWeekly:
+		d.unit = weak
+	case freqMonthly:
+		d.unit = month
+	default:
+		d.unit = year
+	}
+	for _, byDay := range r.byDay {
+		if byDay.ordinal == 0 {
+			d.weekdays = append(d.weekdays, byDay.weekday)
+		} else {
+			d.nthWeekdays = append(d.nthWeekdays, byDay)
+		}
+	}
+	d.monthDays = append(d.monthDays, r.byMonthDay...)
+	d.months = monthsOf(r.byMonth)
+	d.sort()
+	return d
+}
+
+// monthsOf - index of 'months' is time.Month, nil - every month
+func monthsOf(months []bool) []time.Month {
+	var res []time.Month
+	for m := time.January; int(m) < len(months); m++ {
+		if months[m] {
+			res = append(res, m)
+		}
+	}
+	return res
+}
+
+// sort - monday first, days of month: positive ascending, after negative from end of month
+func (d *description) sort() {
+	weekdayIndex := func(weekday time.Weekday) int {
+		return (int(weekday) + 6) % 7
+	}
+	ordinalLess := func(a, b int) bool {
+		if (a < 0) != (b < 0) {
+			return a > 0
+		}
+		return common.Abs(a) < common.Abs(b)
+	}
+	sort.Slice(d.weekdays, func(i, j int) bool {
+		return weekdayIndex(d.weekdays[i]) < weekdayIndex(d.weekdays[j])
+	})
+	sort.Slice(d.monthDays, func(i, j int) bool {
+		return ordinalLess(d.monthDays[i], d.monthDays[j])
+	})
+	sort.Slice(d.nthWeekdays, func(i, j int) bool {
+		a, b := d.nthWeekdays[i], d.nthWeekdays[j]
+		if a.ordinal != b.ordinal {
+			return ordinalLess(a.ordinal, b.ordinal)
+		}
+		return weekdayIndex(a.weekday) < weekdayIndex(b.weekday)
+	})
+}
+
+// joinList - "a", "a and b", "a, b and c"
+func joinList(items []string, and string) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " " + and + " " + items[len(items)-1]
+}
+
+type english struct{}
+
+var (
+	enUnits = map[byte][2]string{
+		hour:        {"hour", "hours"},
+		day:         {"day", "days"},
+		businessDay: {"working day", "working days"},
+		weak:        {"week", "weeks"},
+		month:       {"month", "months"},
+		year:        {"year", "years"},
+	}
+	enOrdinals = []string{"", "first", "second", "third", "fourth", "fifth"}
+)
+
+func (e english) sentence(d description) string {
+	var text string
+	switch {
+	case len(d.monthDays) > 0:
+		days := make([]string, 0, len(d.monthDays))
+		for _, monthDay := range d.monthDays {
+			days = append(days, e.monthDay(monthDay))
+		}
+		text = "on " + joinList(days, "and") + " of " + e.period(d)
+		if len(d.nthWeekdays)+len(d.weekdays) > 0 {
+			text += ", if it is " + e.filter(d)
+		}
+	case len(d.nthWeekdays) > 0:
+		// weekdays of RRULE BYDAY are added to ordinal weekdays
+		days := make([]string, 0, len(d.nthWeekdays)+len(d.weekdays))
+		for _, nth := range d.nthWeekdays {
+			days = append(days, e.nthWeekday(nth))
+		}
+		for _, weekday := range d.weekdays {
+			days = append(days, "every "+weekday.String())
+		}
+		text = "on " + joinList(days, "and") + " of " + e.period(d)
+	case len(d.weekdays) > 0:
+		days := make([]string, 0, len(d.weekdays))
+		for _, weekday := range d.weekdays {
+			days = append(days, weekday.String())
+		}
+		text = "every " + joinList(days, "and")
+		if d.unit == weak && d.every > 1 {
+			text = e.every(d.unit, d.every) + " on " + joinList(days, "and")
+		}
+		if len(d.months) > 0 {
+			text += " in " + e.months(d.months)
+		}
+		if d.unit != weak && d.every > 1 {
+			text += ", " + e.every(d.unit, d.every)
+		}
+	default:
+		text = e.every(d.unit, d.every)
+		if len(d.months) > 0 {
+			text += " in " + e.months(d.months)
+		}
+		if d.window.isSet() {
+			text += " from " + formatMinutes(d.window.from) + " to " + formatMinutes(d.window.to)
+		}
+	}
+	return text + e.tail(d)
+}
+
+// every - "every day", "every 3 days"
+func (e english) every(unit byte, every int) string {
+	if every == 1 {
+		return "every " + enUnits[unit][0]
+	}
+	return "every " + strconv.Itoa(every) + " " + enUnits[unit][1]
+}
+
+// period - months or every month (months)
+func (e english) period(d description) string {
+	if len(d.months) > 0 {
+		text := e.months(d.months)
+		if d.every > 1 {
+			text += ", " + e.every(d.unit, d.every)
+		}
+		return text
+	}
+	if d.unit == month {
+		if d.every == 1 {
+			return "every month"
+		}
+		return e.every(month, d.every)
+	}
+	text := "every month"
+	if d.every > 1 {
+		text += ", " + e.every(d.unit, d.every)
+	}
+	return text
+}
+
+func (e english) months(months []time.Month) string {
+	names := make([]string, 0, len(months))
+	for _, m := range months {
+		names = append(names, m.String()[:3])
+	}
+	return joinList(names, "and")
+}
+
+// monthDay - "the 1st", "the 22nd", "the last day", "the 3rd to last day"
+func (e english) monthDay(monthDay int) string {
+	if monthDay == lastDayMonth {
+		return "the last day"
+	}
+	if monthDay < 0 {
+		return "the " + e.suffix(-monthDay) + " to last day"
+	}
+	return "the " + e.suffix(monthDay)
+}
+
+// nthWeekday - "the second Tuesday", "the last Friday", "the second to last Monday"
+func (e english) nthWeekday(nth ordinalWeekday) string {
+	switch {
+	case nth.ordinal == -1:
+		return "the last " + nth.weekday.String()
+	case nth.ordinal < 0:
+		return "the " + enOrdinals[-nth.ordinal] + " to last " + nth.weekday.String()
+	}
+	return "the " + enOrdinals[nth.ordinal] + " " + nth.weekday.String()
+}
+
+// filter - weekdays which limit days of month (RRULE BYMONTHDAY with BYDAY)
+func (e english) filter(d description) string {
+	days := make([]string, 0, len(d.weekdays)+len(d.nthWeekdays))
+	for _, weekday := range d.weekdays {
+		days = append(days, weekday.String())
+	}
+	for _, nth := range d.nthWeekdays {
+		days = append(days, e.nthWeekday(nth))
+	}
+	return joinList(days, "or")
+}
+
+func (e english) suffix(n int) string {
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+// tail - end condition and shift to workday
+func (e english) tail(d description) string {
+	var text string
+	if !d.end.until.IsZero() {
+		text += ", until " + d.end.until.Format("January 2, 2006")
+	}
+	if d.end.count == 1 {
+		text += ", once"
+	} else if d.end.count > 1 {
+		text += ", " + strconv.Itoa(d.end.count) + " times"
+	}
+	switch d.shift {
+	case 1:
+		text += ", moved to the next working day if it is a day off"
+	case -1:
+		text += ", moved to the previous working day if it is a day off"
+	}
+	return text
+}
+
+type russian struct{}
+
+var (
+	// ruUnits - "раз в N ...": one, few, many
+	ruUnits = map[byte][3]string{
+		hour:        {"час", "часа", "часов"},
+		day:         {"день", "дня", "дней"},
+		businessDay: {"рабочий день", "рабочих дня", "рабочих дней"},
+		weak:        {"неделю", "недели", "недель"},
+		month:       {"месяц", "месяца", "месяцев"},
+		year:        {"год", "года", "лет"},
+	}
+	ruEvery = map[byte]string{
+		hour:        "каждый час",
+		day:         "каждый день",
+		businessDay: "каждый рабочий день",
+		weak:        "каждую неделю",
+		month:       "каждый месяц",
+		year:        "каждый год",
+	}
+
+	// index is time.Weekday
+	ruWeekdays       = []string{"воскресенье", "понедельник", "вторник", "среда", "четверг", "пятница", "суббота"}
+	ruWeekdaysDative = []string{"воскресеньям", "понедельникам", "вторникам", "средам", "четвергам", "пятницам", "субботам"}
+
+	// ruWeekdaysGender - 0 - masculine, 1 - feminine, 2 - neuter, index is time.Weekday
+	ruWeekdaysGender = []int{2, 0, 0, 1, 0, 1, 1}
+
+	// ruOrdinals - index is ordinal, forms by gender
+	ruOrdinals = [][3]string{
+		{},
+		{"первый", "первая", "первое"},
+		{"второй", "вторая", "второе"},
+		{"третий", "третья", "третье"},
+		{"четвёртый", "четвёртая", "четвёртое"},
+		{"пятый", "пятая", "пятое"},
+	}
+	ruEach        = [3]string{"каждый", "каждая", "каждое"}
+	ruLast        = [3]string{"последний", "последняя", "последнее"}
+	ruPenultimate = [3]string{"предпоследний", "предпоследняя", "предпоследнее"}
+
+	// index is time.Month
+	ruMonthsGenitive = []string{"", "января", "февраля", "марта", "апреля", "мая", "июня",
+		"июля", "августа", "сентября", "октября", "ноября", "декабря"}
+	ruMonthsPrepositional = []string{"", "январе", "феврале", "марте", "апреле", "мае", "июне",
+		"июле", "августе", "сентябре", "октябре", "ноябре", "декабре"}
+)
+
+func (r russian) sentence(d description) string {
+	var text string
+	switch {
+	case len(d.monthDays) > 0:
+		days := make([]string, 0, len(d.monthDays))
+		for _, monthDay := range d.monthDays {
+			days = append(days, r.monthDay(monthDay))
+		}
+		text = joinList(days, "и") + " числа" + r.period(d)
+		if len(d.nthWeekdays)+len(d.weekdays) > 0 {
+			text += ", если это " + r.filter(d)
+		}
+	case len(d.nthWeekdays) > 0:
+		// weekdays of RRULE BYDAY are added to ordinal weekdays
+		days := make([]string, 0, len(d.nthWeekdays)+len(d.weekdays))
+		for _, nth := range d.nthWeekdays {
+			days = append(days, r.nthWeekday(nth))
+		}
+		for _, weekday := range d.weekdays {
+			days = append(days, ruEach[ruWeekdaysGender[weekday]]+" "+ruWeekdays[weekday])
+		}
+		text = joinList(days, "и") + r.period(d)
+	case len(d.weekdays) > 0:
+		days := make([]string, 0, len(d.weekdays))
+		for _, weekday := range d.weekdays {
+			days = append(days, ruWeekdaysDative[weekday])
+		}
+		text = "по " + joinList(days, "и")
+		if d.unit == weak && d.every > 1 {
+			text = r.every(d.unit, d.every) + " " + text
+		}
+		if len(d.months) > 0 {
+			text += " " + r.months(d.months)
+		}
+		if d.unit != weak && d.every > 1 {
+			text += ", " + r.every(d.unit, d.every)
+		}
+	default:
+		text = r.every(d.unit, d.every)
+		if len(d.months) > 0 {
+			text += " " + r.months(d.months)
+		}
+		if d.window.isSet() {
+			text += " с " + formatMinutes(d.window.from) + " до " + formatMinutes(d.window.to)
+		}
+	}
+	return text + r.tail(d)
+}
+
+// every - "каждый день", "раз в 3 дня"
+func (r russian) every(unit byte, every int) string {
+	if every == 1 {
+		return ruEvery[unit]
+	}
+	return "раз в " + strconv.Itoa(every) + " " + r.plural(every, ruUnits[unit])
+}
+
+// plural - form of word after number: 1 день, 2 дня, 5 дней, 21 день
+func (r russian) plural(n int, forms [3]string) string {
+	switch {
+	case n%100 >= 11 && n%100 <= 14:
+		return forms[2]
+	case n%10 == 1:
+		return forms[0]
+	case n%10 >= 2 && n%10 <= 4:
+		return forms[1]
+	}
+	return forms[2]
+}
+
+// period - genitive: " января и апреля", " каждого месяца" or ", раз в 3 месяца"
+func (r russian) period(d description) string {
+	text := " каждого месяца"
+	if len(d.months) > 0 {
+		names := make([]string, 0, len(d.months))
+		for _, m := range d.months {
+			names = append(names, ruMonthsGenitive[m])
+		}
+		text = " " + joinList(names, "и")
+	} else if d.unit == month && d.every > 1 {
+		text = ""
+	}
+	if d.every > 1 {
+		text += ", " + r.every(d.unit, d.every)
+	}
+	return text
+}
+
+// months - "в январе и апреле"
+func (r russian) months(months []time.Month) string {
+	names := make([]string, 0, len(months))
+	for _, m := range months {
+		names = append(names, ruMonthsPrepositional[m])
+	}
+	return "в " + joinList(names, "и")
+}
+
+// monthDay - genitive: "15-го", "последнего", "3-го с конца"
+func (r russian) monthDay(monthDay int) string {
+	switch {
+	case monthDay == lastDayMonth:
+		return "последнего"
+	case monthDay == penultimateDayMonth:
+		return "предпоследнего"
+	case monthDay < 0:
+		return strconv.Itoa(-monthDay) + "-го с конца"
+	}
+	return strconv.Itoa(monthDay) + "-го"
+}
+
+// nthWeekday - nominative: "второй вторник", "последняя пятница", "третье с конца воскресенье"
+func (r russian) nthWeekday(nth ordinalWeekday) string {
+	gender := ruWeekdaysGender[nth.weekday]
+	var ordinal string
+	switch {
+	case nth.ordinal == -1:
+		ordinal = ruLast[gender]
+	case nth.ordinal == -2:
+		ordinal = ruPenultimate[gender]
+	case nth.ordinal < 0:
+		ordinal = ruOrdinals[-nth.ordinal][gender] + " с конца"
+	default:
+		ordinal = ruOrdinals[nth.ordinal][gender]
+	}
+	return ordinal + " " + ruWeekdays[nth.weekday]
+}
+
+// filter - weekdays which limit days of month (RRULE BYMONTHDAY with BYDAY)
+func (r russian) filter(d description) string {
+	days := make([]string, 0, len(d.weekdays)+len(d.nthWeekdays))
+	for _, weekday := range d.weekdays {
+		days = append(days, ruWeekdays[weekday])
+	}
+	for _, nth := range d.nthWeekdays {
+		days = append(days, r.nthWeekday(nth))
+	}
+	return joinList(days, "или")
+}
+
+// tail - end condition and shift to workday
+func (r russian) tail(d description) string {
+	var text string
+	if until := d.end.until; !until.IsZero() {
+		text += ", до " + strconv.Itoa(until.Day()) + " " + ruMonthsGenitive[until.Month()] + " " + strconv.Itoa(until.Year())
+	}
+	if count := d.end.count; count > 0 {
+		text += ", " + strconv.Itoa(count) + " " + r.plural(count, [3]string{"раз", "раза", "раз"})
+	}
+	switch d.shift {
+	case 1:
+		text += ", с переносом на следующий рабочий день, если выпадает на выходной"
+	case -1:
+		text += ", с переносом на предыдущий рабочий день, если выпадает на выходной"
+	}
+	return text
+}
@@ -0,0 +1,69 @@
+package nextdate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Describe(t *testing.T) {
+	asserts := assert.New(t)
+	requires := require.New(t)
+
+	dataForDescribe := []struct {
+		repeat string
+		en     string
+		ru     string
+	}{
+		{"d 1", "every day", "каждый день"},
+		{"d 21", "every 21 days", "раз в 21 день"},
+		{"b 3", "every 3 working days", "раз в 3 рабочих дня"},
+		{"h 1", "every hour", "каждый час"},
+		{"h 4 09:00-18:00", "every 4 hours from 09:00 to 18:00", "раз в 4 часа с 09:00 до 18:00"},
+		{"w 4,1", "every Monday and Thursday", "по понедельникам и четвергам"},
+		{"w/2 7,1", "every 2 weeks on Monday and Sunday", "раз в 2 недели по понедельникам и воскресеньям"},
+		{
+			"m -1,15 1,4,7,10",
+			"on the 15th and the last day of Jan, Apr, Jul and Oct",
+			"15-го и последнего числа января, апреля, июля и октября",
+		},
+		{"m/3 1,22", "on the 1st and the 22nd of every 3 months", "1-го и 22-го числа, раз в 3 месяца"},
+		{"y/5", "every 5 years", "раз в 5 лет"},
+		{
+			"n 2#2,5#-1",
+			"on the second Tuesday and the last Friday of every month",
+			"второй вторник и последняя пятница каждого месяца",
+		},
+		{"n 7#-2 12", "on the second to last Sunday of Dec", "предпоследнее воскресенье декабря"},
+		{"d 7 count 5", "every 7 days, 5 times", "раз в 7 дней, 5 раз"},
+		{"y until 20300315", "every year, until March 15, 2030", "каждый год, до 15 марта 2030"},
+		{
+			"m 13 shift prev",
+			"on the 13th of every month, moved to the previous working day if it is a day off",
+			"13-го числа каждого месяца, с переносом на предыдущий рабочий день, если выпадает на выходной",
+		},
+		{"FREQ=WEEKLY;INTERVAL=3;BYDAY=FR", "every 3 weeks on Friday", "раз в 3 недели по пятницам"},
+		{"FREQ=MONTHLY;BYMONTHDAY=13;BYDAY=FR", "on the 13th of every month, if it is Friday", "13-го числа каждого месяца, если это пятница"},
+		{"FREQ=MONTHLY;BYMONTHDAY=-3", "on the 3rd to last day of every month", "3-го с конца числа каждого месяца"},
+		{"FREQ=YEARLY;BYMONTH=3;BYMONTHDAY=8", "on the 8th of Mar", "8-го числа марта"},
+		{"FREQ=DAILY;BYMONTH=1,2", "every day in Jan and Feb", "каждый день в январе и феврале"},
+	}
+
+	for _, test := range dataForDescribe {
+		en, err := Describe(test.repeat, LanguageEnglish)
+		requires.NoError(err, test.repeat)
+		asserts.Equal(test.en, en, test.repeat)
+
+		ru, err := Describe(test.repeat, LanguageRussian)
+		requires.NoError(err, test.repeat)
+		asserts.Equal(test.ru, ru, test.repeat)
+	}
+
+	_, err := Describe("d 1", "de")
+	asserts.ErrorIs(err, ErrNextDateUnknownLanguage)
+	asserts.False(IsLanguage("de"))
+
+	_, err = Describe("m 32", LanguageEnglish)
+	asserts.ErrorIs(err, ErrNextDateWrongRepeat)
+}
@@ -0,0 +1,144 @@
+// exdate - exception dates of series: occurrences which are skipped
+//
+// stored form - "20241231,20250107": dates in format '20060102' separated by comma, sorted without repeats
+// exception date is not counted as done: end condition "count" is not decreased
+// rule 'h' - all occurrences of exception date are skipped
+package nextdate
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+)
+
+var (
+	// ErrNextDateInvalidExdate - exception date is not in format '20060102'
+	ErrNextDateInvalidExdate = errors.New("invalid exception date")
+
+	// ErrNextDateTooManyExdates - more than 'MaxExdates' exception dates
+	ErrNextDateTooManyExdates = errors.New("too many exception dates")
+)
+
+// MaxExdates - limit of exception dates of one task
+const MaxExdates = 32
+
+// NormalizeExdates - check dates and write list in stored form (call from TaskDecode)
+func NormalizeExdates(dates []string) (string, error) {
+	if len(dates) > MaxExdates {
+		return "", ErrNextDateTooManyExdates
+	}
+	for _, date := range dates {
+		if _, err := time.Parse(model.DateFormat, date); err != nil {
+			return "", ErrNextDateInvalidExdate
+		}
+	}
+	sorted := slices.Clone(dates)
+	slices.Sort(sorted)
+	return strings.Join(slices.Compact(sorted), ","), nil
+}
+
+// SplitExdates - list of exception dates from stored form, empty - nil
+func SplitExdates(exdates string) []string {
+	if exdates == "" {
+		return nil
+	}
+	return strings.Split(exdates, ",")
+}
+
+// isExdate - 'date' is in stored list 'exdates'
+func isExdate(exdates, date string) bool {
+	return exdates != "" && slices.Contains(SplitExdates(exdates), date)
+}
+
+// Except - algorithm 'nextDate' which skips exception dates
+//
+// every skipped date is one of 'exdates' and next dates grow,
+// so 'nextDate' is called not more than number of exception dates + 1
+func Except(nextDate NextDateFunc, exdates string) NextDateFunc {
+	if exdates == "" {
+		return nextDate
+	}
+	skip := SplitExdates(exdates)
+	return func(now time.Time, dstart, repeat string) (string, error) {
+		for range len(skip) {
+			date, err := nextDate(now, dstart, repeat)
+			if err != nil || !slices.Contains(skip, date) {
+				return date, err
+			}
+			if now, err = time.Parse(model.DateFormat, date); err != nil {
+				return "", ErrNextDateUnexpectedBehavior
+			}
+		}
+		return nextDate(now, dstart, repeat)
+	}
+}
+
+// ExceptDate - 'date' of series is exception date -> next date after it by 'nextDate'
+//
+// 'dstart' - start of series from which 'date' is found, phase of INTERVAL is kept from it (not from 'date')
+// 'nextDate' - algorithm with exception dates see 'Except'
+// 'repeat' - without end condition "count" see 'WithoutCountOf': occurrence at exception date is not done
+func ExceptDate(nextDate NextDateFunc, dstart, date, repeat, exdates string) (string, error) {
+	if !isExdate(exdates, date) {
+		return date, nil
+	}
+	dateToTime, err := time.Parse(model.DateFormat, date)
+	if err != nil {
+		return "", ErrNextDateInvalidDate
+	}
+	return nextDate(dateToTime, dstart, repeat)
+}
+
+// withoutCount - !_repeat_! string without end condition "count", other rules return without change
+//
+// example: "d 3 count 2" -> "d 3", "FREQ=DAILY;COUNT=2" -> "FREQ=DAILY"
+func withoutCount(repeat string) (string, error) {
+	if IsRRule(repeat) {
+		rule, err := parseRRule(repeat)
+		if err != nil {
+			return "", err
+		}
+		if rule.end.count == 0 {
+			return repeat, nil
+		}
+		rule.end.count = 0
+		return rule.String(), nil
+	}
+	rule, err := ParseRule(repeat)
+	if err != nil {
+		return "", err
+	}
+	if rule.end.count == 0 {
+		return repeat, nil
+	}
+	fields := strings.Split(repeat, " ")
+	for i := len(fields) - 2; i > 0; i-- {
+		if fields[i] == count {
+			fields = slices.Delete(fields, i, i+2)
+			break
+		}
+	}
+	return strings.Join(fields, " "), nil
+}
+
+// ExceptTime - occurrence of rule 'h' at exception date -> first occurrence of next day without exception
+func ExceptTime(date, clock, repeat, exdates string) (string, string, error) {
+	for range len(SplitExdates(exdates)) {
+		if !isExdate(exdates, date) {
+			return date, clock, nil
+		}
+		day, err := time.Parse(model.DateFormat, date)
+		if err != nil {
+			return "", "", ErrNextDateInvalidDate
+		}
+		// last minute of exception date
+		endOfDay := day.Add(24*time.Hour - time.Minute)
+		if date, clock, err = NextTime(endOfDay, date, clock, repeat); err != nil {
+			return "", "", err
+		}
+	}
+	return date, clock, nil
+}
@@ -0,0 +1,159 @@
+package nextdate
+
+import (
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NormalizeExdates(t *testing.T) {
+	asserts := assert.New(t)
+
+	exdates, err := NormalizeExdates([]string{"20250107", "20241231", "20250107"})
+	asserts.NoError(err)
+	asserts.Equal("20241231,20250107", exdates, "sorted without repeats")
+
+	exdates, err = NormalizeExdates(nil)
+	asserts.NoError(err)
+	asserts.Empty(exdates)
+
+	_, err = NormalizeExdates([]string{"2024-12-31"})
+	asserts.ErrorIs(err, ErrNextDateInvalidExdate)
+
+	_, err = NormalizeExdates(make([]string, MaxExdates+1))
+	asserts.ErrorIs(err, ErrNextDateTooManyExdates)
+
+	asserts.Equal([]string{"20241231", "20250107"}, SplitExdates("20241231,20250107"))
+	asserts.Nil(SplitExdates(""))
+}
+
+func Test_Except(t *testing.T) {
+	asserts := assert.New(t)
+
+	now := time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)
+
+	dataForExcept := []struct {
+		dstart  string
+		repeat  string
+		exdates string
+		date    string
+		err     error
+		msg     string
+	}{
+		{
+			dstart:  "20241203",
+			repeat:  "w 2",
+			exdates: "20241231",
+			date:    "20250107",
+			msg:     `weekly without one occurrence`,
+		},
+		{
+			dstart:  "20241203",
+			repeat:  "w 2",
+			exdates: "20241231,20250107,20250114",
+			date:    "20250121",
+			msg:     `several exception dates in a row`,
+		},
+		{
+			dstart:  "20241203",
+			repeat:  "w 2",
+			exdates: "20241230",
+			date:    "20241231",
+			msg:     `exception date is not occurrence`,
+		},
+		{
+			dstart:  "20241203",
+			repeat:  "w 2 until 20250105",
+			exdates: "20241231",
+			err:     ErrNextDateSeriesEnded,
+			msg:     `last occurrence is exception date`,
+		},
+		{
+			dstart:  "20241201",
+			repeat:  "FREQ=MONTHLY;BYMONTHDAY=1",
+			exdates: "20250101",
+			date:    "20250201",
+			msg:     `RRULE`,
+		},
+	}
+
+	for i, test := range dataForExcept {
+		log.Printf("\t%d %s", i+1, test.msg)
+
+		nextDate := NextDate
+		if IsRRule(test.repeat) {
+			nextDate = RRule
+		}
+		date, err := Except(nextDate, test.exdates)(now, test.dstart, test.repeat)
+		if test.err != nil {
+			asserts.ErrorIs(err, test.err, test.msg)
+			continue
+		}
+		asserts.NoError(err, test.msg)
+		asserts.Equal(test.date, date, test.msg)
+	}
+}
+
+func Test_ExceptDate(t *testing.T) {
+	asserts := assert.New(t)
+
+	exdates := "20241231,20250107"
+	nextDate := Except(NextDate, exdates)
+
+	date, err := ExceptDate(nextDate, "20241224", "20241224", "w 2", exdates)
+	asserts.NoError(err)
+	asserts.Equal("20241224", date, "date is not exception")
+
+	date, err = ExceptDate(nextDate, "20241231", "20241231", "w 2", exdates)
+	asserts.NoError(err)
+	asserts.Equal("20250114", date, "date and next occurrence are exceptions")
+
+	// phase of series is kept from start, not from exception date
+	date, err = ExceptDate(Except(NextDate, "20250106"), "20241231", "20250106", "d 3", "20250106")
+	asserts.NoError(err)
+	asserts.Equal("20250109", date, "d 3 from start")
+	date, err = ExceptDate(Except(RRule, "20250107"), "20241224", "20250107",
+		"FREQ=WEEKLY;INTERVAL=2;BYDAY=TU", "20250107")
+	asserts.NoError(err)
+	asserts.Equal("20250121", date, "INTERVAL from start")
+}
+
+func Test_withoutCount(t *testing.T) {
+	asserts := assert.New(t)
+
+	for _, test := range []struct {
+		repeat   string
+		expected string
+	}{
+		{"d 3 count 2", "d 3"},
+		{"w 1,3 count 1 shift next", "w 1,3 shift next"},
+		{"d 3 until 20250101", "d 3 until 20250101"},
+		{"FREQ=DAILY;COUNT=1", "FREQ=DAILY"},
+		{"FREQ=DAILY", "FREQ=DAILY"},
+	} {
+		repeat, err := withoutCount(test.repeat)
+		asserts.NoError(err, test.repeat)
+		asserts.Equal(test.expected, repeat, test.repeat)
+	}
+	_, err := withoutCount("k 1")
+	asserts.ErrorIs(err, ErrNextDateWrongRepeat)
+}
+
+func Test_ExceptTime(t *testing.T) {
+	asserts := assert.New(t)
+
+	date, clock, err := ExceptTime("20241231", "13:00", "h 4 09:00-18:00", "20241231,20250101")
+	asserts.NoError(err)
+	asserts.Equal("20250102", date, "all occurrences of exception dates are skipped")
+	asserts.Equal("09:00", clock, "start of window")
+
+	date, clock, err = ExceptTime("20241230", "13:00", "h 4 09:00-18:00", "20241231")
+	asserts.NoError(err)
+	asserts.Equal("20241230", date, "date is not exception")
+	asserts.Equal("13:00", clock)
+
+	_, _, err = ExceptTime("20241231", "17:00", "h 4 09:00-18:00 until 20241231", "20241231")
+	asserts.ErrorIs(err, ErrNextDateSeriesEnded)
+}
@@ -0,0 +1,195 @@
+// hourly - time of day for rule with flag 'h'
+//
+// "h 4"             - every 4 hours from time of first occurrence
+// "h 4 09:00-18:00" - every 4 hours of window: 09:00, 13:00, 17:00 every day
+//
+// algorithms of type 'NextDateFunc' work only with date, next time of rule 'h' is found here
+// time of 'now' is taken by wall clock, date and time of task have no time zone
+package nextdate
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+	"github.com/Ekvo/yandex-practicum-go-final-project/pkg/common"
+)
+
+// ErrNextDateInvalidTime - time of task is not in format '15:04'
+var ErrNextDateInvalidTime = errors.New("invalid time")
+
+const (
+	minHour = 1
+	maxHour = 23
+
+	minutesPerHour = 60
+)
+
+// timeWindow - hours of day for flag 'h' in minutes from midnight
+// zero value - whole day
+type timeWindow struct {
+	from int
+	to   int
+}
+
+func (w timeWindow) isSet() bool {
+	return w.to != 0
+}
+
+// minutesOfDay - time in format '15:04' to minutes from midnight
+func minutesOfDay(text string) (int, bool) {
+	if len(text) != len(model.TimeFormat) || text[2] != ':' {
+		return 0, false
+	}
+	hours, ok := number(text[:2])
+	if !ok || hours > 23 {
+		return 0, false
+	}
+	minutes, ok := number(text[3:])
+	if !ok || minutes >= minutesPerHour {
+		return 0, false
+	}
+	return hours*minutesPerHour + minutes, true
+}
+
+// formatMinutes - minutes from midnight in format '15:04'
+func formatMinutes(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/minutesPerHour, minutes%minutesPerHour)
+}
+
+// IsHourly - !_repeat_! string of custom grammar with flag 'h'
+func IsHourly(repeat string) bool {
+	if IsRRule(repeat) {
+		return false
+	}
+	rule, err := ParseRule(repeat)
+	return err == nil && rule.flag == hour
+}
+
+// StartTime - first occurrence of rule 'h' not before 'now'
+// occurrence 'dstart' 'dtime' is not before 'now' -> it, othercase next occurrence after 'now'
+// empty 'dtime' - start of window or 00:00
+func StartTime(now time.Time, dstart, dtime, repeat string) (string, string, error) {
+	rule, start, err := parseHourly(dstart, dtime, repeat)
+	if err != nil {
+		return "", "", err
+	}
+	now = wallClock(now)
+	if start.Before(now) {
+		return rule.nextOccurrence(start, now)
+	}
+	return start.Format(model.DateFormat), start.Format(model.TimeFormat), nil
+}
+
+// NextTime - date and time of rule 'h' after 'now'
+//
+// 'dstart' 'dtime' is counted as the first occurrence of the series,
+// if end condition does not allow a next occurrence -> ErrNextDateSeriesEnded
+func NextTime(now time.Time, dstart, dtime, repeat string) (string, string, error) {
+	rule, start, err := parseHourly(dstart, dtime, repeat)
+	if err != nil {
+		return "", "", err
+	}
+	now = wallClock(now)
+	if now.Before(start) {
+		now = start
+	}
+	return rule.nextOccurrence(start, now)
+}
+
+// NextTimeAfterDone - date and time of rule 'h' restarted from moment of completion 'done'
+// next occurrence out of window -> start of window
+func NextTimeAfterDone(done time.Time, repeat string) (string, string, error) {
+	rule, err := ParseRule(repeat)
+	if err != nil {
+		return "", "", err
+	}
+	if rule.flag != hour {
+		return "", "", ErrNextDateWrongRepeat
+	}
+	done = wallClock(done)
+	if !rule.end.until.IsZero() && common.ReduceTimeToDay(done).After(rule.end.until) {
+		return "", "", ErrNextDateSeriesEnded
+	}
+	return rule.occurrence(rule.intoWindow(done.Add(time.Duration(rule.hours) * time.Hour)))
+}
+
+// parseHourly - rule with flag 'h' and first occurrence of series
+func parseHourly(dstart, dtime, repeat string) (*Rule, time.Time, error) {
+	rule, err := ParseRule(repeat)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if rule.flag != hour {
+		return nil, time.Time{}, ErrNextDateWrongRepeat
+	}
+	date, err := time.Parse(model.DateFormat, dstart)
+	if err != nil {
+		return nil, time.Time{}, ErrNextDateInvalidDate
+	}
+	minutes := rule.window.from
+	if dtime != "" {
+		var ok bool
+		if minutes, ok = minutesOfDay(dtime); !ok {
+			return nil, time.Time{}, ErrNextDateInvalidTime
+		}
+	}
+	if !rule.end.until.IsZero() && date.After(rule.end.until) {
+		return nil, time.Time{}, ErrNextDateWrongRepeat
+	}
+	return rule, date.Add(time.Duration(minutes) * time.Minute), nil
+}
+
+// wallClock - date and time of 't' in UTC without seconds (as date and time of task)
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+}
+
+// nextOccurrence - occurrence of series after 'after' ('after' not before 'start')
+//
+// without window - 'start' + N hours, othercase - start of window + N hours every day
+func (r *Rule) nextOccurrence(start, after time.Time) (string, string, error) {
+	step := time.Duration(r.hours) * time.Hour
+	if !r.window.isSet() {
+		return r.occurrence(start.Add((after.Sub(start)/step + 1) * step))
+	}
+	day := common.ReduceTimeToDay(after)
+	minutes := int(after.Sub(day) / time.Minute)
+	if minutes < r.window.from {
+		minutes = r.window.from
+	} else {
+		stepMinutes := r.hours * minutesPerHour
+		minutes = r.window.from + ((minutes-r.window.from)/stepMinutes+1)*stepMinutes
+	}
+	if minutes > r.window.to {
+		day = day.AddDate(0, 0, 1)
+		minutes = r.window.from
+	}
+	return r.occurrence(day.Add(time.Duration(minutes) * time.Minute))
+}
+
+// intoWindow - time before window -> start of window, after window -> start of window next day
+func (r *Rule) intoWindow(t time.Time) time.Time {
+	if !r.window.isSet() {
+		return t
+	}
+	day := common.ReduceTimeToDay(t)
+	minutes := int(t.Sub(day) / time.Minute)
+	if minutes > r.window.to {
+		day = day.AddDate(0, 0, 1)
+		minutes = r.window.from
+	}
+	if minutes < r.window.from {
+		minutes = r.window.from
+	}
+	return day.Add(time.Duration(minutes) * time.Minute)
+}
+
+// occurrence - check end condition and format date and time of 'next'
+func (r *Rule) occurrence(next time.Time) (string, string, error) {
+	if r.end.isReached(common.ReduceTimeToDay(next)) {
+		return "", "", ErrNextDateSeriesEnded
+	}
+	return next.Format(model.DateFormat), next.Format(model.TimeFormat), nil
+}
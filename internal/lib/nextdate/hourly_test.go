@@ -0,0 +1,109 @@
+package nextdate
+
+import (
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NextTime(t *testing.T) {
+	asserts := assert.New(t)
+
+	now := time.Date(2024, 1, 10, 10, 30, 45, 0, time.UTC)
+
+	dataForNextTime := []struct {
+		dstart string
+		dtime  string
+		repeat string
+		date   string
+		time   string
+		err    error
+		msg    string
+	}{
+		{"20240110", "08:00", "h 4", "20240110", "12:00", nil, `next step after now`},
+		{"20240109", "22:15", "h 4", "20240110", "14:15", nil, `step from time of first occurrence`},
+		{"20240110", "10:30", "h 1", "20240110", "11:30", nil, `occurrence now is not next`},
+		{"20240110", "23:00", "h 2", "20240111", "01:00", nil, `start after now - next after start`},
+		{"20240101", "", "h 4 09:00-18:00", "20240110", "13:00", nil, `window - next step of window`},
+		{"20240101", "", "h 4 11:00-18:00", "20240110", "11:00", nil, `window - now before window`},
+		{"20240101", "", "h 4 06:00-09:00", "20240111", "06:00", nil, `window - now after window`},
+		{"20240101", "", "h 5 00:00-23:00", "20240110", "15:00", nil, `window - last step of day`},
+		{"20240110", "09:00", "h 4 09:00-10:00", "20240111", "09:00", nil, `window - step out of window`},
+		{"20240110", "08:00", "h 4 until 20240110", "20240110", "12:00", nil, `until - same day`},
+		{"20240110", "22:00", "h 4 until 20240110", "", "", ErrNextDateSeriesEnded, `until - next day`},
+		{"20240110", "08:00", "h 4 count 1", "", "", ErrNextDateSeriesEnded, `count - last occurrence`},
+		{"20240111", "08:00", "h 4 until 20240110", "", "", ErrNextDateWrongRepeat, `until before start`},
+		{"20240110", "8:00", "h 4", "", "", ErrNextDateInvalidTime, `wrong time`},
+		{"2024011", "08:00", "h 4", "", "", ErrNextDateInvalidDate, `wrong date`},
+		{"20240110", "08:00", "d 1", "", "", ErrNextDateWrongRepeat, `rule without hours`},
+	}
+
+	for i, test := range dataForNextTime {
+		log.Printf("\t%d %s", i+1, test.msg)
+
+		date, clock, err := NextTime(now, test.dstart, test.dtime, test.repeat)
+		asserts.ErrorIs(err, test.err, test.msg)
+		asserts.Equal(test.date, date, test.msg)
+		asserts.Equal(test.time, clock, test.msg)
+	}
+
+	// date of rule 'h' is next day
+	date, err := NextDate(now, "20240101", "h 4 09:00-18:00")
+	asserts.NoError(err)
+	asserts.Equal("20240111", date)
+}
+
+func Test_StartTime(t *testing.T) {
+	asserts := assert.New(t)
+
+	now := time.Date(2024, 1, 10, 10, 30, 0, 0, time.UTC)
+
+	date, clock, err := StartTime(now, "20240110", "12:00", "h 4")
+	asserts.NoError(err)
+	asserts.Equal([]string{"20240110", "12:00"}, []string{date, clock})
+
+	date, clock, err = StartTime(now, "20240112", "", "h 4 09:00-18:00")
+	asserts.NoError(err)
+	asserts.Equal([]string{"20240112", "09:00"}, []string{date, clock})
+
+	date, clock, err = StartTime(now, "20240110", "", "h 3")
+	asserts.NoError(err)
+	asserts.Equal([]string{"20240110", "12:00"}, []string{date, clock})
+
+	_, _, err = StartTime(now, "20240101", "08:00", "h 4 until 20240105")
+	asserts.ErrorIs(err, ErrNextDateSeriesEnded)
+}
+
+func Test_NextTimeAfterDone(t *testing.T) {
+	asserts := assert.New(t)
+
+	dataForAfterDone := []struct {
+		done   time.Time
+		repeat string
+		date   string
+		time   string
+		err    error
+	}{
+		{time.Date(2024, 1, 10, 10, 30, 0, 0, time.UTC), "h 4", "20240110", "14:30", nil},
+		{time.Date(2024, 1, 10, 22, 30, 0, 0, time.UTC), "h 4", "20240111", "02:30", nil},
+		{time.Date(2024, 1, 10, 10, 30, 0, 0, time.UTC), "h 4 09:00-18:00", "20240110", "14:30", nil},
+		{time.Date(2024, 1, 10, 15, 0, 0, 0, time.UTC), "h 4 09:00-18:00", "20240111", "09:00", nil},
+		{time.Date(2024, 1, 10, 3, 0, 0, 0, time.UTC), "h 4 09:00-18:00", "20240110", "09:00", nil},
+		{time.Date(2024, 1, 11, 3, 0, 0, 0, time.UTC), "h 4 until 20240110", "", "", ErrNextDateSeriesEnded},
+		{time.Date(2024, 1, 10, 22, 0, 0, 0, time.UTC), "h 4 until 20240110", "", "", ErrNextDateSeriesEnded},
+		{time.Date(2024, 1, 10, 22, 0, 0, 0, time.UTC), "w 1", "", "", ErrNextDateWrongRepeat},
+	}
+
+	for _, test := range dataForAfterDone {
+		date, clock, err := NextTimeAfterDone(test.done, test.repeat)
+		asserts.ErrorIs(err, test.err, test.repeat)
+		asserts.Equal(test.date, date, test.repeat)
+		asserts.Equal(test.time, clock, test.repeat)
+	}
+
+	asserts.True(IsHourly("h 2"))
+	asserts.False(IsHourly("d 2"))
+	asserts.False(IsHourly("FREQ=DAILY"))
+}
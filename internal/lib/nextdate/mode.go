@@ -0,0 +1,55 @@
+// mode - from which date the series is continued after task is done
+//
+// schedule   - dates are fixed to calendar of 'dstart': "rent on the 1st"
+// completion - series is restarted from day of completion: "water plants 7 days after I did it"
+package nextdate
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+	"github.com/Ekvo/yandex-practicum-go-final-project/pkg/common"
+)
+
+// ErrNextDateUnknownMode - mode of repeat is not supported
+var ErrNextDateUnknownMode = errors.New("unknown mode")
+
+// modes of repeat, empty mode - 'ModeSchedule'
+const (
+	ModeSchedule   = "schedule"
+	ModeCompletion = "completion"
+)
+
+// IsMode - mode of repeat is supported, empty mode is allowed
+func IsMode(mode string) bool {
+	return mode == "" || mode == ModeSchedule || mode == ModeCompletion
+}
+
+// NextDateAfterDone - next date of series restarted from day of completion 'done'
+//
+// 'done' is counted as new 'dstart' of series, 'done' after "until" -> ErrNextDateSeriesEnded
+// end condition of algorithm added by 'Register' is checked only by 'nextDate'
+func NextDateAfterDone(nextDate NextDateFunc, done time.Time, repeat string) (string, error) {
+	done = common.ReduceTimeToDayUTC(done)
+	if until := seriesEnd(repeat).until; !until.IsZero() && done.After(until) {
+		return "", ErrNextDateSeriesEnded
+	}
+	return nextDate(done, done.Format(model.DateFormat), repeat)
+}
+
+// seriesEnd - end condition of !_repeat_! string, unknown syntax -> series is endless
+func seriesEnd(repeat string) endCondition {
+	if IsRRule(repeat) {
+		rule, err := parseRRule(repeat)
+		if err != nil {
+			return endCondition{}
+		}
+		return rule.end
+	}
+	rule, err := ParseRule(repeat)
+	if err != nil {
+		return endCondition{}
+	}
+	return rule.end
+}
@@ -0,0 +1,98 @@
+package nextdate
+
+import (
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NextDateAfterDone(t *testing.T) {
+	asserts := assert.New(t)
+
+	done := time.Date(2024, 1, 10, 15, 0, 0, 0, time.UTC) // Wednesday
+
+	dataForAfterDone := []struct {
+		repeat string
+		date   string
+		err    error
+		msg    string
+	}{
+		{
+			repeat: "d 7",
+			date:   "20240117",
+			msg:    `from day of completion`,
+		},
+		{
+			repeat: "w/2 1",
+			date:   "20240122",
+			msg:    `phase of interval from day of completion`,
+		},
+		{
+			repeat: "m 1",
+			date:   "20240201",
+			msg:    `calendar rule`,
+		},
+		{
+			repeat: "FREQ=DAILY;INTERVAL=3",
+			date:   "20240113",
+			msg:    `RRULE`,
+		},
+		{
+			repeat: "d 7 until 20240120",
+			date:   "20240117",
+			msg:    `until after next date`,
+		},
+		{
+			repeat: "d 7 until 20240115",
+			err:    ErrNextDateSeriesEnded,
+			msg:    `next date after until`,
+		},
+		{
+			repeat: "d 7 until 20240105",
+			err:    ErrNextDateSeriesEnded,
+			msg:    `done after until`,
+		},
+		{
+			repeat: "FREQ=WEEKLY;UNTIL=20240101",
+			err:    ErrNextDateSeriesEnded,
+			msg:    `RRULE done after until`,
+		},
+		{
+			repeat: "d 7 count 1",
+			err:    ErrNextDateSeriesEnded,
+			msg:    `last occurrence`,
+		},
+		{
+			repeat: "k 7",
+			err:    ErrNextDateWrongRepeat,
+			msg:    `wrong repeat`,
+		},
+	}
+
+	for i, test := range dataForAfterDone {
+		log.Printf("\t%d %s", i+1, test.msg)
+
+		nextDate := NextDate
+		if IsRRule(test.repeat) {
+			nextDate = RRule
+		}
+		date, err := NextDateAfterDone(nextDate, done, test.repeat)
+		if test.err != nil {
+			asserts.ErrorIs(err, test.err)
+			continue
+		}
+		asserts.NoError(err)
+		asserts.Equal(test.date, date)
+	}
+}
+
+func Test_IsMode(t *testing.T) {
+	asserts := assert.New(t)
+
+	asserts.True(IsMode(""))
+	asserts.True(IsMode(ModeSchedule))
+	asserts.True(IsMode(ModeCompletion))
+	asserts.False(IsMode("calendar"))
+}
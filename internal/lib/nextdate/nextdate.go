@@ -4,8 +4,8 @@ package nextdate
 import (
 	"errors"
 	"strconv"
+	"strings"
 	"time"
-	"unicode"
 
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
 	"github.com/Ekvo/yandex-practicum-go-final-project/pkg/common"
@@ -21,6 +21,9 @@ var (
 
 	// ErrServicUnexpectedBehavior - critical error of algorithm 'NextDate'
 	ErrNextDateUnexpectedBehavior = errors.New("unexpected behavior")
+
+	// ErrNextDateSeriesEnded - end condition of !_repeat_! string is reached, no more dates
+	ErrNextDateSeriesEnded = errors.New("repeat series ended")
 )
 
 type NextDateFunc func(now time.Time, dstart string, repeat string) (string, error)
@@ -34,14 +37,46 @@ const (
 	//  m day(s)     or  m day(s)      month(s)
 	month = 'm'
 	year  = 'y' //[y], example: y
+
+	// [n weekday#ordinal(s)] or [n weekday#ordinal(s) number(s)], example: n 2#2,5#-1 1,4
+	//  n nth weekday(s) of month or  n nth weekday(s)  month(s)
+	// ordinal: 1..5 from beginning of month, -1..-5 from end of month (-1 - last)
+	nthWeekday = 'n'
+
+	businessDay = 'b' // [b number], example: b 3 - every 3 working days
+
+	// [h number] or [h number time-time], example: h 4 09:00-18:00
+	//  h hour(s)  or  h hour(s) window of day
+	// next date of day - next day, time of day see (lib/nextdate/hourly.go)
+	hour = 'h'
+)
+
+// interval - optional modifier of flags 'w', 'm', 'y' - every N week(s), month(s), year(s)
+// phase of interval is anchored to 'dstart'
+//
+// [flag/number ...], example: w/2 1,4 or m/3 15 or y/2
+const (
+	interval    = '/'
+	maxInterval = 52
+)
+
+// clauses - optional tail of !_repeat_! string
+const (
+	// end conditions (only one of them)
+	until = "until" // [rule until date], example: d 7 until 20250101
+	count = "count" // [rule count number], example: w 1,3 count 10
+
+	// move date to workday if it is weekend or holiday
+	shift = "shift" // [rule shift next] or [rule shift prev], example: m 15 shift next
 )
 
 const (
 	minDay = 1
 
 	// limitation:
-	// 1 - number of days,
-	// 2 - max number of loop in func(s) - 'nextDateByWeek','nextDateByMonth' look down
+	// 1 - number of days of rules 'd' and 'b', INTERVAL of RRULE,
+	// 2 - days off in a row for 'moveToWorkday' and dates of rule for 'shiftNextDate' (lib/nextdate/workday.go),
+	// other dates are found without loop by days (see 'nextDateByBusinessDay', 'rrule.next')
 	maxDay = 400
 
 	monday = 1
@@ -49,43 +84,105 @@ const (
 )
 
 // NextDate - main function to algorithm
+//
+// 'dstart' is counted as the first occurrence of the series,
+// if end condition does not allow a next date -> ErrNextDateSeriesEnded
 func NextDate(now time.Time, dstart string, repeat string) (string, error) {
-	if len(repeat) == 0 {
-		return "", ErrNextDateWrongRepeat
+	rule, err := ParseRule(repeat)
+	if err != nil {
+		return "", err
 	}
 	taskDateStart, err := time.Parse(model.DateFormat, dstart)
 	if err != nil {
 		return "", ErrNextDateInvalidDate
 	}
-	now = common.ReduceTimeToDay(now)
-	var newDate time.Time
-	switch ch := repeat[0]; ch {
+	if !rule.end.until.IsZero() && taskDateStart.After(rule.end.until) {
+		return "", ErrNextDateWrongRepeat
+	}
+	// day of 'now' in own location, dates of series are parsed in UTC
+	now = common.ReduceTimeToDayUTC(now)
+	newDate, err := rule.Next(now, taskDateStart)
+	if err != nil {
+		return "", err
+	}
+	if rule.end.isReached(newDate) {
+		return "", ErrNextDateSeriesEnded
+	}
+	return newDate.Format(model.DateFormat), nil
+}
+
+// nextDateByRule - call function by flag of rule
+func nextDateByRule(now, taskDateStart time.Time, rule *Rule) (time.Time, error) {
+	switch rule.flag {
 	case day:
-		newDate, err = nextDateByDay(now, taskDateStart, repeat)
+		return nextDateByDay(now, taskDateStart, rule.days)
 	case year:
-		newDate, err = nextDateByYear(now, taskDateStart, repeat)
+		return nextDateByYear(now, taskDateStart, rule.every)
 	case weak:
-		newDate, err = nextDateByWeek(now, taskDateStart, repeat)
+		return nextDateByWeek(now, taskDateStart, rule.weekdays, rule.every)
 	case month:
-		newDate, err = nextDateByMonth(now, taskDateStart, repeat)
-	default:
-		err = ErrNextDateWrongRepeat
+		return nextDateByMonth(now, taskDateStart, rule.monthDays, rule.months, rule.every)
+	case nthWeekday:
+		return nextDateByNthWeekday(now, taskDateStart, rule.nthWeekdays, rule.months, rule.every)
+	case businessDay:
+		return nextDateByBusinessDay(now, taskDateStart, rule.days)
+	case hour:
+		// every day has occurrence(s) of rule 'h'
+		return nextDateByDay(now, taskDateStart, 1)
+	}
+	return time.Time{}, ErrNextDateWrongRepeat
+}
+
+// endCondition - limit of series, zero value - series is endless
+type endCondition struct {
+	// last possible date of series (include)
+	until time.Time
+
+	// number of occurrences left, 'dstart' is the first of them
+	count int
+}
+
+// isReached - 'next' is out of series
+func (e endCondition) isReached(next time.Time) bool {
+	if !e.until.IsZero() && next.After(e.until) {
+		return true
 	}
+	return e.count == 1
+}
+
+// ReduceCount - call after one occurrence of series is done
+// decrease number of "count" end condition, other rules return without change
+//
+// example: "d 7 count 3" -> "d 7 count 2"
+func ReduceCount(repeat string) (string, error) {
+	if IsRRule(repeat) {
+		return reduceRRuleCount(repeat)
+	}
+	rule, err := ParseRule(repeat)
 	if err != nil {
 		return "", err
 	}
-	return newDate.Format(model.DateFormat), nil
+	if rule.end.count == 0 {
+		return repeat, nil
+	}
+	if rule.end.count == 1 {
+		return "", ErrNextDateSeriesEnded
+	}
+	fields := strings.Split(repeat, " ")
+	for i := len(fields) - 2; i > 0; i-- {
+		if fields[i] == count {
+			fields[i+1] = strconv.Itoa(rule.end.count - 1)
+			break
+		}
+	}
+	return strings.Join(fields, " "), nil
 }
 
 const daySeconds = 24 * 60 * 60
 
 // nextDateByDay - all time to UNIX and work only with type int64
 // for starters  - find count of days for adding
-func nextDateByDay(now, taskDateStart time.Time, repeat string) (time.Time, error) {
-	days, err := numberOfDays(repeat)
-	if err != nil || days < minDay || days > maxDay {
-		return time.Time{}, ErrNextDateWrongRepeat
-	}
+func nextDateByDay(now, taskDateStart time.Time, days int) (time.Time, error) {
 	nowUnix := now.Unix()
 	startUnix := taskDateStart.Unix()
 	daysToSeconds := int64(days * daySeconds)
@@ -99,209 +196,236 @@ func nextDateByDay(now, taskDateStart time.Time, repeat string) (time.Time, erro
 	return newDate, nil
 }
 
-// numberOfDays - find number of days
-// rules: "number of days" is solo value
-//
-//	"number of days" always exists and is less than or equal to 400 and greater than 0
-func numberOfDays(repeat string) (int, error) {
-	n := len(repeat)
-	if n < 3 || repeat[1] != ' ' {
-		return 0, ErrNextDateWrongRepeat
-	}
-	// example: d 45656 -> d - [0], space [1]
-	days, err := strconv.Atoi(repeat[2:])
-	if err != nil {
-		return 0, ErrNextDateWrongRepeat
-	}
-	return days, nil
-}
-
 // nextDateByYear - add solo or many year(s) to taskDateStart
-func nextDateByYear(now, taskDateStart time.Time, repeat string) (time.Time, error) {
-	if len(repeat) != 1 {
-		return time.Time{}, ErrNextDateWrongRepeat
-	}
-	newDate := taskDateStart.AddDate(1, 0, 0)
+// 'every' - interval of years, always added to taskDateStart (keep phase)
+func nextDateByYear(now, taskDateStart time.Time, every int) (time.Time, error) {
+	years := every
 	nowYear := now.Year()
 	startYear := taskDateStart.Year()
 	if startYear < nowYear {
-		years := nowYear - startYear
-		newDate = taskDateStart.AddDate(years, 0, 0)
+		years = (nowYear - startYear) / every * every
 	}
+	newDate := taskDateStart.AddDate(years, 0, 0)
 	// if the month or day or (month and day) is less than "now"
-	if !newDate.UTC().After(now.UTC()) {
-		newDate = newDate.AddDate(1, 0, 0)
+	for !newDate.UTC().After(now.UTC()) || !newDate.After(taskDateStart) {
+		years += every
+		newDate = taskDateStart.AddDate(years, 0, 0)
 	}
 	return newDate, nil
 }
 
-// nextDateByWeek - find new date by day(s) of week
-// for starters - find number of days
-func nextDateByWeek(now, taskDateStart time.Time, repeat string) (time.Time, error) {
-	days, err := daysOfWeek(repeat)
-	if err != nil {
-		return time.Time{}, err
-	}
+// nextDateByWeek - find new date by day(s) of week, index of 'days' is time.Weekday
+//
+// 'every' - interval of weeks, week of taskDateStart is first
+// jump to week in phase and check only days of it - no more than two weeks for any 'now'
+func nextDateByWeek(now, taskDateStart time.Time, days []bool, every int) (time.Time, error) {
 	newDate := taskDateStart
 	if newDate.UTC().Before(now.UTC()) {
 		newDate = now
 	}
-	for i := 0; i < maxDay; i++ {
-		newDate = newDate.AddDate(0, 0, 1)
-		if days[newDate.Weekday()] {
-			return newDate, nil
-		}
+	newDate = newDate.AddDate(0, 0, 1)
+	startWeek := weekNumber(taskDateStart)
+	if phase := floorMod(weekNumber(newDate)-startWeek, every); phase != 0 {
+		newDate = beginningOfWeek(newDate).AddDate(0, 0, 7*(every-phase))
 	}
-	return time.Time{}, ErrNextDateUnexpectedBehavior
-}
-
-// daysOfWeek - find unique day of week
-// not repeat days
-func daysOfWeek(repeat string) ([]bool, error) {
-	n := len(repeat)
-	if n < 3 || repeat[1] != ' ' {
-		return nil, ErrNextDateWrongRepeat
-	}
-	days := make([]bool, 8)
-	for i := 2; i < n; i++ {
-		ch := rune(repeat[i])
-		if ch == ',' {
-			if prev := repeat[i-1]; prev == ',' || prev == ' ' || i == n-1 {
-				return nil, ErrNextDateWrongRepeat
+	for i := 0; i < 2; i++ {
+		for weekday := newDate.Weekday(); ; weekday = newDate.Weekday() {
+			if days[weekday] {
+				return newDate, nil
 			}
-			continue
-		}
-		if unicode.IsDigit(ch) {
-			start := i
-			for i++; i < n && unicode.IsDigit(rune(repeat[i])); i++ {
+			if weekday == time.Sunday {
+				break
 			}
-			day, err := strconv.Atoi(repeat[start:i])
-			if err != nil || day < monday || day > sunday || days[day] {
-				return nil, ErrNextDateWrongRepeat
-			}
-			days[day] = true
-			i--
-			continue
+			newDate = newDate.AddDate(0, 0, 1)
 		}
-		return nil, ErrNextDateWrongRepeat
+		// after sunday - monday of next week in phase
+		newDate = newDate.AddDate(0, 0, 1+7*(every-1))
 	}
-	if days[sunday] {
-		days[0] = true // time.Sunday = 0
+	return time.Time{}, ErrNextDateUnexpectedBehavior
+}
+
+// beginningOfWeek - monday of week of 't'
+func beginningOfWeek(t time.Time) time.Time {
+	return t.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+}
+
+// dayNumber - number of day from 01.01.1970, not depend on time.Location
+func dayNumber(t time.Time) int {
+	return int(floorDiv(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix(), daySeconds))
+}
+
+// weekNumber - number of week from 01.01.1970, week start from monday
+// 01.01.1970 - thursday -> +3 days to monday
+func weekNumber(t time.Time) int {
+	return int(floorDiv(int64(dayNumber(t)+3), 7))
+}
+
+// monthNumber - number of month from year zero
+func monthNumber(t time.Time) int {
+	return t.Year()*12 + int(t.Month()) - 1
+}
+
+func floorDiv(a, b int64) int64 {
+	res := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		res--
 	}
-	return days, nil
+	return res
+}
+
+func floorMod(a, b int) int {
+	return int(int64(a) - floorDiv(int64(a), int64(b))*int64(b))
 }
 
 const (
 	maxDaysPerMonth     = 31
 	lastDayMonth        = -1
 	penultimateDayMonth = -2
+
+	// maxMonthSteps - limit of checked months in 'nextDateByMonth', 'nextDateByNthWeekday', not depend on 'now'
+	// the rarest date - fifth weekday of february (leap year, february 1 is this weekday):
+	// no more than 40 years between them
+	maxMonthSteps = 12 * 41
 )
 
+// daysInMonth - max number of days in month (february of leap year - 29)
+func daysInMonth(month time.Month) int {
+	return time.Date(2000, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// existInMonths - at least one of 'days' (1..31, negative from end of month) exists in one of 'months'
+// index of 'months' is time.Month
+func existInMonths(days []int, months []bool) bool {
+	for m := time.January; m <= time.December; m++ {
+		if !months[m] {
+			continue
+		}
+		for _, day := range days {
+			if common.Abs(day) <= daysInMonth(m) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // nextDateByMonth - find by (day of month) or (by month and day)
-// for starters - get []any from  monthAndDay look down
 //
-// find by days           -> add day and check map on exist
+// days of every month are calculated from last day of month, no search by day
+// 'every' - interval of months, month of taskDateStart is first (can't be used with month(s))
+func nextDateByMonth(
+	now, taskDateStart time.Time,
+	days map[int]bool,
+	month []bool,
+	every int) (time.Time, error) {
+	return nextDateInMonths(now, taskDateStart, month, every, func(_ time.Time, lastDay int) []int {
+		res := make([]int, 0, len(days))
+		for day := range days {
+			if day < 0 {
+				day = lastDay + 1 + day
+			}
+			if day <= lastDay {
+				res = append(res, day)
+			}
+		}
+		return res
+	})
+}
+
+// nextDateInMonths - first date after 'now' and after 'taskDateStart' from 'daysOfMonth' of month
 //
-// find by days and month -> start: do a day first in month and check month while [index]bool != true
-// then -> find by days
-func nextDateByMonth(now, taskDateStart time.Time, repeat string) (time.Time, error) {
-	data, err := monthAndDay(repeat)
-	if err != nil {
-		return time.Time{}, err
-	}
-	days := data[0].(map[int]bool)
-	var month []bool
-	if len(data) == 2 {
-		month = data[1].([]bool)
-	}
+// months out of 'month' (nil - every month) or out of phase of 'every' are skipped at once
+// 'daysOfMonth' - days of month by first day of month and number of last day
+// rule without date for 'taskDateStart' (m/12 31 started in february) -> ErrNextDateWrongRepeat
+func nextDateInMonths(
+	now, taskDateStart time.Time,
+	month []bool,
+	every int,
+	daysOfMonth func(first time.Time, lastDay int) []int) (time.Time, error) {
 	newDate := taskDateStart
 	if !newDate.UTC().After(now.UTC()) {
 		newDate = now.AddDate(0, 0, 1)
 	}
-	for m, i := len(month), 0; i < maxDay; i++ {
-		if m != 0 && !month[newDate.Month()] {
-			newDate = newDate.AddDate(0, 1, 0)
-			if newDate.Day() != 1 {
-				newDate = common.BeginningOfMonth(newDate)
-			}
+	startMonth := monthNumber(taskDateStart)
+	firstDay := newDate.Day()
+	newDate = common.BeginningOfMonth(newDate)
+	for i := 0; i < maxMonthSteps; i++ {
+		if phase := floorMod(monthNumber(newDate)-startMonth, every); phase != 0 {
+			newDate, firstDay = newDate.AddDate(0, every-phase, 0), 1
 			continue
 		}
-		if days[newDate.Day()] {
-			return newDate, nil
+		if month != nil && !month[newDate.Month()] {
+			newDate, firstDay = newDate.AddDate(0, 1, 0), 1
+			continue
 		}
-		if days[lastDayMonth] &&
-			newDate.AddDate(0, 0, 1).Day() == 1 {
-			return newDate, nil
+		bestDay := 0
+		lastDay := newDate.AddDate(0, 1, -1).Day()
+		for _, day := range daysOfMonth(newDate, lastDay) {
+			if day >= firstDay && (bestDay == 0 || day < bestDay) {
+				bestDay = day
+			}
 		}
-		if days[penultimateDayMonth] &&
-			newDate.AddDate(0, 0, 2).Day() == 1 {
-			return newDate, nil
+		if bestDay != 0 {
+			return newDate.AddDate(0, 0, bestDay-1), nil
 		}
-		newDate = newDate.AddDate(0, 0, 1)
+		newDate, firstDay = newDate.AddDate(0, every, 0), 1
 	}
-	return time.Time{}, ErrNextDateUnexpectedBehavior
+	return time.Time{}, ErrNextDateWrongRepeat
 }
 
-// monthAndDay - find (number of days) map[int]bool -> (because may contain -1, -2)
-//
-//	or (number of days, number of month) -> ([]bool,map[int]bool)
+// ordinalWeekday - day of week with number of it in month
+// example: (time.Tuesday, 2) - second tuesday, (time.Friday, -1) - last friday
+type ordinalWeekday struct {
+	weekday time.Weekday
+	ordinal int
+}
+
+const (
+	separatorOrdinal = '#'
+	maxOrdinal       = 5
+)
+
+// nextDateByNthWeekday - find by nth weekday(s) of month or (by nth weekday(s) and month)
+// algorithm the same as 'nextDateByMonth', day of ordinal weekday is calculated from first day of month
 //
-// solo space  - create -> number of days
-// twice space - create -> number of month
-func monthAndDay(repeat string) ([]any, error) {
-	n := len(repeat)
-	if n < 3 || repeat[1] != ' ' {
-		return nil, ErrNextDateWrongRepeat
-	}
-	space := 1
-	days := make(map[int]bool)
-	months := make([]bool, time.December+1)
-	for i := 2; i < n; i++ {
-		ch := rune(repeat[i])
-		if ch == ',' {
-			if prev := repeat[i-1]; prev == ',' || prev == ' ' || i == n-1 {
-				return nil, ErrNextDateWrongRepeat
-			}
-			continue
-		}
-		if unicode.IsDigit(ch) || ch == '-' {
-			if ch == '-' && unicode.IsDigit(rune(repeat[i-1])) { // m -1-1,31
-				return nil, ErrNextDateWrongRepeat
-			}
-			start := i
-			for i++; i < n && unicode.IsDigit(rune(repeat[i])); i++ {
-			}
-			dayOrMonth, err := strconv.Atoi(repeat[start:i])
-			if err != nil {
-				return nil, ErrNextDateWrongRepeat
+// 'every' - interval of months, month of taskDateStart is first (can't be used with month(s))
+func nextDateByNthWeekday(
+	now, taskDateStart time.Time,
+	weekdays map[ordinalWeekday]bool,
+	month []bool,
+	every int) (time.Time, error) {
+	return nextDateInMonths(now, taskDateStart, month, every, func(first time.Time, lastDay int) []int {
+		res := make([]int, 0, len(weekdays))
+		for nth := range weekdays {
+			if day := dayOfOrdinalWeekday(first, lastDay, nth); day != 0 {
+				res = append(res, day)
 			}
-			if space == 1 {
-				if d := common.Abs(dayOrMonth); d < minDay || d > maxDaysPerMonth ||
-					dayOrMonth < penultimateDayMonth || days[dayOrMonth] {
-					return nil, ErrNextDateWrongRepeat
-				}
-				days[dayOrMonth] = true
-			} else if space == 2 {
-				month := time.Month(dayOrMonth)
-				if month < time.January || month > time.December || months[month] {
-					return nil, ErrNextDateWrongRepeat
-				}
-				months[month] = true
-			}
-			i--
-			continue
 		}
-		if ch == ' ' {
-			space++
-			if prev := repeat[i-1]; prev == ' ' || prev == ',' || space > 2 || i == n-1 {
-				return nil, ErrNextDateWrongRepeat
-			}
-			continue
-		}
-		return nil, ErrNextDateWrongRepeat
+		return res
+	})
+}
+
+// dayOfOrdinalWeekday - day of month for 'nth' weekday, 'first' - first day of month
+// weekday not exist in month (fifth monday) -> 0
+func dayOfOrdinalWeekday(first time.Time, lastDay int, nth ordinalWeekday) int {
+	day := 1 + (int(nth.weekday)-int(first.Weekday())+7)%7 + 7*(nth.ordinal-1)
+	if nth.ordinal < 0 {
+		lastWeekday := (int(first.Weekday()) + lastDay - 1) % 7
+		day = lastDay - (lastWeekday-int(nth.weekday)+7)%7 + 7*(nth.ordinal+1)
 	}
-	if space == 1 {
-		return []any{days}, nil
+	if day < 1 || day > lastDay {
+		return 0
 	}
-	return []any{days, months}, nil
+	return day
+}
+
+// ordinalOfWeekday - 'date' is ordinal weekday of month from beginning
+func ordinalOfWeekday(date time.Time) ordinalWeekday {
+	return ordinalWeekday{weekday: date.Weekday(), ordinal: (date.Day()-1)/7 + 1}
+}
+
+// ordinalOfWeekdayFromEnd - 'date' is ordinal weekday of month from end (negative)
+func ordinalOfWeekdayFromEnd(date time.Time) ordinalWeekday {
+	lastDay := common.BeginningOfMonth(date).AddDate(0, 1, -1).Day()
+	return ordinalWeekday{weekday: date.Weekday(), ordinal: -((lastDay-date.Day())/7 + 1)}
 }
@@ -51,6 +51,33 @@ func Test_NextDate(t *testing.T) {
 		{"20240125", "w 1,2,3", "20240129"},
 		{"20240126", "w 7", "20240128"},
 		{"20230126", "w 4,5", "20240201"},
+		{"20240113", "d 7 until 20240131", "20240127"},
+		{"20240113", "d 7 count 3", "20240127"},
+		{"20240125", "w 1,2,3 until 20240129", "20240129"},
+		{"20240329", "m 10,17 12,8,1 count 2", "20240810"},
+		{"20240101", "y until 20250101", "20250101"},
+		{"20240131", "m 5 2", "20240205"},
+		{"20240115", "w/2 1,4", "20240129"},
+		{"20240122", "w/2 1,4", "20240205"},
+		{"20240105", "w/3 5", "20240216"},
+		{"20231015", "m/3 15", "20240415"},
+		{"20231130", "m/2 -1", "20240131"},
+		{"20200301", "y/2", "20240301"},
+		{"20230126", "y/2", "20250126"},
+		{"20250701", "y/3", "20280701"},
+		{"20230126", "w/2 1 count 5", "20240205"},
+		{"20240101", "n 2#2", "20240213"},
+		{"20240101", "n 5#-1", "20240223"},
+		{"20240101", "n 1#1 3,6", "20240304"},
+		{"20240101", "n 7#1,7#-1", "20240128"},
+		{"20231019", "n/3 4#3", "20240418"},
+		{"20240101", "n 4#5", "20240229"},
+		{"20240101", "n 3#-2 02", "20240221"},
+		{"20240101", "m 31 2,3", "20240331"},
+		{"20240101", "m 29 2", "20240229"},
+		{"20240301", "m 29 2", "20280229"},
+		{"20240101", "n 5#5 2", "20360229"},
+		{"20240101", "n 5#-5 2", "20360201"},
 	}
 
 	for i, test := range validData {
@@ -97,6 +124,41 @@ func Test_NextDate(t *testing.T) {
 		{"20220425", "m 18 ,1,3", ""},
 		{"20600714", "m 18 1 ", ""},
 		{"20600714", "m 31 2", ""},
+		{"20600714", "m 30,31 2", ""},
+		{"20240229", "m/12 30", ""},
+		{"20240113", "d 7 until 20240126", ""},
+		{"20240113", "d 7 count 1", ""},
+		{"20240113", "d 7 count 0", ""},
+		{"20240113", "d 7 count 03", ""},
+		{"20240113", "d 7 until 2024013", ""},
+		{"20240113", "d 7 until 20240101", ""},
+		{"20240113", "until 20240201", ""},
+		{"20240113", "d 7 count 3 until 20240301", ""},
+		{"20240113", "d 7  count 3", ""},
+		{"20240113", "d/2 7", ""},
+		{"20240113", "w/0 1", ""},
+		{"20240113", "w/53 1", ""},
+		{"20240113", "w/ 1", ""},
+		{"20240113", "w/02 1", ""},
+		{"20240113", "m/3 15 1,4", ""},
+		{"20240113", "y/", ""},
+		{"20240113", "y/2 ", ""},
+		{"20240113", "y/x", ""},
+		{"20240113", "n", ""},
+		{"20240113", "n ", ""},
+		{"20240113", "n 2", ""},
+		{"20240113", "n 8#1", ""},
+		{"20240113", "n 2#0", ""},
+		{"20240113", "n 2#6", ""},
+		{"20240113", "n 2#-6", ""},
+		{"20240113", "n 2#2,2#2", ""},
+		{"20240113", "n 2#2 13", ""},
+		{"20240113", "n 2#2 1,1", ""},
+		{"20240113", "n/2 2#2 1", ""},
+		{"20240113", "n 2#2,", ""},
+		{"20240113", "n 2##2", ""},
+		{"20240113", "n #2", ""},
+		{"20240113", "n 2#2 1 ", ""},
 	}
 
 	for i, test := range invalidData {
@@ -107,3 +169,110 @@ func Test_NextDate(t *testing.T) {
 		asserts.Empty(getDate, "should be equal")
 	}
 }
+
+func Test_ReduceCount(t *testing.T) {
+	asserts := assert.New(t)
+
+	data := []struct {
+		repeat string
+		want   string
+		err    error
+	}{
+		{"d 7", "d 7", nil},
+		{"w 1,3 until 20250101", "w 1,3 until 20250101", nil},
+		{"d 7 count 3", "d 7 count 2", nil},
+		{"m 1,15 1,2 count 2", "m 1,15 1,2 count 1", nil},
+		{"y count 1", "", ErrNextDateSeriesEnded},
+		{"d 7 count -1", "", ErrNextDateWrongRepeat},
+	}
+
+	for _, test := range data {
+		repeat, err := ReduceCount(test.repeat)
+		asserts.ErrorIs(err, test.err, "errors should be equal")
+		asserts.Equal(test.want, repeat, "should be equal")
+	}
+
+	now, _ := time.Parse(model.DateFormat, "20240126")
+	_, err := NextDate(now, "20240113", "d 7 until 20240126")
+	asserts.ErrorIs(err, ErrNextDateSeriesEnded, "series should be ended")
+}
+
+// Test_NextDate_IntervalPhase - 'dstart' moves after every done, phase of interval should be stable
+func Test_NextDate_IntervalPhase(t *testing.T) {
+	requires := require.New(t)
+
+	data := []struct {
+		date   string
+		repeat string
+		want   []string
+	}{
+		{"20240115", "w/2 1,4", []string{"20240118", "20240129", "20240201", "20240212", "20240215"}},
+		{"20240115", "m/3 1,15", []string{"20240401", "20240415", "20240701", "20240715"}},
+		{"20240229", "y/2", []string{"20260301", "20280301"}},
+	}
+
+	for _, test := range data {
+		date := test.date
+		for _, want := range test.want {
+			now, err := time.Parse(model.DateFormat, date)
+			requires.NoError(err, "invalid date")
+
+			date, err = NextDate(now, date, test.repeat)
+			requires.NoError(err, fmt.Sprintf("valid Data but error - %v", err))
+			requires.Equal(want, date, "should be equal "+test.repeat)
+		}
+	}
+}
+
+// rules 'b' and RRULE are found without loop by days from 'dstart' to 'now'
+func Test_NextDate_FarFuture(t *testing.T) {
+	requires := require.New(t)
+
+	now := time.Date(7024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	dataForFarFuture := []struct {
+		repeat string
+		want   string
+	}{
+		{"b 5", "70240621"},
+		{"FREQ=DAILY;INTERVAL=3", "70240616"},
+		{"FREQ=WEEKLY;BYDAY=MO", "70240621"},
+		{"FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=29", "70280229"},
+		{"FREQ=MONTHLY;INTERVAL=5;BYDAY=-1FR", "70240625"},
+	}
+
+	for _, test := range dataForFarFuture {
+		nextDate, err := Lookup(DetectAlgorithm(test.repeat, AlgorithmNextDate))
+		requires.NoError(err, test.repeat)
+		date, err := nextDate(now, "20240101", test.repeat)
+		requires.NoError(err, test.repeat)
+		requires.Equal(test.want, date, test.repeat)
+	}
+}
+
+// BenchmarkNextDate_FarFuture - time of 'NextDate' and 'RRule' not depend on distance between 'dstart' and 'now'
+func BenchmarkNextDate_FarFuture(b *testing.B) {
+	repeats := []string{"w/3 2,6", "m 31 1,3,5", "m/5 -2", "n 5#5 2", "n/7 1#-1", "y/4", "b 3", "b 250",
+		"FREQ=DAILY;INTERVAL=3;BYDAY=MO", "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,FR", "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=29"}
+	nows := map[string]time.Time{
+		"now+1y":    time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC),
+		"now+100y":  time.Date(2124, 6, 15, 0, 0, 0, 0, time.UTC),
+		"now+5000y": time.Date(7024, 6, 15, 0, 0, 0, 0, time.UTC),
+	}
+	for _, repeat := range repeats {
+		for _, name := range []string{"now+1y", "now+100y", "now+5000y"} {
+			now := nows[name]
+			b.Run(repeat+"/"+name, func(b *testing.B) {
+				nextDate, err := Lookup(DetectAlgorithm(repeat, AlgorithmNextDate))
+				if err != nil {
+					b.Fatal(err)
+				}
+				for i := 0; i < b.N; i++ {
+					if _, err := nextDate(now, "20240101", repeat); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// occurrences - list of dates of series for preview
+package nextdate
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+	"github.com/Ekvo/yandex-practicum-go-final-project/pkg/common"
+)
+
+// MaxOccurrences - limit of dates in one list
+const MaxOccurrences = 366
+
+// NextDates - 'n' dates of series by algorithm from syntax of !_repeat_! string (RRULE or custom grammar)
+// look 'Occurrences'
+func NextDates(now time.Time, dstart, repeat string, n int) ([]string, error) {
+	name := DetectAlgorithm(repeat, AlgorithmNextDate)
+	nextDate, err := Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	return Occurrences(nextDate, name, now, dstart, repeat, n, time.Time{})
+}
+
+// Occurrences - dates of series not before 'now' by algorithm 'nextDate' with name 'algorithm'
+//
+// 1. 'dstart' is first date of series, added if not before 'now'
+// 2. next date is found after previous, end condition "count" is decreased after each date (as in DoneTask)
+// 3. stop: 'n' dates, date after 'to' (zero - without window) or series ended
+// algorithm added by 'Register' has no "count", series is ended only by 'nextDate'
+// empty !_repeat_! string - series of one date 'dstart'
+//
+// 'n' must be 1..MaxOccurrences
+func Occurrences(
+	nextDate NextDateFunc,
+	algorithm string,
+	now time.Time,
+	dstart, repeat string,
+	n int,
+	to time.Time) ([]string, error) {
+	if n < 1 || n > MaxOccurrences {
+		return nil, ErrNextDateUnexpectedBehavior
+	}
+	taskDateStart, err := time.Parse(model.DateFormat, dstart)
+	if err != nil {
+		return nil, ErrNextDateInvalidDate
+	}
+	// dates of series are parsed in UTC, day of 'now' is compared with them
+	now = common.ReduceTimeToDayUTC(now)
+	inWindow := func(date time.Time) bool {
+		return to.IsZero() || !date.After(to)
+	}
+	dates := make([]string, 0, n)
+	if !taskDateStart.Before(now) {
+		if !inWindow(taskDateStart) {
+			return dates, nil
+		}
+		dates = append(dates, dstart)
+		now = taskDateStart
+	}
+	if repeat == "" {
+		return dates, nil
+	}
+	for len(dates) < n {
+		date, err := nextDate(now, dstart, repeat)
+		if err != nil {
+			if errors.Is(err, ErrNextDateSeriesEnded) {
+				break
+			}
+			return nil, err
+		}
+		next, err := time.Parse(model.DateFormat, date)
+		if err != nil || !next.After(now) {
+			return nil, ErrNextDateUnexpectedBehavior
+		}
+		if !inWindow(next) {
+			break
+		}
+		dates = append(dates, date)
+		now = next
+		if len(dates) == n {
+			break
+		}
+		repeat, err = ReduceCountOf(algorithm, repeat)
+		if err != nil {
+			if errors.Is(err, ErrNextDateSeriesEnded) {
+				break
+			}
+			return nil, err
+		}
+	}
+	return dates, nil
+}
+
+// OccursOn - 'day' is date of series from 'dstart' by algorithm 'nextDate'
+//
+// 1. 'dstart' is 'day' -> true, 'dstart' after 'day' or empty !_repeat_! string -> false
+// 2. end condition "count" (not more than MaxOccurrences) -> dates of series from 'dstart' up to 'day' see 'Occurrences'
+// 3. othercase next date after previous day is compared with 'day', ended series -> false
+func OccursOn(nextDate NextDateFunc, algorithm, dstart, repeat string, day time.Time) (bool, error) {
+	taskDateStart, err := time.Parse(model.DateFormat, dstart)
+	if err != nil {
+		return false, ErrNextDateInvalidDate
+	}
+	day = common.ReduceTimeToDayUTC(day)
+	date := day.Format(model.DateFormat)
+	if taskDateStart.Equal(day) {
+		return true, nil
+	}
+	if taskDateStart.After(day) || repeat == "" {
+		return false, nil
+	}
+	if count := seriesEndOf(algorithm, repeat).count; count > 0 && count <= MaxOccurrences {
+		dates, err := Occurrences(nextDate, algorithm, taskDateStart, dstart, repeat, count, day)
+		if err != nil {
+			return false, err
+		}
+		return len(dates) > 0 && dates[len(dates)-1] == date, nil
+	}
+	next, err := nextDate(day.AddDate(0, 0, -1), dstart, repeat)
+	if err != nil {
+		if errors.Is(err, ErrNextDateSeriesEnded) {
+			return false, nil
+		}
+		return false, err
+	}
+	return next == date, nil
+}
+
+// Between - dates of series from 'dstart' in window 'from'..'to' (include) by algorithm 'nextDate'
+//
+// end condition "count" (not more than MaxOccurrences) is counted from 'dstart',
+// othercase dates are found from previous day of 'from' - series before window is not passed
+// not more than MaxOccurrences dates
+func Between(nextDate NextDateFunc, algorithm, dstart, repeat string, from, to time.Time) ([]string, error) {
+	taskDateStart, err := time.Parse(model.DateFormat, dstart)
+	if err != nil {
+		return nil, ErrNextDateInvalidDate
+	}
+	from = common.ReduceTimeToDayUTC(from)
+	now := from.AddDate(0, 0, -1)
+	if count := seriesEndOf(algorithm, repeat).count; !taskDateStart.Before(from) || (count > 0 && count <= MaxOccurrences) {
+		now = taskDateStart
+	}
+	dates, err := Occurrences(nextDate, algorithm, now, dstart, repeat, MaxOccurrences, common.ReduceTimeToDayUTC(to))
+	if err != nil {
+		return nil, err
+	}
+	first := from.Format(model.DateFormat)
+	return slices.DeleteFunc(dates, func(date string) bool {
+		return date < first
+	}), nil
+}
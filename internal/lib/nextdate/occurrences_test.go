@@ -0,0 +1,185 @@
+package nextdate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NextDates(t *testing.T) {
+	asserts := assert.New(t)
+	requires := require.New(t)
+
+	now := time.Date(2024, 1, 10, 15, 0, 0, 0, time.UTC)
+
+	dataForNextDates := []struct {
+		dstart string
+		repeat string
+		n      int
+		dates  []string
+	}{
+		{"20240101", "d 7", 3, []string{"20240115", "20240122", "20240129"}},
+		{"20240110", "d 7", 2, []string{"20240110", "20240117"}},
+		{"20240112", "w 1,5", 4, []string{"20240112", "20240115", "20240119", "20240122"}},
+		{"20240110", "d 7 count 3", 10, []string{"20240110", "20240117", "20240124"}},
+		{"20240110", "d 7 count 1", 10, []string{"20240110"}},
+		{"20240101", "m 31", 3, []string{"20240131", "20240331", "20240531"}},
+		{"20240110", "FREQ=WEEKLY;BYDAY=MO;COUNT=3", 5, []string{"20240110", "20240115", "20240122"}},
+		{"20240110", "d 7 until 20240120", 5, []string{"20240110", "20240117"}},
+	}
+
+	for _, test := range dataForNextDates {
+		dates, err := NextDates(now, test.dstart, test.repeat, test.n)
+		requires.NoError(err, test.repeat)
+		asserts.Equal(test.dates, dates, test.repeat)
+	}
+
+	// first date after 'now' is same as result of 'NextDate'
+	date, err := NextDate(now, "20240101", "w 3")
+	requires.NoError(err)
+	dates, err := NextDates(now, "20240101", "w 3", 1)
+	requires.NoError(err)
+	asserts.Equal([]string{date}, dates)
+
+	dates, err = Occurrences(NextDate, AlgorithmNextDate, now, "20240110", "d 3", MaxOccurrences, time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC))
+	requires.NoError(err)
+	asserts.Equal([]string{"20240110", "20240113", "20240116", "20240119"}, dates)
+
+	dates, err = Occurrences(NextDate, AlgorithmNextDate, now, "20240201", "d 3", 5, time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC))
+	requires.NoError(err)
+	asserts.Empty(dates)
+
+	_, err = NextDates(now, "20240101", "k 1", 3)
+	asserts.ErrorIs(err, ErrNextDateWrongRepeat)
+
+	_, err = NextDates(now, "2024011", "d 1", 3)
+	asserts.ErrorIs(err, ErrNextDateInvalidDate)
+
+	_, err = NextDates(now, "20240101", "d 1", MaxOccurrences+1)
+	asserts.ErrorIs(err, ErrNextDateUnexpectedBehavior)
+}
+
+func Test_OccursOn(t *testing.T) {
+	asserts := assert.New(t)
+	requires := require.New(t)
+
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	dataForOccursOn := []struct {
+		dstart string
+		repeat string
+		occurs bool
+	}{
+		{"20240115", "", true},
+		{"20240101", "", false},
+		{"20240120", "d 1", false},
+		{"20240101", "d 7", true},
+		{"20240101", "d 5", false},
+		{"20240101", "w 1", true},
+		{"20240101", "w 2,3", false},
+		{"20231215", "m 15", true},
+		{"20240101", "d 7 count 3", true},
+		{"20240101", "d 7 count 2", false},
+		{"20240101", "d 7 until 20240114", false},
+		{"20240101", "FREQ=WEEKLY;BYDAY=MO", true},
+	}
+
+	for _, test := range dataForOccursOn {
+		name := DetectAlgorithm(test.repeat, AlgorithmNextDate)
+		nextDate, err := Lookup(name)
+		requires.NoError(err, test.repeat)
+		occurs, err := OccursOn(nextDate, name, test.dstart, test.repeat, day)
+		requires.NoError(err, test.repeat)
+		asserts.Equal(test.occurs, occurs, test.dstart+" "+test.repeat)
+	}
+
+	occurs, err := OccursOn(Except(NextDate, "20240115"), AlgorithmNextDate, "20240101", "d 7", day)
+	requires.NoError(err)
+	asserts.False(occurs, "exception date")
+
+	_, err = OccursOn(NextDate, AlgorithmNextDate, "2024011", "d 7", day)
+	asserts.ErrorIs(err, ErrNextDateInvalidDate)
+}
+
+func Test_Between(t *testing.T) {
+	asserts := assert.New(t)
+	requires := require.New(t)
+
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)
+
+	dataForBetween := []struct {
+		dstart string
+		repeat string
+		dates  []string
+	}{
+		{"20240116", "", []string{"20240116"}},
+		{"20240110", "", []string{}},
+		{"20240101", "d 7", []string{"20240115"}},
+		{"20240114", "d 2", []string{"20240116", "20240118", "20240120"}},
+		{"20240117", "d 3", []string{"20240117", "20240120"}},
+		{"20240101", "w 1,7", []string{"20240115", "20240121"}},
+		{"20240101", "d 7 count 3", []string{"20240115"}},
+		{"20240101", "d 7 count 2", []string{}},
+		{"20240101", "d 1 until 20240116", []string{"20240115", "20240116"}},
+		{"20240125", "d 1", []string{}},
+	}
+
+	for _, test := range dataForBetween {
+		name := DetectAlgorithm(test.repeat, AlgorithmNextDate)
+		nextDate, err := Lookup(name)
+		requires.NoError(err, test.repeat)
+		dates, err := Between(nextDate, name, test.dstart, test.repeat, from, to)
+		requires.NoError(err, test.repeat)
+		asserts.Equal(test.dates, dates, test.dstart+" "+test.repeat)
+	}
+
+	dates, err := Between(Except(NextDate, "20240116"), AlgorithmNextDate, "20240114", "d 2", from, to)
+	requires.NoError(err)
+	asserts.Equal([]string{"20240118", "20240120"}, dates, "exception date")
+
+	_, err = Between(NextDate, AlgorithmNextDate, "2024011", "d 7", from, to)
+	asserts.ErrorIs(err, ErrNextDateInvalidDate)
+}
+
+// algorithm added by 'Register' has own syntax of repeat without "count"
+func Test_OccurrencesRegistered(t *testing.T) {
+	asserts := assert.New(t)
+	requires := require.New(t)
+
+	defer func() {
+		registry.Lock()
+		delete(registry.algorithms, "fortnight")
+		registry.Unlock()
+	}()
+	fortnight := func(now time.Time, dstart string, repeat string) (string, error) {
+		return NextDate(now, dstart, "d 14")
+	}
+	requires.NoError(Register("fortnight", fortnight))
+	asserts.False(IsBuiltIn("fortnight"))
+	asserts.True(IsBuiltIn(AlgorithmRRule))
+
+	repeat, err := ReduceCountOf("fortnight", "every other week")
+	requires.NoError(err)
+	asserts.Equal("every other week", repeat)
+	repeat, err = ReduceCountOf(AlgorithmNextDate, "d 7 count 3")
+	requires.NoError(err)
+	asserts.Equal("d 7 count 2", repeat)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	dates, err := Occurrences(fortnight, "fortnight", now, "20240101", "every other week", 3, time.Time{})
+	requires.NoError(err)
+	asserts.Equal([]string{"20240101", "20240115", "20240129"}, dates)
+
+	occurs, err := OccursOn(fortnight, "fortnight", "20240101", "every other week", time.Date(2024, 1, 29, 0, 0, 0, 0, time.UTC))
+	requires.NoError(err)
+	asserts.True(occurs)
+
+	from := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	dates, err = Between(fortnight, "fortnight", "20240101", "every other week", from, to)
+	requires.NoError(err)
+	asserts.Equal([]string{"20240115", "20240129"}, dates)
+}
@@ -0,0 +1,143 @@
+// registry - algorithms of type 'NextDateFunc' by name
+//
+// default algorithm of application - ALGORITHM_TASK_DATE from config
+// every task store name of algorithm for own !_repeat_! string
+package nextdate
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrNextDateUnknownAlgorithm - algorithm with name not registered
+	ErrNextDateUnknownAlgorithm = errors.New("unknown algorithm")
+
+	// ErrNextDateAlgorithmExist - name of algorithm is empty or already taken
+	ErrNextDateAlgorithmExist = errors.New("algorithm already registered")
+)
+
+// names of built-in algorithms
+const (
+	AlgorithmNextDate = "nextdate" // custom grammar: d, w, m, y, n, b
+	AlgorithmRRule    = "rrule"    // RFC 5545
+)
+
+// MaxAlgorithmLen - max length of name, database store it in VARCHAR(16)
+const MaxAlgorithmLen = 16
+
+var registry = struct {
+	sync.RWMutex
+	algorithms map[string]NextDateFunc
+}{
+	algorithms: map[string]NextDateFunc{
+		AlgorithmNextDate: NextDate,
+		AlgorithmRRule:    RRule,
+	},
+}
+
+// validators - check syntax of !_repeat_! string for built-in algorithms, return normalized form
+var validators = map[string]func(repeat string) (string, error){
+	AlgorithmNextDate: func(repeat string) (string, error) {
+		if _, err := ParseRule(repeat); err != nil {
+			return "", err
+		}
+		return repeat, nil
+	},
+	AlgorithmRRule: NormalizeRRule,
+}
+
+// Register - add algorithm by name, call before start of application
+func Register(name string, fn NextDateFunc) error {
+	if name == "" || len(name) > MaxAlgorithmLen || fn == nil {
+		return ErrNextDateAlgorithmExist
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	if _, ex := registry.algorithms[name]; ex {
+		return ErrNextDateAlgorithmExist
+	}
+	registry.algorithms[name] = fn
+	return nil
+}
+
+// Lookup - return algorithm by name
+func Lookup(name string) (NextDateFunc, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+	fn, ex := registry.algorithms[name]
+	if !ex {
+		return nil, ErrNextDateUnknownAlgorithm
+	}
+	return fn, nil
+}
+
+// Algorithms - sorted names of all registered algorithms
+func Algorithms() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	names := make([]string, 0, len(registry.algorithms))
+	for name := range registry.algorithms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DetectAlgorithm - name of algorithm by syntax of !_repeat_! string,
+// RRULE syntax -> "rrule", othercase 'defaultName'
+func DetectAlgorithm(repeat, defaultName string) string {
+	if IsRRule(repeat) {
+		return AlgorithmRRule
+	}
+	return defaultName
+}
+
+// IsBuiltIn - algorithm with syntax of !_repeat_! string known to package (custom grammar or RRULE)
+// only its series have end condition "count" and working days, algorithm added by 'Register' -> false
+func IsBuiltIn(name string) bool {
+	_, ex := validators[name]
+	return ex
+}
+
+// ReduceCountOf - 'ReduceCount' for !_repeat_! string of algorithm 'name'
+// algorithm added by 'Register' has no "count" -> !_repeat_! string without change
+func ReduceCountOf(name, repeat string) (string, error) {
+	if !IsBuiltIn(name) {
+		return repeat, nil
+	}
+	return ReduceCount(repeat)
+}
+
+// WithoutCountOf - !_repeat_! string of algorithm 'name' without end condition "count" see 'withoutCount'
+// algorithm added by 'Register' has no "count" -> !_repeat_! string without change
+func WithoutCountOf(name, repeat string) (string, error) {
+	if !IsBuiltIn(name) {
+		return repeat, nil
+	}
+	return withoutCount(repeat)
+}
+
+// seriesEndOf - end condition of !_repeat_! string of algorithm 'name', algorithm added by 'Register' -> endless
+func seriesEndOf(name, repeat string) endCondition {
+	if !IsBuiltIn(name) {
+		return endCondition{}
+	}
+	return seriesEnd(repeat)
+}
+
+// Validate - check !_repeat_! string by syntax of algorithm without calculation of date
+// return normalized !_repeat_! string, wrong syntax -> *ParseError
+//
+// algorithm added by 'Register' has no validator -> !_repeat_! string without change
+func Validate(name, repeat string) (string, error) {
+	if _, err := Lookup(name); err != nil {
+		return "", err
+	}
+	validator, ex := validators[name]
+	if !ex {
+		return repeat, nil
+	}
+	return validator(repeat)
+}
@@ -0,0 +1,40 @@
+package nextdate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Registry(t *testing.T) {
+	requires := require.New(t)
+
+	requires.Equal([]string{AlgorithmNextDate, AlgorithmRRule}, Algorithms())
+	defer func() {
+		registry.Lock()
+		delete(registry.algorithms, "test")
+		registry.Unlock()
+	}()
+
+	fn := func(now time.Time, dstart string, repeat string) (string, error) { return dstart, nil }
+
+	requires.NoError(Register("test", fn))
+	requires.ErrorIs(Register("test", fn), ErrNextDateAlgorithmExist, "name is taken")
+	requires.ErrorIs(Register(AlgorithmNextDate, fn), ErrNextDateAlgorithmExist, "name is taken")
+	requires.ErrorIs(Register("", fn), ErrNextDateAlgorithmExist, "empty name")
+	requires.ErrorIs(Register("name-longer-than-sixteen", fn), ErrNextDateAlgorithmExist, "long name")
+	requires.ErrorIs(Register("nil", nil), ErrNextDateAlgorithmExist, "nil algorithm")
+
+	got, err := Lookup("test")
+	requires.NoError(err)
+	date, err := got(time.Now(), "20240101", "any")
+	requires.NoError(err)
+	requires.Equal("20240101", date)
+
+	_, err = Lookup("alien")
+	requires.ErrorIs(err, ErrNextDateUnknownAlgorithm)
+
+	requires.Equal(AlgorithmRRule, DetectAlgorithm("RRULE:FREQ=DAILY", AlgorithmNextDate))
+	requires.Equal("test", DetectAlgorithm("d 1", "test"))
+}
@@ -0,0 +1,569 @@
+// rrule - describes function 'RRule' - algorithm for finding next date for task by iCalendar RRULE (RFC 5545)
+//
+// supported parts: FREQ (DAILY, WEEKLY, MONTHLY, YEARLY), INTERVAL, BYDAY, BYMONTHDAY, BYMONTH, COUNT, UNTIL, WKST=MO
+// example: FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE
+package nextdate
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+	"github.com/Ekvo/yandex-practicum-go-final-project/pkg/common"
+)
+
+// ErrNextDateNoRRule - rule of custom grammar can't be written as RRULE (flag 'b', clause "shift")
+var ErrNextDateNoRRule = errors.New("rule has no RRULE form")
+
+const (
+	rrulePrefix = "RRULE:"
+
+	freqDaily   = "DAILY"
+	freqWeekly  = "WEEKLY"
+	freqMonthly = "MONTHLY"
+	freqYearly  = "YEARLY"
+
+	// maxRRuleYears - limit of search in 'next' from day after 'now', not depend on distance to 'now'
+	// calendar (weekdays and leap years) is repeated every 400 years
+	maxRRuleYears = 400
+
+	// calendarCycleYears - every month (february of 28 and 29 days) starts with every weekday
+	// in 28 years without year of century (2001..2028), see 'canMatch'
+	calendarCycleYears = 28
+)
+
+// rruleWeekdays - index is time.Weekday
+var rruleWeekdays = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+type rrule struct {
+	freq     string
+	interval int
+
+	// ordinal == 0 - every weekday
+	byDay      []ordinalWeekday
+	byMonthDay []int
+	byMonth    []bool
+
+	end endCondition
+}
+
+// IsRRule - !_repeat_! string is written in RRULE syntax
+func IsRRule(repeat string) bool {
+	upper := strings.ToUpper(repeat)
+	return strings.HasPrefix(upper, rrulePrefix) || strings.HasPrefix(upper, "FREQ=")
+}
+
+// RRule - algorithm 'NextDateFunc' for !_repeat_! string in RRULE syntax
+//
+// 'dstart' is counted as the first occurrence of the series (DTSTART), COUNT - number of occurrences left
+func RRule(now time.Time, dstart string, repeat string) (string, error) {
+	rule, err := parseRRule(repeat)
+	if err != nil {
+		return "", err
+	}
+	taskDateStart, err := time.Parse(model.DateFormat, dstart)
+	if err != nil {
+		return "", ErrNextDateInvalidDate
+	}
+	if !rule.end.until.IsZero() && taskDateStart.After(rule.end.until) {
+		return "", ErrNextDateWrongRepeat
+	}
+	now = common.ReduceTimeToDayUTC(now)
+	newDate, err := rule.next(now, taskDateStart)
+	if err != nil {
+		return "", err
+	}
+	if rule.end.isReached(newDate) {
+		return "", ErrNextDateSeriesEnded
+	}
+	return newDate.Format(model.DateFormat), nil
+}
+
+// NormalizeRRule - return RRULE in canonical form:
+// without prefix "RRULE:", upper case, fixed order of parts, sorted values, INTERVAL=1 is skipped
+func NormalizeRRule(repeat string) (string, error) {
+	rule, err := parseRRule(repeat)
+	if err != nil {
+		return "", err
+	}
+	return rule.String(), nil
+}
+
+// next - first date after 'now' and after 'start' which match the rule
+//
+// 1. jump to first period (day, week, month, year) in phase of INTERVAL from 'start' not before day after 'now'
+// 2. days of period are checked by 'match', month out of BYMONTH is skipped at once
+// 3. period without date -> next period in phase, no date in maxRRuleYears -> ErrNextDateWrongRepeat
+//
+// no date: day of 'start' out of BYMONTH or INTERVAL never reaches BYMONTH (FREQ=MONTHLY;INTERVAL=12;BYMONTH=3)
+func (r rrule) next(now, start time.Time) (time.Time, error) {
+	from := start
+	if !from.UTC().After(now.UTC()) {
+		from = now
+	}
+	period, from := r.inPhase(start, from.AddDate(0, 0, 1))
+	limit := from.AddDate(maxRRuleYears, 0, 0)
+	for period.Before(limit) {
+		if date, ok := r.inPeriod(start, period, from); ok {
+			return date, nil
+		}
+		period = r.addPeriods(period, r.interval)
+		from = period
+		if !r.monthAllowed(start, from.Month()) && (r.freq == freqDaily || r.freq == freqWeekly) {
+			// periods shorter than month: jump over months out of BYMONTH
+			period, from = r.inPhase(start, r.nextMonth(start, from))
+		}
+	}
+	return time.Time{}, fmt.Errorf("nextdate: rrule - %w: no date in %d years", ErrNextDateWrongRepeat, maxRRuleYears)
+}
+
+// inPhase - first period in phase of INTERVAL from period of 'start' which contains 'date' or after it
+// return beginning of period and first day for check (not before 'date')
+func (r rrule) inPhase(start, date time.Time) (time.Time, time.Time) {
+	period := r.beginningOfPeriod(date)
+	if shift := floorMod(r.periodNumber(period)-r.periodNumber(start), r.interval); shift != 0 {
+		period = r.addPeriods(period, r.interval-shift)
+		return period, period
+	}
+	return period, date
+}
+
+// inPeriod - first date from 'from' up to end of 'period' which match the rule
+func (r rrule) inPeriod(start, period, from time.Time) (time.Time, bool) {
+	end := r.addPeriods(period, 1)
+	for date := from; date.Before(end); {
+		if !r.monthAllowed(start, date.Month()) {
+			date = common.BeginningOfMonth(date).AddDate(0, 1, 0)
+			continue
+		}
+		if r.match(start, date) {
+			return date, true
+		}
+		date = date.AddDate(0, 0, 1)
+	}
+	return time.Time{}, false
+}
+
+// monthAllowed - dates of 'month' can match the rule:
+// month of BYMONTH, YEARLY without BYMONTH, BYDAY and BYMONTHDAY - only month of 'start'
+func (r rrule) monthAllowed(start time.Time, month time.Month) bool {
+	if r.byMonth != nil {
+		return r.byMonth[month]
+	}
+	if r.freq == freqYearly && len(r.byDay) == 0 && len(r.byMonthDay) == 0 {
+		return month == start.Month()
+	}
+	return true
+}
+
+// nextMonth - beginning of first allowed month after month of 'date', no more than one year
+func (r rrule) nextMonth(start, date time.Time) time.Time {
+	month := common.BeginningOfMonth(date).AddDate(0, 1, 0)
+	for i := 0; i < 12 && !r.monthAllowed(start, month.Month()); i++ {
+		month = month.AddDate(0, 1, 0)
+	}
+	return month
+}
+
+// periodNumber - number of period of 'date' by FREQ (see 'dayNumber', 'weekNumber', 'monthNumber')
+func (r rrule) periodNumber(date time.Time) int {
+	switch r.freq {
+	case freqDaily:
+		return dayNumber(date)
+	case freqWeekly:
+		return weekNumber(date)
+	case freqMonthly:
+		return monthNumber(date)
+	}
+	return date.Year()
+}
+
+// beginningOfPeriod - first day of period of 'date' by FREQ, week starts from monday (WKST=MO)
+func (r rrule) beginningOfPeriod(date time.Time) time.Time {
+	switch r.freq {
+	case freqDaily:
+		return date
+	case freqWeekly:
+		// monday = 0, sunday = 6
+		return date.AddDate(0, 0, -((int(date.Weekday()) + 6) % 7))
+	case freqMonthly:
+		return common.BeginningOfMonth(date)
+	}
+	return time.Date(date.Year(), time.January, 1, 0, 0, 0, 0, date.Location())
+}
+
+// addPeriods - beginning of period 'n' periods after 'period' by FREQ
+func (r rrule) addPeriods(period time.Time, n int) time.Time {
+	switch r.freq {
+	case freqDaily:
+		return period.AddDate(0, 0, n)
+	case freqWeekly:
+		return period.AddDate(0, 0, 7*n)
+	case freqMonthly:
+		return period.AddDate(0, n, 0)
+	}
+	return period.AddDate(n, 0, 0)
+}
+
+// match - check BYDAY, BYMONTHDAY or default values from 'start' (RFC 5545)
+func (r rrule) match(start, date time.Time) bool {
+	byDay, byMonthDay := r.byDay, r.byMonthDay
+	if len(byDay) == 0 && len(byMonthDay) == 0 {
+		switch r.freq {
+		case freqWeekly:
+			byDay = []ordinalWeekday{{weekday: start.Weekday()}}
+		case freqMonthly:
+			byMonthDay = []int{start.Day()}
+		case freqYearly:
+			if r.byMonth == nil && date.Month() != start.Month() {
+				return false
+			}
+			byMonthDay = []int{start.Day()}
+		}
+	}
+	if len(byMonthDay) > 0 && !matchMonthDay(byMonthDay, date) {
+		return false
+	}
+	if len(byDay) > 0 && !matchDay(byDay, date) {
+		return false
+	}
+	return true
+}
+
+func matchMonthDay(days []int, date time.Time) bool {
+	lastDay := common.BeginningOfMonth(date).AddDate(0, 1, -1).Day()
+	for _, day := range days {
+		if day == date.Day() || (day < 0 && lastDay+1+day == date.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchDay(days []ordinalWeekday, date time.Time) bool {
+	fromBeginning := ordinalOfWeekday(date)
+	fromEnd := ordinalOfWeekdayFromEnd(date)
+	for _, day := range days {
+		if day.weekday != date.Weekday() {
+			continue
+		}
+		if day.ordinal == 0 || day.ordinal == fromBeginning.ordinal || day.ordinal == fromEnd.ordinal {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRRule - check all parts of RRULE, every part only once
+// error - *ParseError, position from beginning of !_repeat_! string
+func parseRRule(repeat string) (rrule, error) {
+	rule := rrule{interval: 1}
+	line, offset := strings.ToUpper(repeat), 0
+	if strings.HasPrefix(line, rrulePrefix) {
+		line, offset = line[len(rrulePrefix):], len(rrulePrefix)
+	}
+	if line == "" {
+		return rule, parseError(offset, "empty rule")
+	}
+	parts, err := splitFields(line, ';', offset)
+	if err != nil {
+		return rule, err
+	}
+	keys := make(map[string]int)
+	for _, part := range parts {
+		key, value, ok := strings.Cut(part.text, "=")
+		if !ok {
+			return rule, parseError(part.pos, "expected KEY=VALUE")
+		}
+		if _, ex := keys[key]; ex {
+			return rule, parseError(part.pos, fmt.Sprintf("part %s is repeated", key))
+		}
+		keys[key] = part.pos
+		valuePos := part.pos + len(key) + 1
+		if value == "" {
+			return rule, parseError(valuePos, fmt.Sprintf("value of %s is missing", key))
+		}
+		if err := rule.setPart(key, field{text: value, pos: valuePos}); err != nil {
+			return rule, err
+		}
+	}
+	return rule, rule.valid(keys)
+}
+
+// setPart - set value of RRULE part by key
+func (r *rrule) setPart(key string, value field) error {
+	switch key {
+	case "FREQ":
+		if value.text != freqDaily && value.text != freqWeekly &&
+			value.text != freqMonthly && value.text != freqYearly {
+			return parseError(value.pos, "FREQ must be DAILY, WEEKLY, MONTHLY or YEARLY")
+		}
+		r.freq = value.text
+	case "INTERVAL":
+		every, ok := number(value.text)
+		if !ok || every < 1 || every > maxDay {
+			return parseError(value.pos, fmt.Sprintf("INTERVAL must be number 1..%d", maxDay))
+		}
+		r.interval = every
+	case "COUNT":
+		n, err := strconv.Atoi(value.text)
+		if err != nil || n < 1 {
+			return parseError(value.pos, "COUNT must be positive number")
+		}
+		r.end.count = n
+	case "UNTIL":
+		// 20250101 or 20250101T000000Z
+		text := value.text
+		if len(text) > len(model.DateFormat) && text[len(model.DateFormat)] == 'T' {
+			text = text[:len(model.DateFormat)]
+		}
+		date, err := time.Parse(model.DateFormat, text)
+		if err != nil {
+			return parseError(value.pos, "UNTIL must be in format 20060102 or 20060102T150405Z")
+		}
+		r.end.until = date
+	case "WKST":
+		if value.text != rruleWeekdays[time.Monday] {
+			return parseError(value.pos, "only WKST=MO is supported")
+		}
+	case "BYMONTH":
+		items, err := splitFields(value.text, ',', value.pos)
+		if err != nil {
+			return err
+		}
+		r.byMonth = make([]bool, time.December+1)
+		for _, item := range items {
+			month, ok := number(item.text)
+			if !ok || month < int(time.January) || month > int(time.December) {
+				return parseError(item.pos, "month must be 1..12")
+			}
+			if r.byMonth[month] {
+				return parseError(item.pos, "month is repeated")
+			}
+			r.byMonth[month] = true
+		}
+	case "BYMONTHDAY":
+		items, err := splitFields(value.text, ',', value.pos)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			day, err := strconv.Atoi(item.text)
+			if err != nil || day == 0 || common.Abs(day) > maxDaysPerMonth {
+				return parseError(item.pos, fmt.Sprintf("day of month must be 1..%d or -%d..-1",
+					maxDaysPerMonth, maxDaysPerMonth))
+			}
+			if slices.Contains(r.byMonthDay, day) {
+				return parseError(item.pos, "day of month is repeated")
+			}
+			r.byMonthDay = append(r.byMonthDay, day)
+		}
+	case "BYDAY":
+		items, err := splitFields(value.text, ',', value.pos)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			day, err := parseRRuleDay(item)
+			if err != nil {
+				return err
+			}
+			if slices.Contains(r.byDay, day) {
+				return parseError(item.pos, "day is repeated")
+			}
+			r.byDay = append(r.byDay, day)
+		}
+	default:
+		return parseError(value.pos-len(key)-1, fmt.Sprintf("part %s is not supported", key))
+	}
+	return nil
+}
+
+// valid - check compatibility of parts, 'keys' - position of part by key (can be nil)
+func (r *rrule) valid(keys map[string]int) error {
+	if r.freq == "" {
+		return parseError(0, "FREQ is required")
+	}
+	if r.end.count != 0 && !r.end.until.IsZero() {
+		return parseError(max(keys["COUNT"], keys["UNTIL"]), "UNTIL and COUNT can't be used together")
+	}
+	if r.byMonth != nil && len(r.byMonthDay) > 0 && !existInMonths(r.byMonthDay, r.byMonth) {
+		return parseError(keys["BYMONTHDAY"], "BYMONTHDAY never exists in BYMONTH")
+	}
+	for _, day := range r.byDay {
+		if day.ordinal == 0 {
+			continue
+		}
+		// ordinal of weekday only inside month
+		if r.freq == freqDaily || r.freq == freqWeekly || (r.freq == freqYearly && r.byMonth == nil) {
+			return parseError(keys["BYDAY"], "ordinal of BYDAY only for MONTHLY or YEARLY with BYMONTH")
+		}
+	}
+	if (len(r.byDay) > 0 || len(r.byMonthDay) > 0) && !r.canMatch() {
+		return parseError(max(keys["BYDAY"], keys["BYMONTHDAY"]), "BYDAY, BYMONTHDAY and BYMONTH never match together")
+	}
+	return nil
+}
+
+// canMatch - some date of calendar fits BYDAY, BYMONTHDAY and BYMONTH: FREQ=MONTHLY;BYMONTHDAY=1;BYDAY=-1MO - never
+// only for rule with BYDAY or BYMONTHDAY (without them 'match' depends on start of series)
+func (r *rrule) canMatch() bool {
+	first := time.Date(2001, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := first.AddDate(calendarCycleYears, 0, 0)
+	for date := first; date.Before(end); date = date.AddDate(0, 0, 1) {
+		if r.monthAllowed(first, date.Month()) && r.match(first, date) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRRuleDay - example: MO, 2TU, -1FR, +3WE
+func parseRRuleDay(item field) (ordinalWeekday, error) {
+	day := ordinalWeekday{weekday: -1}
+	if n := len(item.text); n >= 2 {
+		name := item.text[n-2:]
+		for i, weekday := range rruleWeekdays {
+			if weekday == name {
+				day.weekday = time.Weekday(i)
+			}
+		}
+	}
+	if day.weekday < 0 {
+		return ordinalWeekday{}, parseError(item.pos, "day must be MO, TU, WE, TH, FR, SA or SU with optional ordinal")
+	}
+	if ordinal := item.text[:len(item.text)-2]; ordinal != "" {
+		number, err := strconv.Atoi(ordinal)
+		if err != nil || number == 0 || common.Abs(number) > maxOrdinal {
+			return ordinalWeekday{}, parseError(item.pos,
+				fmt.Sprintf("ordinal must be %d..%d or -%d..-%d", 1, maxOrdinal, 1, maxOrdinal))
+		}
+		day.ordinal = number
+	}
+	return day, nil
+}
+
+// String - canonical form of RRULE
+func (r rrule) String() string {
+	parts := []string{"FREQ=" + r.freq}
+	if r.interval != 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.interval))
+	}
+	if r.byMonth != nil {
+		months := make([]string, 0, len(r.byMonth))
+		for m := time.January; m <= time.December; m++ {
+			if r.byMonth[m] {
+				months = append(months, strconv.Itoa(int(m)))
+			}
+		}
+		parts = append(parts, "BYMONTH="+strings.Join(months, ","))
+	}
+	if len(r.byMonthDay) > 0 {
+		days := append([]int(nil), r.byMonthDay...)
+		// positive ascending, after negative from end of month
+		sort.Slice(days, func(i, j int) bool {
+			if (days[i] < 0) != (days[j] < 0) {
+				return days[i] > 0
+			}
+			return common.Abs(days[i]) < common.Abs(days[j])
+		})
+		items := make([]string, 0, len(days))
+		for _, day := range days {
+			items = append(items, strconv.Itoa(day))
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(items, ","))
+	}
+	if len(r.byDay) > 0 {
+		days := append([]ordinalWeekday(nil), r.byDay...)
+		// monday first
+		sort.Slice(days, func(i, j int) bool {
+			wi, wj := (int(days[i].weekday)+6)%7, (int(days[j].weekday)+6)%7
+			if wi != wj {
+				return wi < wj
+			}
+			return days[i].ordinal < days[j].ordinal
+		})
+		items := make([]string, 0, len(days))
+		for _, day := range days {
+			item := rruleWeekdays[day.weekday]
+			if day.ordinal != 0 {
+				item = strconv.Itoa(day.ordinal) + item
+			}
+			items = append(items, item)
+		}
+		parts = append(parts, "BYDAY="+strings.Join(items, ","))
+	}
+	if r.end.count != 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.end.count))
+	}
+	if !r.end.until.IsZero() {
+		parts = append(parts, "UNTIL="+r.end.until.Format(model.DateFormat))
+	}
+	return strings.Join(parts, ";")
+}
+
+// reduceRRuleCount - the same as 'ReduceCount' for RRULE
+func reduceRRuleCount(repeat string) (string, error) {
+	rule, err := parseRRule(repeat)
+	if err != nil {
+		return "", err
+	}
+	if rule.end.count == 0 {
+		return repeat, nil
+	}
+	if rule.end.count == 1 {
+		return "", ErrNextDateSeriesEnded
+	}
+	rule.end.count--
+	return rule.String(), nil
+}
+
+// ToRRule - write !_repeat_! string of custom grammar (d, w, m, y, n) as RRULE in canonical form
+// RRULE -> normalize
+func ToRRule(repeat string) (string, error) {
+	if IsRRule(repeat) {
+		return NormalizeRRule(repeat)
+	}
+	rule, err := ParseRule(repeat)
+	if err != nil {
+		return "", err
+	}
+	if rule.shift != 0 {
+		return "", ErrNextDateNoRRule
+	}
+	res := rrule{interval: rule.every, end: rule.end, byMonth: rule.months}
+	switch rule.flag {
+	case day:
+		res.freq, res.interval = freqDaily, rule.days
+	case weak:
+		res.freq = freqWeekly
+		for d := monday; d <= sunday; d++ {
+			if weekday := time.Weekday(d % 7); rule.weekdays[weekday] {
+				res.byDay = append(res.byDay, ordinalWeekday{weekday: weekday})
+			}
+		}
+	case month:
+		res.freq = freqMonthly
+		for d := range rule.monthDays {
+			res.byMonthDay = append(res.byMonthDay, d)
+		}
+	case year:
+		res.freq = freqYearly
+	case nthWeekday:
+		res.freq = freqMonthly
+		for d := range rule.nthWeekdays {
+			res.byDay = append(res.byDay, d)
+		}
+	case businessDay, hour:
+		return "", ErrNextDateNoRRule
+	}
+	if err := res.valid(nil); err != nil {
+		return "", fmt.Errorf("nextdate: rrule - %w", err)
+	}
+	return res.String(), nil
+}
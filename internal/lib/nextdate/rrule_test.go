@@ -0,0 +1,147 @@
+package nextdate
+
+import (
+	"fmt"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+)
+
+func Test_RRule(t *testing.T) {
+	asserts := assert.New(t)
+	requires := require.New(t)
+
+	now, _ := time.Parse(model.DateFormat, "20240126")
+
+	validData := []struct {
+		date   string
+		repeat string
+		want   string
+	}{
+		{"20240126", "FREQ=DAILY", "20240127"},
+		{"20240120", "FREQ=DAILY;INTERVAL=4", "20240128"},
+		{"20240201", "FREQ=DAILY;INTERVAL=3", "20240204"},
+		{"20240122", "FREQ=WEEKLY", "20240129"},
+		{"20240103", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE", "20240129"},
+		{"20240101", "RRULE:FREQ=WEEKLY;BYDAY=SU;WKST=MO", "20240128"},
+		{"20240131", "FREQ=MONTHLY", "20240331"},
+		{"20240101", "FREQ=MONTHLY;BYMONTHDAY=-1", "20240131"},
+		{"20240101", "FREQ=MONTHLY;BYDAY=-1FR", "20240223"},
+		{"20240101", "FREQ=MONTHLY;BYDAY=2TU;BYMONTH=3,6", "20240312"},
+		{"20240101", "FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=13;BYDAY=FR", "20240913"},
+		{"20200229", "FREQ=YEARLY", "20240229"},
+		{"20230131", "FREQ=YEARLY;INTERVAL=2", "20250131"},
+		{"20200229", "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=29", "20240229"},
+		{"20240101", "FREQ=YEARLY;BYMONTH=11;BYDAY=4TH", "20241128"},
+		{"20240101", "freq=daily;count=2", "20240127"},
+		{"20240101", "FREQ=DAILY;UNTIL=20240127T000000Z", "20240127"},
+	}
+
+	for i, test := range validData {
+		log.Printf("\ttest: %d", i+1)
+
+		getDate, err := RRule(now, test.date, test.repeat)
+
+		requires.NoError(err, fmt.Sprintf("valid Data but error - %v", err))
+		asserts.Equal(test.want, getDate, "should be equal "+test.repeat)
+	}
+
+	invalidData := []struct {
+		date   string
+		repeat string
+		err    error
+	}{
+		{"20240126", "", ErrNextDateWrongRepeat},
+		{"20240126", "RRULE:", ErrNextDateWrongRepeat},
+		{"20240126", "FREQ=HOURLY", ErrNextDateWrongRepeat},
+		{"20240126", "INTERVAL=2", ErrNextDateWrongRepeat},
+		{"20240126", "FREQ=DAILY;FREQ=WEEKLY", ErrNextDateWrongRepeat},
+		{"20240126", "FREQ=DAILY;INTERVAL=0", ErrNextDateWrongRepeat},
+		{"20240126", "FREQ=WEEKLY;BYDAY=1MO", ErrNextDateWrongRepeat},
+		{"20240126", "FREQ=YEARLY;BYDAY=1MO", ErrNextDateWrongRepeat},
+		{"20240126", "FREQ=MONTHLY;BYDAY=6MO", ErrNextDateWrongRepeat},
+		{"20240126", "FREQ=MONTHLY;BYMONTHDAY=32", ErrNextDateWrongRepeat},
+		{"20240126", "FREQ=MONTHLY;BYMONTH=13", ErrNextDateWrongRepeat},
+		{"20240126", "FREQ=DAILY;COUNT=2;UNTIL=20250101", ErrNextDateWrongRepeat},
+		{"20240126", "FREQ=DAILY;BYSETPOS=1", ErrNextDateWrongRepeat},
+		{"20240126", "FREQ=DAILY;WKST=SU", ErrNextDateWrongRepeat},
+		{"20240126", "FREQ=DAILY;UNTIL=20240101", ErrNextDateWrongRepeat},
+		{"20240126", "FREQ=MONTHLY;BYMONTHDAY=1;BYDAY=-1MO", ErrNextDateWrongRepeat},
+		{"20240126", "FREQ=MONTHLY;BYMONTHDAY=8;BYDAY=1MO", ErrNextDateWrongRepeat},
+		{"20240126", "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=-1;BYDAY=1MO", ErrNextDateWrongRepeat},
+		{"20240126", "FREQ=MONTHLY;INTERVAL=12;BYMONTH=3", ErrNextDateWrongRepeat},
+		{"20240131", "FREQ=MONTHLY;BYMONTH=2", ErrNextDateWrongRepeat},
+		{"2024012", "FREQ=DAILY", ErrNextDateInvalidDate},
+		{"20240120", "FREQ=DAILY;UNTIL=20240126", ErrNextDateSeriesEnded},
+		{"20240126", "FREQ=DAILY;COUNT=1", ErrNextDateSeriesEnded},
+	}
+
+	for i, test := range invalidData {
+		log.Printf("\ttest: %d", i+1)
+
+		getDate, err := RRule(now, test.date, test.repeat)
+		requires.ErrorIs(err, test.err, "invalid Data but other error "+test.repeat)
+		asserts.Empty(getDate, "should be empty")
+	}
+}
+
+func Test_ToRRule(t *testing.T) {
+	asserts := assert.New(t)
+
+	validData := []struct {
+		repeat string
+		want   string
+	}{
+		{"rrule:byday=we,-1fr,mo;freq=monthly", "FREQ=MONTHLY;BYDAY=MO,WE,-1FR"},
+		{"FREQ=MONTHLY;INTERVAL=1;BYMONTHDAY=-1,3,1", "FREQ=MONTHLY;BYMONTHDAY=1,3,-1"},
+		{"d 5", "FREQ=DAILY;INTERVAL=5"},
+		{"w 7,1,3", "FREQ=WEEKLY;BYDAY=MO,WE,SU"},
+		{"w/2 5", "FREQ=WEEKLY;INTERVAL=2;BYDAY=FR"},
+		{"m -1,15 1,7", "FREQ=MONTHLY;BYMONTH=1,7;BYMONTHDAY=15,-1"},
+		{"y count 3", "FREQ=YEARLY;COUNT=3"},
+		{"n 2#2,5#-1 until 20250101", "FREQ=MONTHLY;BYDAY=2TU,-1FR;UNTIL=20250101"},
+	}
+
+	for _, test := range validData {
+		getRule, err := ToRRule(test.repeat)
+		asserts.NoError(err, fmt.Sprintf("valid Data but error - %v", err))
+		asserts.Equal(test.want, getRule, "should be equal "+test.repeat)
+	}
+
+	invalidData := []struct {
+		repeat string
+		err    error
+	}{
+		{"b 1", ErrNextDateNoRRule},
+		{"d 1 shift next", ErrNextDateNoRRule},
+		{"k 1", ErrNextDateWrongRepeat},
+		{"m/2 1 3", ErrNextDateWrongRepeat},
+		{"FREQ=SECONDLY", ErrNextDateWrongRepeat},
+	}
+
+	for _, test := range invalidData {
+		getRule, err := ToRRule(test.repeat)
+		asserts.ErrorIs(err, test.err, "invalid Data but other error "+test.repeat)
+		asserts.Empty(getRule, "should be empty")
+	}
+}
+
+func Test_ReduceCount_RRule(t *testing.T) {
+	requires := require.New(t)
+
+	repeat, err := ReduceCount("FREQ=DAILY;COUNT=2")
+	requires.NoError(err)
+	requires.Equal("FREQ=DAILY;COUNT=1", repeat)
+
+	_, err = ReduceCount(repeat)
+	requires.ErrorIs(err, ErrNextDateSeriesEnded)
+
+	repeat, err = ReduceCount("FREQ=DAILY")
+	requires.NoError(err)
+	requires.Equal("FREQ=DAILY", repeat)
+}
@@ -0,0 +1,425 @@
+// rule - parsed !_repeat_! string of custom grammar
+//
+// grammar: flag[/interval] [argument(s)] [clause value]...
+// example: "w/2 1,4 until 20250101 shift next"
+package nextdate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+)
+
+// ParseError - wrong !_repeat_! string with position (byte from 0) and reason
+// errors.Is(err, ErrNextDateWrongRepeat) - true
+type ParseError struct {
+	Pos    int
+	Reason string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s at position %d: %s", ErrNextDateWrongRepeat, e.Pos, e.Reason)
+}
+
+func (e *ParseError) Unwrap() error {
+	return ErrNextDateWrongRepeat
+}
+
+func parseError(pos int, reason string) error {
+	return &ParseError{Pos: pos, Reason: reason}
+}
+
+// Rule - result of 'ParseRule', checked once and used for find next date(s)
+type Rule struct {
+	flag byte
+
+	// every - interval of week(s), month(s), year(s), 1 - without interval
+	every int
+
+	// days - number of days for flags 'd', 'b'
+	days int
+
+	// hours - number of hours for flag 'h'
+	hours int
+
+	// window - hours of day for flag 'h', zero value - whole day
+	window timeWindow
+
+	// weekdays - flag 'w', index is time.Weekday
+	weekdays []bool
+
+	// monthDays - flag 'm', 1..31, -1 - last day, -2 - penultimate day
+	monthDays map[int]bool
+
+	// nthWeekdays - flag 'n'
+	nthWeekdays map[ordinalWeekday]bool
+
+	// months - flags 'm', 'n', index is time.Month, nil - every month
+	months []bool
+
+	end endCondition
+
+	// shift - direction for move date to workday: 0 - not move, 1 - next, -1 - previous
+	shift int
+}
+
+// field - part of !_repeat_! string between spaces
+type field struct {
+	text string
+	pos  int
+}
+
+// ParseRule - check !_repeat_! string of custom grammar and create 'Rule'
+// error - *ParseError
+func ParseRule(repeat string) (*Rule, error) {
+	if repeat == "" {
+		return nil, parseError(0, "empty rule")
+	}
+	fields, err := splitFields(repeat, ' ', 0)
+	if err != nil {
+		return nil, err
+	}
+	rule := &Rule{every: 1}
+	if err := rule.parseFlag(fields[0]); err != nil {
+		return nil, err
+	}
+	args, tail := fields[1:], []field(nil)
+	for i, f := range args {
+		if f.text == until || f.text == count || f.text == shift {
+			args, tail = args[:i], args[i:]
+			break
+		}
+	}
+	end := len(repeat)
+	if len(tail) > 0 {
+		end = tail[0].pos
+	}
+	if err := rule.parseArgs(args, end); err != nil {
+		return nil, err
+	}
+	if err := rule.parseClauses(tail, len(repeat)); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// splitFields - split line by 'sep', empty field is error
+// 'offset' - position of line in !_repeat_! string
+func splitFields(line string, sep byte, offset int) ([]field, error) {
+	fields := make([]field, 0, 4)
+	start := 0
+	for i := 0; i <= len(line); i++ {
+		if i < len(line) && line[i] != sep {
+			continue
+		}
+		if i == start {
+			pos := offset + i
+			if i == len(line) && i > 0 {
+				pos--
+			}
+			if sep == ' ' {
+				return nil, parseError(pos, "extra space")
+			}
+			return nil, parseError(pos, "empty item of list")
+		}
+		fields = append(fields, field{text: line[start:i], pos: offset + start})
+		start = i + 1
+	}
+	return fields, nil
+}
+
+// parseFlag - flag with optional interval: "w", "w/2"
+func (r *Rule) parseFlag(f field) error {
+	r.flag = f.text[0]
+	switch r.flag {
+	case day, weak, month, year, nthWeekday, businessDay, hour:
+	default:
+		return parseError(f.pos, fmt.Sprintf("unknown flag '%s'", f.text[:1]))
+	}
+	if len(f.text) == 1 {
+		return nil
+	}
+	if f.text[1] != interval {
+		return parseError(f.pos+1, "expected space after flag")
+	}
+	if r.flag == day || r.flag == businessDay || r.flag == hour {
+		return parseError(f.pos+1, fmt.Sprintf("interval can't be used with flag '%s'", f.text[:1]))
+	}
+	every, ok := strictNumber(f.text[2:])
+	if !ok || every < 1 || every > maxInterval {
+		return parseError(f.pos+2, fmt.Sprintf("interval must be number 1..%d", maxInterval))
+	}
+	r.every = every
+	return nil
+}
+
+// parseArgs - argument(s) of flag, 'end' - position after last argument
+func (r *Rule) parseArgs(args []field, end int) error {
+	need, limit := 1, 1
+	switch r.flag {
+	case year:
+		need, limit = 0, 0
+	case month, nthWeekday, hour:
+		limit = 2
+	}
+	if len(args) < need {
+		return parseError(end, fmt.Sprintf("argument of flag '%c' is missing", r.flag))
+	}
+	if len(args) > limit {
+		return parseError(args[limit].pos, "unexpected argument")
+	}
+	if len(args) == 2 && r.flag != hour {
+		if r.every != 1 {
+			return parseError(args[1].pos, "interval can't be used with months")
+		}
+		if err := r.parseMonths(args[1]); err != nil {
+			return err
+		}
+	}
+	switch r.flag {
+	case day, businessDay:
+		days, ok := number(args[0].text)
+		if !ok || days < minDay || days > maxDay {
+			return parseError(args[0].pos, fmt.Sprintf("number of days must be %d..%d", minDay, maxDay))
+		}
+		r.days = days
+	case weak:
+		return r.parseWeekdays(args[0])
+	case month:
+		if err := r.parseMonthDays(args[0]); err != nil {
+			return err
+		}
+		return r.possibleMonthDays(args[0])
+	case nthWeekday:
+		return r.parseNthWeekdays(args[0])
+	case hour:
+		hours, ok := strictNumber(args[0].text)
+		if !ok || hours < minHour || hours > maxHour {
+			return parseError(args[0].pos, fmt.Sprintf("number of hours must be %d..%d", minHour, maxHour))
+		}
+		r.hours = hours
+		if len(args) == 2 {
+			return r.parseWindow(args[1])
+		}
+	}
+	return nil
+}
+
+func (r *Rule) parseWeekdays(f field) error {
+	items, err := splitFields(f.text, ',', f.pos)
+	if err != nil {
+		return err
+	}
+	r.weekdays = make([]bool, sunday)
+	for _, item := range items {
+		day, ok := number(item.text)
+		if !ok || day < monday || day > sunday {
+			return parseError(item.pos, fmt.Sprintf("day of week must be %d..%d", monday, sunday))
+		}
+		weekday := time.Weekday(day % 7) // time.Sunday = 0
+		if r.weekdays[weekday] {
+			return parseError(item.pos, "day of week is repeated")
+		}
+		r.weekdays[weekday] = true
+	}
+	return nil
+}
+
+func (r *Rule) parseMonthDays(f field) error {
+	items, err := splitFields(f.text, ',', f.pos)
+	if err != nil {
+		return err
+	}
+	r.monthDays = make(map[int]bool, len(items))
+	for _, item := range items {
+		text, sign := item.text, 1
+		if strings.HasPrefix(text, "-") {
+			text, sign = text[1:], -1
+		}
+		day, ok := number(text)
+		day *= sign
+		if !ok || day == 0 || day > maxDaysPerMonth || day < penultimateDayMonth {
+			return parseError(item.pos, fmt.Sprintf("day of month must be %d..%d, %d or %d",
+				minDay, maxDaysPerMonth, lastDayMonth, penultimateDayMonth))
+		}
+		if r.monthDays[day] {
+			return parseError(item.pos, "day of month is repeated")
+		}
+		r.monthDays[day] = true
+	}
+	return nil
+}
+
+// possibleMonthDays - rule with day(s) which never exist in month(s) is wrong: m 31 2, m 30,31 2,4
+func (r *Rule) possibleMonthDays(f field) error {
+	if r.months == nil {
+		return nil
+	}
+	days := make([]int, 0, len(r.monthDays))
+	for day := range r.monthDays {
+		days = append(days, day)
+	}
+	if !existInMonths(days, r.months) {
+		return parseError(f.pos, "days of month never exist in selected months")
+	}
+	return nil
+}
+
+func (r *Rule) parseNthWeekdays(f field) error {
+	items, err := splitFields(f.text, ',', f.pos)
+	if err != nil {
+		return err
+	}
+	r.nthWeekdays = make(map[ordinalWeekday]bool, len(items))
+	for _, item := range items {
+		weekday, ordinal, ok := strings.Cut(item.text, string(separatorOrdinal))
+		if !ok {
+			return parseError(item.pos, "expected weekday#ordinal")
+		}
+		day, ok := strictNumber(weekday)
+		if !ok || day < monday || day > sunday {
+			return parseError(item.pos, fmt.Sprintf("day of week must be %d..%d", monday, sunday))
+		}
+		text, sign := ordinal, 1
+		if strings.HasPrefix(text, "-") {
+			text, sign = text[1:], -1
+		}
+		number, ok := strictNumber(text)
+		if !ok || number == 0 || number > maxOrdinal {
+			return parseError(item.pos+len(weekday)+1,
+				fmt.Sprintf("ordinal must be %d..%d or -%d..-%d", 1, maxOrdinal, 1, maxOrdinal))
+		}
+		key := ordinalWeekday{weekday: time.Weekday(day % 7), ordinal: number * sign} // time.Sunday = 0
+		if r.nthWeekdays[key] {
+			return parseError(item.pos, "weekday#ordinal is repeated")
+		}
+		r.nthWeekdays[key] = true
+	}
+	return nil
+}
+
+// parseWindow - hours of day for flag 'h': "09:00-18:00"
+func (r *Rule) parseWindow(f field) error {
+	from, to, ok := strings.Cut(f.text, "-")
+	if !ok {
+		return parseError(f.pos, "expected time-time")
+	}
+	start, ok := minutesOfDay(from)
+	if !ok {
+		return parseError(f.pos, "time must be in format "+model.TimeFormat)
+	}
+	end, ok := minutesOfDay(to)
+	if !ok {
+		return parseError(f.pos+len(from)+1, "time must be in format "+model.TimeFormat)
+	}
+	if end <= start {
+		return parseError(f.pos+len(from)+1, "end of window must be after start")
+	}
+	r.window = timeWindow{from: start, to: end}
+	return nil
+}
+
+func (r *Rule) parseMonths(f field) error {
+	items, err := splitFields(f.text, ',', f.pos)
+	if err != nil {
+		return err
+	}
+	r.months = make([]bool, time.December+1)
+	for _, item := range items {
+		number, ok := number(item.text)
+		month := time.Month(number)
+		if !ok || month < time.January || month > time.December {
+			return parseError(item.pos, "month must be 1..12")
+		}
+		if r.months[month] {
+			return parseError(item.pos, "month is repeated")
+		}
+		r.months[month] = true
+	}
+	return nil
+}
+
+// parseClauses - pairs "key value" in any order, every key only once
+func (r *Rule) parseClauses(tail []field, end int) error {
+	keys := make(map[string]bool)
+	for i := 0; i < len(tail); i += 2 {
+		key := tail[i]
+		if key.text != until && key.text != count && key.text != shift {
+			return parseError(key.pos, fmt.Sprintf("unexpected '%s', expected clause", key.text))
+		}
+		if keys[key.text] {
+			return parseError(key.pos, fmt.Sprintf("clause '%s' is repeated", key.text))
+		}
+		keys[key.text] = true
+		if i+1 == len(tail) {
+			return parseError(end, fmt.Sprintf("value of '%s' is missing", key.text))
+		}
+		value := tail[i+1]
+		switch key.text {
+		case until:
+			if r.end.count != 0 {
+				return parseError(key.pos, "until and count can't be used together")
+			}
+			date, err := time.Parse(model.DateFormat, value.text)
+			if err != nil {
+				return parseError(value.pos, "date of until must be in format "+model.DateFormat)
+			}
+			r.end.until = date
+		case count:
+			if !r.end.until.IsZero() {
+				return parseError(key.pos, "until and count can't be used together")
+			}
+			number, ok := strictNumber(value.text)
+			if !ok || number < 1 {
+				return parseError(value.pos, "count must be positive number")
+			}
+			r.end.count = number
+		case shift:
+			if r.flag == hour {
+				return parseError(key.pos, fmt.Sprintf("shift can't be used with flag '%c'", hour))
+			}
+			direction, ok := shiftDirections[value.text]
+			if !ok {
+				return parseError(value.pos, "shift must be next or prev")
+			}
+			r.shift = direction
+		}
+	}
+	return nil
+}
+
+// number - only digits, leading zeros are allowed (m 07 05)
+func number(text string) (int, bool) {
+	if text == "" || len(text) > 4 {
+		return 0, false
+	}
+	for i := 0; i < len(text); i++ {
+		if text[i] < '0' || text[i] > '9' {
+			return 0, false
+		}
+	}
+	n, err := strconv.Atoi(text)
+	return n, err == nil
+}
+
+// strictNumber - only digits without leading zeros
+func strictNumber(text string) (int, bool) {
+	n, ok := number(text)
+	return n, ok && strconv.Itoa(n) == text
+}
+
+// Next - find next date of rule after 'now' for series started from 'taskDateStart'
+// end condition is not checked
+func (r *Rule) Next(now, taskDateStart time.Time) (time.Time, error) {
+	newDate, err := nextDateByRule(now, taskDateStart, r)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if r.shift != 0 {
+		return shiftNextDate(now, taskDateStart, r, newDate)
+	}
+	return newDate, nil
+}
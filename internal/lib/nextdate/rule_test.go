@@ -0,0 +1,128 @@
+package nextdate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseRule(t *testing.T) {
+	asserts := assert.New(t)
+	requires := require.New(t)
+
+	rule, err := ParseRule("m 07,-1 05,6 until 20250101 shift prev")
+	requires.NoError(err)
+	asserts.Equal(byte(month), rule.flag)
+	asserts.Equal(map[int]bool{7: true, -1: true}, rule.monthDays)
+	asserts.True(rule.months[5] && rule.months[6])
+	asserts.Equal(-1, rule.shift)
+	asserts.False(rule.end.until.IsZero())
+
+	rule, err = ParseRule("h 4 09:00-18:30")
+	requires.NoError(err)
+	asserts.Equal(4, rule.hours)
+	asserts.Equal(timeWindow{from: 9 * 60, to: 18*60 + 30}, rule.window)
+
+	rule, err = ParseRule("w/2 7,1 count 3")
+	requires.NoError(err)
+	asserts.Equal(2, rule.every)
+	asserts.Equal([]bool{true, true, false, false, false, false, false}, rule.weekdays)
+	asserts.Equal(3, rule.end.count)
+
+	invalidData := []struct {
+		repeat string
+		pos    int
+		reason string
+	}{
+		{"", 0, "empty rule"},
+		{" y", 0, "extra space"},
+		{"y ", 1, "extra space"},
+		{"d 7  count 3", 4, "extra space"},
+		{"k 34", 0, "unknown flag 'k'"},
+		{"m2", 1, "expected space after flag"},
+		{"d/2 7", 1, "interval can't be used with flag 'd'"},
+		{"w/02 1", 2, "interval must be number 1..52"},
+		{"d", 1, "argument of flag 'd' is missing"},
+		{"d until 20250101", 2, "argument of flag 'd' is missing"},
+		{"d 45 17", 5, "unexpected argument"},
+		{"y 1", 2, "unexpected argument"},
+		{"d 401", 2, "number of days must be 1..400"},
+		{"w 1,2,8", 6, "day of week must be 1..7"},
+		{"w 1,1", 4, "day of week is repeated"},
+		{"w ,2", 2, "empty item of list"},
+		{"w 2,3,", 5, "empty item of list"},
+		{"m -3", 2, "day of month must be 1..31, -1 or -2"},
+		{"m 15 1,13", 7, "month must be 1..12"},
+		{"m/3 15 1,4", 7, "interval can't be used with months"},
+		{"m 31 2", 2, "days of month never exist in selected months"},
+		{"m 30,31 02", 2, "days of month never exist in selected months"},
+		{"n 2", 2, "expected weekday#ordinal"},
+		{"n 1#1,2#6", 8, "ordinal must be 1..5 or -1..-5"},
+		{"n 8#1", 2, "day of week must be 1..7"},
+		{"d 7 count 03", 10, "count must be positive number"},
+		{"d 7 until 2024013", 10, "date of until must be in format 20060102"},
+		{"h/2 4", 1, "interval can't be used with flag 'h'"},
+		{"h 24", 2, "number of hours must be 1..23"},
+		{"h 4 09:00", 4, "expected time-time"},
+		{"h 4 9:00-18:00", 4, "time must be in format 15:04"},
+		{"h 4 09:00-24:00", 10, "time must be in format 15:04"},
+		{"h 4 18:00-09:00", 10, "end of window must be after start"},
+		{"h 4 shift next", 4, "shift can't be used with flag 'h'"},
+		{"d 7 count 3 until 20250101", 12, "until and count can't be used together"},
+		{"d 7 shift next shift prev", 15, "clause 'shift' is repeated"},
+		{"d 7 shift up", 10, "shift must be next or prev"},
+		{"d 7 count", 9, "value of 'count' is missing"},
+		{"d 7 count 3 7", 12, "unexpected '7', expected clause"},
+	}
+
+	for _, test := range invalidData {
+		_, err := ParseRule(test.repeat)
+		requires.ErrorIs(err, ErrNextDateWrongRepeat, "should be wrong repeat "+test.repeat)
+
+		var parseErr *ParseError
+		requires.True(errors.As(err, &parseErr), "should be *ParseError "+test.repeat)
+		asserts.Equal(test.pos, parseErr.Pos, "position "+test.repeat)
+		asserts.Equal(test.reason, parseErr.Reason, "reason "+test.repeat)
+	}
+
+	invalidRRule := []struct {
+		repeat string
+		pos    int
+	}{
+		{"RRULE:", 6},
+		{"FREQ=DAILY;;COUNT=2", 11},
+		{"FREQ=DAILY;COUNT", 11},
+		{"FREQ=DAILY;BYDAY=MO,XX", 20},
+		{"RRULE:FREQ=MONTHLY;BYMONTH=1,13", 29},
+		{"FREQ=DAILY;BYSETPOS=1", 11},
+		{"FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30", 22},
+	}
+
+	for _, test := range invalidRRule {
+		_, err := NormalizeRRule(test.repeat)
+
+		var parseErr *ParseError
+		requires.True(errors.As(err, &parseErr), "should be *ParseError "+test.repeat)
+		asserts.Equal(test.pos, parseErr.Pos, "position "+test.repeat)
+	}
+}
+
+func Test_Validate(t *testing.T) {
+	requires := require.New(t)
+
+	repeat, err := Validate(AlgorithmRRule, "rrule:freq=daily")
+	requires.NoError(err)
+	requires.Equal("FREQ=DAILY", repeat)
+
+	repeat, err = Validate(AlgorithmNextDate, "m 07 05")
+	requires.NoError(err)
+	requires.Equal("m 07 05", repeat)
+
+	_, err = Validate(AlgorithmNextDate, "m 32")
+	requires.ErrorIs(err, ErrNextDateWrongRepeat)
+
+	_, err = Validate("alien", "d 1")
+	requires.ErrorIs(err, ErrNextDateUnknownAlgorithm)
+}
@@ -0,0 +1,216 @@
+// workday - rules of working days for algorithm 'NextDate'
+//
+// working day - not saturday, not sunday and not holiday
+// holidays set from calendar file during application startup, see 'NewHolidayCalendar(cfg *config.Config) error'
+package nextdate
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/config"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+	"github.com/Ekvo/yandex-practicum-go-final-project/pkg/common"
+)
+
+// ErrNextDateInvalidCalendar - file of holidays contain wrong line
+var ErrNextDateInvalidCalendar = errors.New("invalid holiday calendar")
+
+// holidays - number of day (see 'dayNumber') -> true
+// read only after application startup
+var holidays = map[int]bool{}
+
+// shiftDirections - values of clause "shift"
+var shiftDirections = map[string]int{
+	"next": 1,
+	"prev": -1,
+}
+
+// NewHolidayCalendar - call in Run -> during application startup
+// path of calendar is empty -> only saturday and sunday are days off
+//
+// file formats:
+// .ics - take 'DTSTART' of every event
+// other - one date in line (20060102 or 2006-01-02), empty line and line start with '#' are skipped
+func NewHolidayCalendar(cfg *config.Config) error {
+	if cfg.HolidaysFile == "" {
+		return nil
+	}
+	file, err := os.Open(cfg.HolidaysFile)
+	if err != nil {
+		return fmt.Errorf("nextdate: calendar open error - %w", err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Printf("nextdate: calendar close error - %v", err)
+		}
+	}()
+	isICS := strings.EqualFold(filepath.Ext(cfg.HolidaysFile), ".ics")
+	days := make(map[int]bool)
+	scanner := bufio.NewScanner(file)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if isICS {
+			if !strings.HasPrefix(line, "DTSTART") {
+				continue
+			}
+			// DTSTART;VALUE=DATE:20250101 or DTSTART:20250101T000000Z
+			_, line, _ = strings.Cut(line, ":")
+			if len(line) > len(model.DateFormat) {
+				line = line[:len(model.DateFormat)]
+			}
+		} else if line == "" || line[0] == '#' {
+			continue
+		}
+		date, err := parseHoliday(line)
+		if err != nil {
+			return fmt.Errorf("nextdate: line %d - %w", number, err)
+		}
+		days[dayNumber(date)] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("nextdate: calendar read error - %w", err)
+	}
+	holidays = days
+	return nil
+}
+
+func parseHoliday(line string) (time.Time, error) {
+	for _, layout := range []string{model.DateFormat, time.DateOnly} {
+		if date, err := time.Parse(layout, line); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, ErrNextDateInvalidCalendar
+}
+
+// IsWorkday - 'date' is not weekend and not holiday
+func IsWorkday(date time.Time) bool {
+	if weekday := date.Weekday(); weekday == time.Saturday || weekday == time.Sunday {
+		return false
+	}
+	return !holidays[dayNumber(date)]
+}
+
+// moveToWorkday - find nearest workday by 'direction', 'date' is workday -> return 'date'
+func moveToWorkday(date time.Time, direction int) (time.Time, error) {
+	for i := 0; i < maxDay; i++ {
+		if IsWorkday(date) {
+			return date, nil
+		}
+		date = date.AddDate(0, 0, direction)
+	}
+	return time.Time{}, ErrNextDateUnexpectedBehavior
+}
+
+// shiftNextDate - move 'newDate' of rule to workday by clause "shift"
+// moved date should be after 'now', othercase - take next date of rule
+func shiftNextDate(now, taskDateStart time.Time, rule *Rule, newDate time.Time) (time.Time, error) {
+	for i := 0; i < maxDay; i++ {
+		shifted, err := moveToWorkday(newDate, rule.shift)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if shifted.After(now) {
+			return shifted, nil
+		}
+		newDate, err = nextDateByRule(newDate, taskDateStart, rule)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+	return time.Time{}, ErrNextDateUnexpectedBehavior
+}
+
+// nextDateByBusinessDay - add number of working days to taskDateStart while date not after 'now'
+//
+// working days from taskDateStart up to 'now' -> number of full steps of 'days', next step is after 'now'
+func nextDateByBusinessDay(now, taskDateStart time.Time, days int) (time.Time, error) {
+	steps := 1
+	if now.UTC().After(taskDateStart.UTC()) {
+		steps += workdaysBetween(taskDateStart, now) / days
+	}
+	return addWorkdays(taskDateStart, steps*days), nil
+}
+
+// addWorkdays - working day number 'n' after 'date' (n > 0)
+// weekdays are added as full weeks and remainder, then every holiday of added days adds one more weekday
+func addWorkdays(date time.Time, n int) time.Time {
+	newDate := addWeekdays(date, n)
+	for missing := n - workdaysBetween(date, newDate); missing > 0; missing = n - workdaysBetween(date, newDate) {
+		newDate = addWeekdays(newDate, missing)
+	}
+	return newDate
+}
+
+// addWeekdays - day from monday to friday number 'n' after 'date' (n > 0), holidays are not checked
+func addWeekdays(date time.Time, n int) time.Time {
+	// monday = 0, sunday = 6, saturday and sunday are counted from friday
+	weekday := (int(date.Weekday()) + 6) % 7
+	if weekday > 4 {
+		date = date.AddDate(0, 0, 4-weekday)
+		weekday = 4
+	}
+	weeks, rest := n/5, n%5
+	if weekday+rest > 4 {
+		rest += 2
+	}
+	return date.AddDate(0, 0, 7*weeks+rest)
+}
+
+// workdaysBetween - number of working days after 'from' up to 'to' (include)
+func workdaysBetween(from, to time.Time) int {
+	first, last := dayNumber(from), dayNumber(to)
+	if last <= first {
+		return 0
+	}
+	n := weekdaysUpTo(last) - weekdaysUpTo(first)
+	for day := range holidays {
+		if day > first && day <= last && floorMod(day+3, 7) < 5 {
+			n--
+		}
+	}
+	return n
+}
+
+// weekdaysUpTo - number of days from monday to friday from 29.12.1969 (monday) up to day 'day' (see 'dayNumber')
+func weekdaysUpTo(day int) int {
+	days := int64(day + 3 + 1)
+	return int(floorDiv(days, 7))*5 + min(floorMod(int(days), 7), 5)
+}
+
+// WorkdayDate - move 'date' to workday if rule work only with working days
+// (flag 'b' or clause "shift"), othercase return 'date' without change
+//
+// use when 'date' of task is taken as is (not from 'NextDate')
+func WorkdayDate(date string, repeat string) (string, error) {
+	if IsRRule(repeat) { // RRULE has no working days
+		return date, nil
+	}
+	rule, err := ParseRule(repeat)
+	if err != nil {
+		return "", err
+	}
+	direction := rule.shift
+	if rule.flag == businessDay {
+		direction = 1
+	}
+	if direction == 0 {
+		return date, nil
+	}
+	dateToTime, err := time.Parse(model.DateFormat, date)
+	if err != nil {
+		return "", ErrNextDateInvalidDate
+	}
+	dateToTime, err = moveToWorkday(common.ReduceTimeToDay(dateToTime), direction)
+	if err != nil {
+		return "", err
+	}
+	return dateToTime.Format(model.DateFormat), nil
+}
@@ -0,0 +1,124 @@
+package nextdate
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/config"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+)
+
+func Test_NewHolidayCalendar(t *testing.T) {
+	requires := require.New(t)
+	defer func() { holidays = map[int]bool{} }()
+
+	dir := t.TempDir()
+	files := []struct {
+		name    string
+		content string
+		err     bool
+	}{
+		{"holidays.txt", "# new year\n20240101\n\n2024-01-02\n", false},
+		{"holidays.ics", "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART;VALUE=DATE:20240101\nEND:VEVENT\n" +
+			"BEGIN:VEVENT\nDTSTART:20240102T000000Z\nEND:VEVENT\nEND:VCALENDAR\n", false},
+		{"wrong.txt", "20240101\n01.02.2024\n", true},
+	}
+
+	for _, test := range files {
+		path := filepath.Join(dir, test.name)
+		requires.NoError(os.WriteFile(path, []byte(test.content), 0o644), "write file error")
+
+		holidays = map[int]bool{}
+		err := NewHolidayCalendar(&config.Config{HolidaysFile: path})
+		if test.err {
+			requires.ErrorIs(err, ErrNextDateInvalidCalendar, "should be error "+test.name)
+			continue
+		}
+		requires.NoError(err, fmt.Sprintf("calendar error - %v", err))
+		requires.Len(holidays, 2, "should be 2 holidays "+test.name)
+		requires.False(IsWorkday(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)), "should be holiday "+test.name)
+		requires.True(IsWorkday(time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)), "should be workday "+test.name)
+	}
+	requires.Error(NewHolidayCalendar(&config.Config{HolidaysFile: filepath.Join(dir, "alien.txt")}))
+}
+
+func Test_NextDate_Workday(t *testing.T) {
+	asserts := assert.New(t)
+	requires := require.New(t)
+	defer func() { holidays = map[int]bool{} }()
+
+	path := filepath.Join(t.TempDir(), "holidays.txt")
+	requires.NoError(os.WriteFile(path, []byte("20240129\n20240215\n"), 0o644), "write file error")
+	requires.NoError(NewHolidayCalendar(&config.Config{HolidaysFile: path}), "calendar error")
+
+	now, _ := time.Parse(model.DateFormat, "20240126")
+
+	validData := []struct {
+		date   string
+		repeat string
+		want   string
+	}{
+		{"20240126", "b 1", "20240130"},
+		{"20240122", "b 3", "20240131"},
+		{"20240101", "m 15 shift next", "20240216"},
+		{"20240101", "m 15 shift prev", "20240214"},
+		{"20240101", "m 17 2 shift next", "20240219"},
+		{"20240101", "m 27 shift prev", "20240227"},
+		{"20240125", "d 3 shift next count 2", "20240130"},
+		{"20240125", "w 6 until 20240301 shift next", "20240130"},
+	}
+
+	for i, test := range validData {
+		log.Printf("\ttest: %d", i+1)
+
+		getDate, err := NextDate(now, test.date, test.repeat)
+
+		requires.NoError(err, fmt.Sprintf("valid Data but error - %v", err))
+		asserts.Equal(test.want, getDate, "should be equal "+test.repeat)
+	}
+
+	invalidData := []string{
+		"b",
+		"b 0",
+		"b 401",
+		"b/2 3",
+		"m 15 shift",
+		"m 15 shift up",
+		"m 15 shift next shift prev",
+		"m 15 until 20250101 count 2",
+		"shift next",
+	}
+
+	for i, repeat := range invalidData {
+		log.Printf("\ttest: %d", i+1)
+
+		getDate, err := NextDate(now, "20240126", repeat)
+		requires.Error(err, "invalid Data but not error "+repeat)
+		asserts.Empty(getDate, "should be empty")
+	}
+
+	dataForWorkday := []struct {
+		date   string
+		repeat string
+		want   string
+	}{
+		{"20240127", "b 2", "20240130"},
+		{"20240127", "d 2 shift next", "20240130"},
+		{"20240127", "m 15 shift prev", "20240126"},
+		{"20240127", "d 2", "20240127"},
+	}
+
+	for _, test := range dataForWorkday {
+		getDate, err := WorkdayDate(test.date, test.repeat)
+
+		requires.NoError(err, fmt.Sprintf("valid Data but error - %v", err))
+		asserts.Equal(test.want, getDate, "should be equal "+test.repeat)
+	}
+}
@@ -0,0 +1,87 @@
+// timezone - location where "today" and "now" of tasks are evaluated
+//
+// default location - TODO_TIMEZONE from config, empty -> local zone of server
+// location of request (header "X-Timezone" or param "tz") is stored in context.Context
+package timezone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	// database of time zones inside binary file, image 'scratch' has no zoneinfo
+	_ "time/tzdata"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/config"
+)
+
+// ErrTimezoneUnknown - name of time zone not found and not offset
+var ErrTimezoneUnknown = errors.New("unknown time zone")
+
+// names of request properties with time zone
+const (
+	Header = "X-Timezone"
+	Param  = "tz"
+)
+
+// location - non-exported global variable, set only in start application
+var location = time.Local
+
+// NewLocation - set default location from config, call in Run -> during application startup
+func NewLocation(cfg *config.Config) error {
+	if cfg.Timezone == "" {
+		return nil
+	}
+	loc, err := Load(cfg.Timezone)
+	if err != nil {
+		return fmt.Errorf("timezone: config error - %w", err)
+	}
+	location = loc
+	return nil
+}
+
+// Load - location by IANA name ("Europe/Moscow", "UTC") or offset from UTC ("+03:00", "-0530")
+func Load(name string) (*time.Location, error) {
+	if strings.HasPrefix(name, "+") || strings.HasPrefix(name, "-") {
+		return loadOffset(name)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, ErrTimezoneUnknown
+	}
+	return loc, nil
+}
+
+func loadOffset(name string) (*time.Location, error) {
+	for _, layout := range []string{"-07:00", "-0700", "-07"} {
+		t, err := time.Parse(layout, name)
+		if err != nil {
+			continue
+		}
+		_, offset := t.Zone()
+		return time.FixedZone(name, offset), nil
+	}
+	return nil, ErrTimezoneUnknown
+}
+
+type ctxKey struct{}
+
+// WithLocation - context of request with location
+func WithLocation(ctx context.Context, loc *time.Location) context.Context {
+	return context.WithValue(ctx, ctxKey{}, loc)
+}
+
+// Location - location of request, othercase default location
+func Location(ctx context.Context) *time.Location {
+	if loc, ok := ctx.Value(ctxKey{}).(*time.Location); ok && loc != nil {
+		return loc
+	}
+	return location
+}
+
+// In - time 't' ("now" of clock see lib/clock/clock.go) in location of request
+func In(ctx context.Context, t time.Time) time.Time {
+	return t.In(Location(ctx))
+}
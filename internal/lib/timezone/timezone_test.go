@@ -0,0 +1,55 @@
+package timezone
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	asserts := assert.New(t)
+	requires := require.New(t)
+
+	dataForLoad := []struct {
+		name   string
+		offset int
+		err    error
+	}{
+		{name: "Europe/Moscow", offset: 3 * 60 * 60},
+		{name: "UTC", offset: 0},
+		{name: "+03:00", offset: 3 * 60 * 60},
+		{name: "-0530", offset: -(5*60 + 30) * 60},
+		{name: "+05", offset: 5 * 60 * 60},
+		{name: "Mars/Olympus", err: ErrTimezoneUnknown},
+		{name: "+3", err: ErrTimezoneUnknown},
+		{name: "+25:00", err: ErrTimezoneUnknown},
+	}
+
+	// Moscow has no daylight saving time since 2014
+	moment := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+	for _, test := range dataForLoad {
+		loc, err := Load(test.name)
+		if test.err != nil {
+			asserts.ErrorIs(err, test.err, test.name)
+			continue
+		}
+		requires.NoError(err, test.name)
+		_, offset := moment.In(loc).Zone()
+		asserts.Equal(test.offset, offset, test.name)
+	}
+}
+
+func TestLocation(t *testing.T) {
+	asserts := assert.New(t)
+
+	ctx := context.Background()
+	asserts.Equal(location, Location(ctx), "default location")
+
+	loc := time.FixedZone("+03:00", 3*60*60)
+	ctx = WithLocation(ctx, loc)
+	asserts.Equal(loc, Location(ctx), "location of request")
+	asserts.Equal(loc, In(ctx, time.Now()).Location(), "now in location of request")
+}
@@ -0,0 +1,38 @@
+// completion - describes the record of done task and its implementing interfaces
+package model
+
+import "context"
+
+// max lenght of 'CompletionModel.Note'
+const CompletionNoteLen = 2048
+
+// CompletionModel - record of done task in history (table 'task_completions')
+// record is kept after task is deleted
+type CompletionModel struct {
+	ID uint
+
+	// ID of done task
+	TaskID uint
+
+	// scheduled date of done task in format '20060102'
+	Date string
+
+	// scheduled time of done task in format '15:04', empty - task for whole day
+	Time string
+
+	// time of completion by clock of application in UTC, format time.RFC3339
+	DoneAt string
+
+	// optional, max 2048 characters
+	Note string
+}
+
+// TaskComplete - done task in one transaction: save completion, then new data of task or delete it
+type TaskComplete interface {
+	CompleteTask(ctx context.Context, data any) error
+}
+
+// TaskHistory - read completions of task in order of completion
+type TaskHistory interface {
+	FindCompletionList(ctx context.Context, data any) ([]CompletionModel, error)
+}
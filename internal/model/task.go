@@ -16,11 +16,17 @@ const (
 	TaskTitleLen   = 255
 	TaskCommentLen = 2048
 	TaskRepeatLen  = 128
+
+	// 32 exception dates '20060102' separated by comma
+	TaskExdatesLen = 287
 )
 
 // in this format database store 'date' in type VARCHAR(8)
 const DateFormat = "20060102"
 
+// in this format database store 'time' in type VARCHAR(5)
+const TimeFormat = "15:04"
+
 type TaskModel struct {
 	ID uint
 
@@ -28,6 +34,10 @@ type TaskModel struct {
 	// max 8 characters
 	Date string
 
+	// optional time of day in format '15:04'
+	// empty - task for whole day
+	Time string
+
 	// not empty
 	// max 255 characters
 	Title string
@@ -38,6 +48,18 @@ type TaskModel struct {
 	// containing the repetition rules for the task.
 	// max 128 characters,
 	Repeat string
+
+	// name of algorithm for 'Repeat' (lib/nextdate/registry.go)
+	// empty - selected by syntax of 'Repeat' or default from config
+	Algorithm string
+
+	// from which date series is continued after task is done (lib/nextdate/mode.go)
+	// empty - "schedule", task without 'Repeat' - empty
+	Mode string
+
+	// exception dates of series in format '20241231,20250107' (lib/nextdate/exdate.go)
+	// max 287 characters, task without 'Repeat' - empty
+	Exdates string
 }
 
 // TaskCreate - save a task to storage, and return a unique ID for the new task
@@ -49,6 +71,8 @@ type TaskCreate interface {
 type TaskRead interface {
 	FindOneTask(ctx context.Context, data any) (TaskModel, error)
 	FindTaskList(ctx context.Context, data any) ([]TaskModel, error)
+	FindRepeatTaskList(ctx context.Context, data any) ([]TaskModel, error)
+	FindAgendaTaskList(ctx context.Context, data any) ([]TaskModel, error)
 }
 
 // TaskUpdate - write new data for a specific task
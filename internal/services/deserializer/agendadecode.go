@@ -0,0 +1,83 @@
+// agendadecode - rules for decode params of agenda from http.Request (/api/agenda)
+package deserializer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/services/entity"
+	"github.com/Ekvo/yandex-practicum-go-final-project/pkg/common"
+)
+
+// ErrServicesWindowBeforeStart - end of window is before first day
+var ErrServicesWindowBeforeStart = errors.New("before from")
+
+const (
+	// DefaultAgendaDays - number of days without param 'to' (week)
+	DefaultAgendaDays = 7
+
+	// MaxAgendaDays - limit of days in one agenda (quarter)
+	MaxAgendaDays = 92
+)
+
+// AgendaDecode - params of URL query
+//
+// from - first day of agenda (empty - today of clock)
+// to   - last day of agenda (include), empty - week from first day
+// window is not more than MaxAgendaDays
+type AgendaDecode struct {
+	From string
+	To   string
+
+	// today - "now" of clock in time zone of request, used without param 'from'
+	today time.Time
+
+	agenda *entity.TaskAgenda
+}
+
+func NewAgendaDecode(today time.Time) *AgendaDecode {
+	return &AgendaDecode{today: today}
+}
+
+func (ad *AgendaDecode) Entity() *entity.TaskAgenda {
+	return ad.agenda
+}
+
+// Decode - check all params and create full error list use map - common.Message
+func (ad *AgendaDecode) Decode(r *http.Request) error {
+	query := r.URL.Query()
+	ad.From = query.Get("from")
+	ad.To = query.Get("to")
+
+	msgErr := make(common.Message)
+	from := common.ReduceTimeToDayUTC(ad.today)
+	if ad.From != "" {
+		date, err := time.Parse(model.DateFormat, ad.From)
+		if err != nil {
+			msgErr["from"] = ErrServicesInvalidDate.Error()
+		}
+		from = date
+	}
+	to := from.AddDate(0, 0, DefaultAgendaDays-1)
+	if ad.To != "" {
+		date, err := time.Parse(model.DateFormat, ad.To)
+		if err != nil {
+			msgErr["to"] = ErrServicesInvalidDate.Error()
+		} else if _, wrongFrom := msgErr["from"]; !wrongFrom {
+			if date.Before(from) {
+				msgErr["to"] = ErrServicesWindowBeforeStart.Error()
+			} else if date.After(from.AddDate(0, 0, MaxAgendaDays-1)) {
+				msgErr["to"] = ErrServicesOutOfRange.Error()
+			}
+		}
+		to = date
+	}
+	if len(msgErr) != 0 {
+		return fmt.Errorf("agendadecode: error - %s", msgErr.String())
+	}
+	ad.agenda = entity.NewTaskAgenda(from, to)
+	return nil
+}
@@ -0,0 +1,79 @@
+// clockdecode - rules for decode change of debug clock from http.Request (/api/debug/clock)
+package deserializer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/lib/timezone"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/services/entity"
+	"github.com/Ekvo/yandex-practicum-go-final-project/pkg/common"
+)
+
+var (
+	// ErrServicesInvalidOffset - offset is not duration: "72h", "-90m"
+	ErrServicesInvalidOffset = errors.New("invalid offset")
+
+	// ErrServicesNowAndOffset - frozen time and offset in one request
+	ErrServicesNowAndOffset = errors.New("now and offset can't be used together")
+)
+
+// ClockDecode - body of request
+//
+// now    - freeze clock: "20060102" or "20060102 15:04" in time zone of request
+// offset - move clock from real time: "72h", "-30m" (time.ParseDuration)
+// without 'now' and 'offset' - clock is reset
+type ClockDecode struct {
+	Now    string `json:"now,omitempty"`
+	Offset string `json:"offset,omitempty"`
+
+	setting *entity.ClockSetting `json:"-"`
+}
+
+func NewClockDecode() *ClockDecode {
+	return &ClockDecode{}
+}
+
+func (cd *ClockDecode) Entity() *entity.ClockSetting {
+	return cd.setting
+}
+
+// Decode - check all fields and create full error list use map - common.Message
+func (cd *ClockDecode) Decode(r *http.Request) error {
+	if err := common.DecodeJSON(r, cd); err != nil {
+		return err
+	}
+	msgErr := make(common.Message)
+	freeze := time.Time{}
+	if cd.Now != "" {
+		layout := model.DateFormat
+		if len(cd.Now) > len(model.DateFormat) {
+			layout = model.DateFormat + " " + model.TimeFormat
+		}
+		// 'now' in time zone of request (lib/timezone/timezone.go)
+		date, err := time.ParseInLocation(layout, cd.Now, timezone.Location(r.Context()))
+		if err != nil {
+			msgErr["now"] = ErrServicesInvalidDate.Error()
+		}
+		freeze = date
+	}
+	offset := time.Duration(0)
+	if cd.Offset != "" {
+		duration, err := time.ParseDuration(cd.Offset)
+		if err != nil {
+			msgErr["offset"] = ErrServicesInvalidOffset.Error()
+		}
+		offset = duration
+		if cd.Now != "" {
+			msgErr["offset"] = ErrServicesNowAndOffset.Error()
+		}
+	}
+	if len(msgErr) != 0 {
+		return fmt.Errorf("clockdecode: error - %s", msgErr.String())
+	}
+	cd.setting = entity.NewClockSetting(freeze, offset)
+	return nil
+}
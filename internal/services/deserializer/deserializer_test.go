@@ -5,11 +5,16 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/lib/nextdate"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
 	"github.com/Ekvo/yandex-practicum-go-final-project/pkg/common"
 )
 
@@ -62,7 +67,7 @@ func TestTaskDecode_Decode(t *testing.T) {
 	mux := http.ServeMux{}
 
 	mux.HandleFunc("POST /test", func(w http.ResponseWriter, r *http.Request) {
-		deserialize := NewTaskDecode()
+		deserialize := NewTaskDecode(nextdate.AlgorithmNextDate)
 		if err := deserialize.Decode(r); err != nil {
 			common.EncodeJSON(w, http.StatusUnprocessableEntity, common.Message{"error": err.Error()})
 			return
@@ -88,6 +93,306 @@ func TestTaskDecode_Decode(t *testing.T) {
 			resRegexp: `{"error":"taskdecode: error - {date:invalid date format},{id:not numeric},{repeat:length exceeded},{title:empty}"}`,
 			msg:       `invalid decode`,
 		},
+		{
+			body:      `{"date":"20240201","title":"Summarize","repeat":"rrule:freq=weekly;byday=we,mo"}`,
+			resCode:   http.StatusOK,
+			resRegexp: `{"task":"approve"}`,
+			msg:       `valid decode rrule`,
+		},
+		{
+			body:      `{"date":"20240201","title":"Summarize","repeat":"FREQ=HOURLY"}`,
+			resCode:   http.StatusUnprocessableEntity,
+			resRegexp: `{"error":"taskdecode: error - {repeat:invalid repeat data at position 5: FREQ must be DAILY, WEEKLY, MONTHLY or YEARLY}"}`,
+			msg:       `invalid decode rrule`,
+		},
+		{
+			body:      `{"date":"20240201","title":"Summarize","repeat":"FREQ=MONTHLY;BYMONTHDAY=1;BYDAY=-1MO"}`,
+			resCode:   http.StatusUnprocessableEntity,
+			resRegexp: `{"error":"taskdecode: error - {repeat:invalid repeat data at position 26: BYDAY, BYMONTHDAY and BYMONTH never match together}"}`,
+			msg:       `invalid decode rrule without date`,
+		},
+		{
+			body:      `{"date":"20240201","title":"Summarize","repeat":"d 1","algorithm":"alien"}`,
+			resCode:   http.StatusUnprocessableEntity,
+			resRegexp: `{"error":"taskdecode: error - {algorithm:unknown algorithm}"}`,
+			msg:       `invalid decode algorithm`,
+		},
+		{
+			body:      `{"date":"20240201","title":"Summarize","repeat":"w 1,8"}`,
+			resCode:   http.StatusUnprocessableEntity,
+			resRegexp: `{"error":"taskdecode: error - {repeat:invalid repeat data at position 4: day of week must be 1..7}"}`,
+			msg:       `invalid decode repeat with position`,
+		},
+		{
+			body:      `{"date":"20240201","title":"Water plants","repeat":"d 7","mode":"completion"}`,
+			resCode:   http.StatusOK,
+			resRegexp: `{"task":"approve"}`,
+			msg:       `valid decode mode`,
+		},
+		{
+			body:      `{"date":"20240201","title":"Water plants","repeat":"d 7","mode":"sometimes"}`,
+			resCode:   http.StatusUnprocessableEntity,
+			resRegexp: `{"error":"taskdecode: error - {mode:unknown mode}"}`,
+			msg:       `invalid decode mode`,
+		},
+		{
+			body:      `{"date":"20240201","title":"Backup","repeat":"w 2","exdates":["20241231"]}`,
+			resCode:   http.StatusOK,
+			resRegexp: `{"task":"approve"}`,
+			msg:       `valid decode exception dates`,
+		},
+		{
+			body:      `{"date":"20240201","title":"Backup","repeat":"w 2","exdates":["31.12.2024"]}`,
+			resCode:   http.StatusUnprocessableEntity,
+			resRegexp: `{"error":"taskdecode: error - {exdates:invalid exception date}"}`,
+			msg:       `invalid decode exception dates`,
+		},
+		{
+			body:      `{"date":"20240201","time":"08:30","title":"Drink water","repeat":"h 4 09:00-18:00"}`,
+			resCode:   http.StatusOK,
+			resRegexp: `{"task":"approve"}`,
+			msg:       `valid decode time and hourly repeat`,
+		},
+		{
+			body:      `{"date":"20240201","time":"8:30","title":"Drink water","repeat":"h 4"}`,
+			resCode:   http.StatusUnprocessableEntity,
+			resRegexp: `{"error":"taskdecode: error - {time:invalid time format}"}`,
+			msg:       `invalid decode time`,
+		},
+	}
+
+	for _, test := range dataForRequest {
+		req, err := http.NewRequest(http.MethodPost, "/test", bytes.NewBuffer([]byte(test.body)))
+		require.NoError(t, err, fmt.Sprintf("request create error - %v", err))
+		req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, test.resCode, w.Code, "status code not equal "+test.msg)
+		assert.Regexp(t, test.resRegexp, w.Body.String(), "other body from response "+test.msg)
+	}
+}
+
+func TestPreviewDecode_Decode(t *testing.T) {
+	mux := http.ServeMux{}
+
+	mux.HandleFunc("GET /test", func(w http.ResponseWriter, r *http.Request) {
+		deserialize := NewPreviewDecode(time.Now(), nextdate.AlgorithmNextDate)
+		if err := deserialize.Decode(r); err != nil {
+			common.EncodeJSON(w, http.StatusBadRequest, common.Message{"error": err.Error()})
+			return
+		}
+		common.EncodeJSON(w, http.StatusOK, common.Message{"limit": deserialize.Entity().PassLimit()})
+	})
+
+	dataForRequest := []struct {
+		query     string
+		resCode   int
+		resRegexp string
+		msg       string
+	}{
+		{
+			query:     `repeat=d+5`,
+			resCode:   http.StatusOK,
+			resRegexp: `{"limit":10}`,
+			msg:       `valid decode with default number of dates`,
+		},
+		{
+			query:     `id=3&now=20240101&to=20240301`,
+			resCode:   http.StatusOK,
+			resRegexp: `{"limit":366}`,
+			msg:       `valid decode all dates of window`,
+		},
+		{
+			query:     `date=20240101&repeat=w+2&n=4`,
+			resCode:   http.StatusOK,
+			resRegexp: `{"limit":4}`,
+			msg:       `valid decode with number of dates`,
+		},
+		{
+			query:     `date=2024&n=367&now=20240301&to=20240201`,
+			resCode:   http.StatusBadRequest,
+			resRegexp: `{"error":"previewdecode: error - {date:invalid date format},{n:out of range},{repeat:empty},{to:before now}"}`,
+			msg:       `invalid decode`,
+		},
+		{
+			query:     `id=0&algorithm=alien`,
+			resCode:   http.StatusBadRequest,
+			resRegexp: `{"error":"previewdecode: error - {id:not numeric}"}`,
+			msg:       `invalid decode ID`,
+		},
+		{
+			query:     `repeat=d+1&describe=de`,
+			resCode:   http.StatusBadRequest,
+			resRegexp: `{"error":"previewdecode: error - {describe:unknown language}"}`,
+			msg:       `invalid decode language`,
+		},
+		{
+			query:     `repeat=m+32`,
+			resCode:   http.StatusBadRequest,
+			resRegexp: `{"error":"previewdecode: error - {repeat:invalid repeat data at position 2: day of month must be 1..31, -1 or -2}"}`,
+			msg:       `invalid decode repeat with position`,
+		},
+	}
+
+	for _, test := range dataForRequest {
+		req, err := http.NewRequest(http.MethodGet, "/test?"+test.query, nil)
+		require.NoError(t, err, fmt.Sprintf("request create error - %v", err))
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, test.resCode, w.Code, "status code not equal "+test.msg)
+		assert.Regexp(t, test.resRegexp, w.Body.String(), "other body from response "+test.msg)
+	}
+}
+
+// repeat without algorithm is checked by default algorithm from config, same as in usecase
+func TestDecode_DefaultAlgorithm(t *testing.T) {
+	dataForDecode := []struct {
+		algorithm string
+		repeat    string
+		valid     bool
+		msg       string
+	}{
+		{
+			algorithm: nextdate.AlgorithmNextDate,
+			repeat:    "d 5",
+			valid:     true,
+			msg:       `custom grammar by default nextdate`,
+		},
+		{
+			algorithm: nextdate.AlgorithmRRule,
+			repeat:    "d 5",
+			valid:     false,
+			msg:       `custom grammar by default rrule`,
+		},
+		{
+			algorithm: nextdate.AlgorithmRRule,
+			repeat:    "FREQ=WEEKLY;BYDAY=MO",
+			valid:     true,
+			msg:       `rrule by default rrule`,
+		},
+	}
+
+	for _, test := range dataForDecode {
+		body := fmt.Sprintf(`{"date":"20240201","title":"Summarize","repeat":"%s"}`, test.repeat)
+		req, err := http.NewRequest(http.MethodPost, "/test", bytes.NewBuffer([]byte(body)))
+		require.NoError(t, err, fmt.Sprintf("request create error - %v", err))
+		req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+		err = NewTaskDecode(test.algorithm).Decode(req)
+		assert.Equal(t, test.valid, err == nil, fmt.Sprintf("task decode %s - %v", test.msg, err))
+
+		req, err = http.NewRequest(http.MethodGet, "/test?repeat="+url.QueryEscape(test.repeat), nil)
+		require.NoError(t, err, fmt.Sprintf("request create error - %v", err))
+		preview := NewPreviewDecode(time.Now(), test.algorithm)
+		err = preview.Decode(req)
+		assert.Equal(t, test.valid, err == nil, fmt.Sprintf("preview decode %s - %v", test.msg, err))
+		if err == nil {
+			detected := nextdate.DetectAlgorithm(test.repeat, test.algorithm)
+			assert.Equal(t, detected, preview.Entity().PassTask().Algorithm, "algorithm of ad-hoc rule "+test.msg)
+		}
+	}
+}
+
+func TestClockDecode_Decode(t *testing.T) {
+	mux := http.ServeMux{}
+
+	mux.HandleFunc("PUT /test", func(w http.ResponseWriter, r *http.Request) {
+		deserialize := NewClockDecode()
+		if err := deserialize.Decode(r); err != nil {
+			common.EncodeJSON(w, http.StatusBadRequest, common.Message{"error": err.Error()})
+			return
+		}
+		setting := deserialize.Entity()
+		common.EncodeJSON(w, http.StatusOK, common.Message{
+			"freeze": setting.PassFreeze().Format("20060102 15:04"),
+			"offset": setting.PassOffset().String(),
+		})
+	})
+
+	dataForRequest := []struct {
+		body      string
+		resCode   int
+		resRegexp string
+		msg       string
+	}{
+		{
+			body:      `{"now":"20240229 10:00"}`,
+			resCode:   http.StatusOK,
+			resRegexp: `{"freeze":"20240229 10:00","offset":"0s"}`,
+			msg:       `valid decode freeze`,
+		},
+		{
+			body:      `{"offset":"-36h"}`,
+			resCode:   http.StatusOK,
+			resRegexp: `{"freeze":"00010101 00:00","offset":"-36h0m0s"}`,
+			msg:       `valid decode offset`,
+		},
+		{
+			body:      `{"now":"2024-02-29","offset":"week"}`,
+			resCode:   http.StatusBadRequest,
+			resRegexp: `{"error":"clockdecode: error - {now:invalid date format},{offset:now and offset can't be used together}"}`,
+			msg:       `invalid decode`,
+		},
+		{
+			body:      `{"offset":"week"}`,
+			resCode:   http.StatusBadRequest,
+			resRegexp: `{"error":"clockdecode: error - {offset:invalid offset}"}`,
+			msg:       `invalid decode offset`,
+		},
+	}
+
+	for _, test := range dataForRequest {
+		req, err := http.NewRequest(http.MethodPut, "/test", bytes.NewBuffer([]byte(test.body)))
+		require.NoError(t, err, fmt.Sprintf("request create error - %v", err))
+		req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, test.resCode, w.Code, "status code not equal "+test.msg)
+		assert.Regexp(t, test.resRegexp, w.Body.String(), "other body from response "+test.msg)
+	}
+}
+
+func TestDoneDecode_Decode(t *testing.T) {
+	mux := http.ServeMux{}
+
+	mux.HandleFunc("POST /test", func(w http.ResponseWriter, r *http.Request) {
+		deserialize := NewDoneDecode()
+		if err := deserialize.Decode(r); err != nil {
+			common.EncodeJSON(w, http.StatusBadRequest, common.Message{"error": err.Error()})
+			return
+		}
+		common.EncodeJSON(w, http.StatusOK, common.Message{"note": deserialize.Note})
+	})
+
+	dataForRequest := []struct {
+		body      string
+		resCode   int
+		resRegexp string
+		msg       string
+	}{
+		{
+			body:      ``,
+			resCode:   http.StatusOK,
+			resRegexp: `{"note":""}`,
+			msg:       `valid decode without body`,
+		},
+		{
+			body:      `{"note":"ran 5 km"}`,
+			resCode:   http.StatusOK,
+			resRegexp: `{"note":"ran 5 km"}`,
+			msg:       `valid decode note`,
+		},
+		{
+			body:      `{"note":"` + strings.Repeat("n", model.CompletionNoteLen+1) + `"}`,
+			resCode:   http.StatusBadRequest,
+			resRegexp: `{"error":"donedecode: error - {note:length exceeded}"}`,
+			msg:       `invalid decode long note`,
+		},
 	}
 
 	for _, test := range dataForRequest {
@@ -102,3 +407,186 @@ func TestTaskDecode_Decode(t *testing.T) {
 		assert.Regexp(t, test.resRegexp, w.Body.String(), "other body from response "+test.msg)
 	}
 }
+
+func TestAgendaDecode_Decode(t *testing.T) {
+	mux := http.ServeMux{}
+
+	mux.HandleFunc("GET /test", func(w http.ResponseWriter, r *http.Request) {
+		deserialize := NewAgendaDecode(time.Date(2024, 2, 28, 23, 30, 0, 0, time.UTC))
+		if err := deserialize.Decode(r); err != nil {
+			common.EncodeJSON(w, http.StatusBadRequest, common.Message{"error": err.Error()})
+			return
+		}
+		agenda := deserialize.Entity()
+		common.EncodeJSON(w, http.StatusOK, common.Message{
+			"from": agenda.PassFrom().Format("20060102"),
+			"to":   agenda.PassTo().Format("20060102"),
+		})
+	})
+
+	dataForRequest := []struct {
+		query     string
+		resCode   int
+		resRegexp string
+		msg       string
+	}{
+		{
+			query:     ``,
+			resCode:   http.StatusOK,
+			resRegexp: `{"from":"20240228","to":"20240305"}`,
+			msg:       `valid decode week from today`,
+		},
+		{
+			query:     `from=20240101&to=20240131`,
+			resCode:   http.StatusOK,
+			resRegexp: `{"from":"20240101","to":"20240131"}`,
+			msg:       `valid decode window`,
+		},
+		{
+			query:     `from=20240101&to=20240101`,
+			resCode:   http.StatusOK,
+			resRegexp: `{"from":"20240101","to":"20240101"}`,
+			msg:       `valid decode one day`,
+		},
+		{
+			query:     `from=2024-01-01&to=20240101`,
+			resCode:   http.StatusBadRequest,
+			resRegexp: `{"error":"agendadecode: error - {from:invalid date format}"}`,
+			msg:       `invalid decode first day`,
+		},
+		{
+			query:     `from=20240201&to=20240131`,
+			resCode:   http.StatusBadRequest,
+			resRegexp: `{"error":"agendadecode: error - {to:before from}"}`,
+			msg:       `invalid decode end before start`,
+		},
+		{
+			query:     `from=20240101&to=20240402`,
+			resCode:   http.StatusBadRequest,
+			resRegexp: `{"error":"agendadecode: error - {to:out of range}"}`,
+			msg:       `invalid decode window more than limit`,
+		},
+	}
+
+	for _, test := range dataForRequest {
+		req, err := http.NewRequest(http.MethodGet, "/test?"+test.query, nil)
+		require.NoError(t, err, fmt.Sprintf("request create error - %v", err))
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, test.resCode, w.Code, "status code not equal "+test.msg)
+		assert.Regexp(t, test.resRegexp, w.Body.String(), "other body from response "+test.msg)
+	}
+}
+
+func TestTaskListDecode_Decode(t *testing.T) {
+	mux := http.ServeMux{}
+
+	mux.HandleFunc("GET /test", func(w http.ResponseWriter, r *http.Request) {
+		deserialize := NewTaskListDecode(time.Date(2024, 1, 15, 22, 0, 0, 0, time.UTC))
+		if err := deserialize.Decode(r); err != nil {
+			common.EncodeJSON(w, http.StatusBadRequest, common.Message{"error": err.Error()})
+			return
+		}
+		property := deserialize.Entity()
+		res := common.Message{
+			"word":  property.PassWord(),
+			"limit": property.PassLimit(),
+		}
+		if cursor := property.PassCursor(); cursor != nil {
+			res["cursor"] = fmt.Sprintf("%s %s %d", cursor.PassDate(), cursor.PassTime(), cursor.PassID())
+		}
+		filter := property.PassFilter()
+		if from, to := filter.PassFrom(), filter.PassTo(); !from.IsZero() || !to.IsZero() {
+			res["window"] = from.Format("20060102") + "-" + to.Format("20060102")
+		}
+		if before := filter.PassBefore(); !before.IsZero() {
+			res["before"] = before.Format("20060102")
+		}
+		if filter.PassKind() != "" || filter.PassField() != "" || filter.PassSort() != "" {
+			res["filter"] = fmt.Sprintf("%s %s %s %t",
+				filter.PassKind(), filter.PassField(), filter.PassSort(), filter.IsDesc())
+		}
+		common.EncodeJSON(w, http.StatusOK, res)
+	})
+
+	dataForRequest := []struct {
+		query     string
+		resCode   int
+		resRegexp string
+		msg       string
+	}{
+		{
+			query:     ``,
+			resCode:   http.StatusOK,
+			resRegexp: `{"limit":50,"word":""}`,
+			msg:       `valid decode first page with default limit`,
+		},
+		{
+			query:     `search=bread&limit=20&cursor=MjAyNDAxMDEsMDk6MzAsMTI`,
+			resCode:   http.StatusOK,
+			resRegexp: `{"cursor":"20240101 09:30 12","limit":20,"word":"bread"}`,
+			msg:       `valid decode page after cursor`,
+		},
+		{
+			query:     `limit=0`,
+			resCode:   http.StatusBadRequest,
+			resRegexp: `{"error":"tasklistdecode: error - {limit:out of range}"}`,
+			msg:       `invalid decode zero limit`,
+		},
+		{
+			query:     `limit=101&cursor=20240101`,
+			resCode:   http.StatusBadRequest,
+			resRegexp: `{"error":"tasklistdecode: error - {cursor:invalid cursor},{limit:out of range}"}`,
+			msg:       `invalid decode limit more than max and cursor`,
+		},
+		{
+			query:     `search=bread&from=20240101&to=20240131&kind=once&overdue=true&in=title&sort=title&order=desc`,
+			resCode:   http.StatusOK,
+			resRegexp: `{"before":"20240115","filter":"once title title true","limit":50,"window":"20240101-20240131","word":"bread"}`,
+			msg:       `valid decode all filters`,
+		},
+		{
+			query:     `to=20240131&overdue=false&sort=id`,
+			resCode:   http.StatusOK,
+			resRegexp: `{"filter":"  id false","limit":50,"window":"00010101-20240131","word":""}`,
+			msg:       `valid decode without from and not overdue`,
+		},
+		{
+			query:     `from=20240201&to=20240131&kind=daily&overdue=yes&in=body&sort=time&order=up`,
+			resCode:   http.StatusBadRequest,
+			resRegexp: `{"error":"tasklistdecode: error - {in:unknown value},{kind:unknown value},{order:unknown value},{overdue:unknown value},{sort:unknown value},{to:before from}"}`,
+			msg:       `invalid decode unknown values and window`,
+		},
+		{
+			query:     `search=title:report%20-draft`,
+			resCode:   http.StatusOK,
+			resRegexp: `{"limit":50,"word":""}`,
+			msg:       `valid decode structured search`,
+		},
+		{
+			query:     `search=title:report%20before:junk`,
+			resCode:   http.StatusBadRequest,
+			resRegexp: `{"error":"tasklistdecode: error - {search:invalid search query at position 20: invalid date}"}`,
+			msg:       `invalid decode structured search`,
+		},
+		{
+			query:     `from=2024-01-01&to=31.01.2024`,
+			resCode:   http.StatusBadRequest,
+			resRegexp: `{"error":"tasklistdecode: error - {from:invalid date format},{to:invalid date format}"}`,
+			msg:       `invalid decode dates`,
+		},
+	}
+
+	for _, test := range dataForRequest {
+		req, err := http.NewRequest(http.MethodGet, "/test?"+test.query, nil)
+		require.NoError(t, err, fmt.Sprintf("request create error - %v", err))
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, test.resCode, w.Code, "status code not equal "+test.msg)
+		assert.Regexp(t, test.resRegexp, w.Body.String(), "other body from response "+test.msg)
+	}
+}
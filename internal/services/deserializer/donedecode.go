@@ -0,0 +1,39 @@
+// donedecode - rules for decode optional body of done task from http.Request (/api/task/done)
+package deserializer
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+	"github.com/Ekvo/yandex-practicum-go-final-project/pkg/common"
+)
+
+// DoneDecode - body of request, request without body - completion without note
+//
+// note - optional note of completion for history, max model.CompletionNoteLen characters
+type DoneDecode struct {
+	Note string `json:"note,omitempty"`
+}
+
+func NewDoneDecode() *DoneDecode {
+	return &DoneDecode{}
+}
+
+// Decode - check body if exist and create full error list use map - common.Message
+func (dd *DoneDecode) Decode(r *http.Request) error {
+	if r.ContentLength == 0 {
+		return nil
+	}
+	if err := common.DecodeJSON(r, dd); err != nil {
+		return err
+	}
+	msgErr := make(common.Message)
+	if len(dd.Note) > model.CompletionNoteLen {
+		msgErr["note"] = ErrServicesFiledLengthExceeded.Error()
+	}
+	if len(msgErr) != 0 {
+		return fmt.Errorf("donedecode: error - %s", msgErr.String())
+	}
+	return nil
+}
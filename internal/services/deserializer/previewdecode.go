@@ -0,0 +1,161 @@
+// previewdecode - rules for decode params of preview dates from http.Request (/api/nextdates)
+package deserializer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/lib/nextdate"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/services/entity"
+	"github.com/Ekvo/yandex-practicum-go-final-project/pkg/common"
+)
+
+var (
+	// ErrServicesParamsConflict - stored task (id) and ad-hoc rule (repeat) in one request
+	ErrServicesParamsConflict = errors.New("id and repeat can't be used together")
+
+	// ErrServicesOutOfRange - number is not in allowed range
+	ErrServicesOutOfRange = errors.New("out of range")
+
+	// ErrServicesWindowBeforeNow - end of window is before start
+	ErrServicesWindowBeforeNow = errors.New("before now")
+)
+
+// DefaultOccurrences - number of dates without params 'n' and 'to'
+const DefaultOccurrences = 10
+
+// PreviewDecode - params of URL query
+//
+// id     - stored task, othercase ad-hoc rule: date (empty - now), repeat, algorithm
+// now    - dates not before now (empty - today of clock)
+// n      - number of dates 1..nextdate.MaxOccurrences
+// to     - end of window, without 'n' - all dates of window (not more nextdate.MaxOccurrences)
+// describe - language of description of rule ("en", "ru")
+type PreviewDecode struct {
+	ID        string
+	Now       string
+	Date      string
+	Repeat    string
+	Algorithm string
+	N         string
+	To        string
+	Describe  string
+
+	// today - "now" of clock in time zone of request, used without param 'now'
+	today time.Time
+
+	// defaultAlgorithm - algorithm of ad-hoc rule without 'algorithm' and not in RRULE syntax (ALGORITHM_TASK_DATE)
+	defaultAlgorithm string
+
+	preview *entity.TaskPreview
+}
+
+func NewPreviewDecode(today time.Time, defaultAlgorithm string) *PreviewDecode {
+	return &PreviewDecode{today: today, defaultAlgorithm: defaultAlgorithm}
+}
+
+func (pd *PreviewDecode) Entity() *entity.TaskPreview {
+	return pd.preview
+}
+
+// Decode - check all params and create full error list use map - common.Message
+func (pd *PreviewDecode) Decode(r *http.Request) error {
+	query := r.URL.Query()
+	pd.ID = query.Get("id")
+	pd.Now = query.Get("now")
+	pd.Date = query.Get("date")
+	pd.Repeat = query.Get("repeat")
+	pd.Algorithm = query.Get("algorithm")
+	pd.N = query.Get("n")
+	pd.To = query.Get("to")
+	pd.Describe = query.Get("describe")
+
+	msgErr := make(common.Message)
+	taskID := uint(0)
+	if idSTR := pd.ID; idSTR != "" {
+		if id, err := strconv.ParseUint(idSTR, 10, 64); err != nil || id == 0 {
+			msgErr["id"] = ErrServicesWrongID.Error()
+		} else {
+			taskID = uint(id)
+		}
+		if pd.Repeat != "" {
+			msgErr["id"] = ErrServicesParamsConflict.Error()
+		}
+	}
+	task := model.TaskModel{}
+	if pd.ID == "" {
+		task = pd.rule(msgErr)
+	}
+	now := pd.today
+	if pd.Now != "" {
+		date, err := time.Parse(model.DateFormat, pd.Now)
+		if err != nil {
+			msgErr["now"] = ErrServicesInvalidDate.Error()
+		}
+		now = date
+	}
+	to := time.Time{}
+	if pd.To != "" {
+		date, err := time.Parse(model.DateFormat, pd.To)
+		if err != nil {
+			msgErr["to"] = ErrServicesInvalidDate.Error()
+		} else if date.Before(common.ReduceTimeToDayUTC(now)) {
+			msgErr["to"] = ErrServicesWindowBeforeNow.Error()
+		}
+		to = date
+	}
+	limit := DefaultOccurrences
+	if pd.To != "" {
+		limit = nextdate.MaxOccurrences
+	}
+	if pd.N != "" {
+		n, err := strconv.Atoi(pd.N)
+		if err != nil || n < 1 || n > nextdate.MaxOccurrences {
+			msgErr["n"] = ErrServicesOutOfRange.Error()
+		}
+		limit = n
+	}
+	if pd.Describe != "" && !nextdate.IsLanguage(pd.Describe) {
+		msgErr["describe"] = nextdate.ErrNextDateUnknownLanguage.Error()
+	}
+	if len(msgErr) != 0 {
+		return fmt.Errorf("previewdecode: error - %s", msgErr.String())
+	}
+	pd.preview = entity.NewTaskPreview(taskID, task, now, to, limit, pd.Describe)
+	return nil
+}
+
+// rule - check ad-hoc rule same as in 'TaskDecode', repeat can't be empty
+// task contains algorithm of check (param or detected by syntax), usecase doesn't detect it again
+func (pd *PreviewDecode) rule(msgErr common.Message) model.TaskModel {
+	algorithm := pd.Algorithm
+	if algorithm != "" {
+		if _, err := nextdate.Lookup(algorithm); err != nil {
+			msgErr["algorithm"] = err.Error()
+		}
+	} else {
+		algorithm = nextdate.DetectAlgorithm(pd.Repeat, pd.defaultAlgorithm)
+	}
+	repeat := pd.Repeat
+	if repeat == "" {
+		msgErr["repeat"] = ErrServicesFiledEmpty.Error()
+	} else if len(repeat) > model.TaskRepeatLen {
+		msgErr["repeat"] = ErrServicesFiledLengthExceeded.Error()
+	} else if _, wrongAlgorithm := msgErr["algorithm"]; !wrongAlgorithm {
+		normalized, err := nextdate.Validate(algorithm, repeat)
+		if err != nil {
+			msgErr["repeat"] = err.Error()
+		}
+		repeat = normalized
+	}
+	if pd.Date != "" {
+		if _, err := time.Parse(model.DateFormat, pd.Date); err != nil {
+			msgErr["date"] = ErrServicesInvalidDate.Error()
+		}
+	}
+	return model.TaskModel{Date: pd.Date, Repeat: repeat, Algorithm: algorithm}
+}
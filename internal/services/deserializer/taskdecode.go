@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/lib/nextdate"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
 	"github.com/Ekvo/yandex-practicum-go-final-project/pkg/common"
 )
@@ -24,6 +25,9 @@ var (
 
 	// ErrBizInvalidDate - wrong format of date
 	ErrServicesInvalidDate = errors.New("invalid date format")
+
+	// ErrServicesInvalidTime - wrong format of time of day
+	ErrServicesInvalidTime = errors.New("invalid time format")
 )
 
 // TaskValidtor - rules for deserialize object 'TaskModel'
@@ -31,16 +35,29 @@ var (
 type TaskDecode struct {
 	ID      string `json:"id,omitempty"`
 	Date    string `json:"date"`
+	Time    string `json:"time,omitempty"`
 	Title   string `json:"title"`
 	Comment string `json:"comment,omitempty"`
 	Repeat  string `json:"repeat"`
 
+	// Algorithm - name of algorithm for repeat (lib/nextdate/registry.go), empty - by syntax or default
+	Algorithm string `json:"algorithm,omitempty"`
+
+	// Mode - continue series after done from calendar or from day of completion (lib/nextdate/mode.go)
+	Mode string `json:"mode,omitempty"`
+
+	// Exdates - exception dates of series in format '20060102' (lib/nextdate/exdate.go)
+	Exdates []string `json:"exdates,omitempty"`
+
+	// defaultAlgorithm - algorithm of repeat without 'Algorithm' and not in RRULE syntax (ALGORITHM_TASK_DATE)
+	defaultAlgorithm string
+
 	task model.TaskModel `json:"-"`
 }
 
-// NewTaskDecode - if need add default property
-func NewTaskDecode() *TaskDecode {
-	return &TaskDecode{}
+// NewTaskDecode - take name of default algorithm from config, same as in usecase
+func NewTaskDecode(defaultAlgorithm string) *TaskDecode {
+	return &TaskDecode{defaultAlgorithm: defaultAlgorithm}
 }
 
 // Model - return task
@@ -53,6 +70,9 @@ func (td TaskDecode) Model() model.TaskModel {
 //
 // for check all property 'TaskModel' and create full error list if exist bad data
 // use map - common.Message look (../../pkg/common/common.go)
+//
+// repeat is checked by syntax of algorithm: empty algorithm -> RRULE or default algorithm (custom grammar - lib/nextdate/rule.go)
+// wrong repeat -> position and reason of error
 func (td *TaskDecode) Decode(r *http.Request) error {
 	if err := common.DecodeJSON(r, td); err != nil {
 		return err
@@ -76,8 +96,31 @@ func (td *TaskDecode) Decode(r *http.Request) error {
 	if len(td.Comment) > model.TaskCommentLen {
 		msgErr["comment"] = ErrServicesFiledLengthExceeded.Error()
 	}
-	if len(td.Repeat) > model.TaskRepeatLen {
+	algorithm := td.Algorithm
+	if algorithm != "" {
+		if _, err := nextdate.Lookup(algorithm); err != nil {
+			msgErr["algorithm"] = err.Error()
+		}
+	} else {
+		algorithm = nextdate.DetectAlgorithm(td.Repeat, td.defaultAlgorithm)
+	}
+	repeat := td.Repeat
+	if len(repeat) > model.TaskRepeatLen {
 		msgErr["repeat"] = ErrServicesFiledLengthExceeded.Error()
+	} else if _, wrongAlgorithm := msgErr["algorithm"]; repeat != "" && !wrongAlgorithm {
+		// syntax error with position, RRULE is stored in canonical form
+		normalized, err := nextdate.Validate(algorithm, repeat)
+		if err != nil {
+			msgErr["repeat"] = err.Error()
+		}
+		repeat = normalized
+	}
+	if !nextdate.IsMode(td.Mode) {
+		msgErr["mode"] = nextdate.ErrNextDateUnknownMode.Error()
+	}
+	exdates, err := nextdate.NormalizeExdates(td.Exdates)
+	if err != nil {
+		msgErr["exdates"] = err.Error()
 	}
 	date := td.Date
 	if date != "" {
@@ -85,13 +128,23 @@ func (td *TaskDecode) Decode(r *http.Request) error {
 			msgErr["date"] = ErrServicesInvalidDate.Error()
 		}
 	}
+	clock := td.Time
+	if clock != "" {
+		if _, err := time.Parse(model.TimeFormat, clock); err != nil || len(clock) != len(model.TimeFormat) {
+			msgErr["time"] = ErrServicesInvalidTime.Error()
+		}
+	}
 	if len(msgErr) != 0 {
 		return fmt.Errorf("taskdecode: error - %s", msgErr.String())
 	}
 	td.task.ID = taskID
 	td.task.Date = date
+	td.task.Time = clock
 	td.task.Title = td.Title
 	td.task.Comment = td.Comment
-	td.task.Repeat = td.Repeat
+	td.task.Repeat = repeat
+	td.task.Algorithm = td.Algorithm
+	td.task.Mode = td.Mode
+	td.task.Exdates = exdates
 	return nil
 }
@@ -0,0 +1,140 @@
+// tasklistdecode - rules for decode params of task list from http.Request (/api/tasks)
+package deserializer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/services/entity"
+	"github.com/Ekvo/yandex-practicum-go-final-project/pkg/common"
+)
+
+var (
+	// ErrServicesInvalidCursor - cursor is not 'next_cursor' of response
+	ErrServicesInvalidCursor = errors.New("invalid cursor")
+
+	// ErrServicesUnknownValue - value of param is not one of allowed
+	ErrServicesUnknownValue = errors.New("unknown value")
+)
+
+// TaskListDecode - params of URL query, all filters are combined
+//
+// search  - word, date in format 02.01.2006 or structured search (title:report -draft), empty - all tasks
+// limit   - number of tasks on page 1..entity.MaxTaskLimit, empty - entity.DefaultTaskLimit
+// cursor  - 'next_cursor' of previous page, empty - first page
+// from    - first date of tasks (include) in format 20060102, empty - without bound
+// to      - last date of tasks (include), not before 'from', empty - without bound
+// kind    - recurring or once, empty - all tasks
+// overdue - true -> tasks with date before today of clock
+// in      - title or comment, search by word only in this field, empty - both
+// sort    - date, title or id, empty - by date (search by word - by relevance)
+// order   - asc or desc, empty - asc
+type TaskListDecode struct {
+	Search  string
+	Limit   string
+	Cursor  string
+	From    string
+	To      string
+	Kind    string
+	Overdue string
+	In      string
+	Sort    string
+	Order   string
+
+	// today - "now" of clock in time zone of request, used by 'overdue'
+	today time.Time
+
+	property *entity.TaskProperty
+}
+
+func NewTaskListDecode(today time.Time) *TaskListDecode {
+	return &TaskListDecode{today: today}
+}
+
+func (td *TaskListDecode) Entity() *entity.TaskProperty {
+	return td.property
+}
+
+// Decode - check all params and create full error list use map - common.Message
+func (td *TaskListDecode) Decode(r *http.Request) error {
+	query := r.URL.Query()
+	td.Search = query.Get("search")
+	td.Limit = query.Get("limit")
+	td.Cursor = query.Get("cursor")
+	td.From = query.Get("from")
+	td.To = query.Get("to")
+	td.Kind = query.Get("kind")
+	td.Overdue = query.Get("overdue")
+	td.In = query.Get("in")
+	td.Sort = query.Get("sort")
+	td.Order = query.Get("order")
+
+	msgErr := make(common.Message)
+	search, err := entity.ParseTaskQuery(td.Search)
+	if err != nil {
+		msgErr["search"] = err.Error()
+	}
+	limit := 0
+	if td.Limit != "" {
+		n, err := strconv.Atoi(td.Limit)
+		if err != nil || n < 1 || n > entity.MaxTaskLimit {
+			msgErr["limit"] = ErrServicesOutOfRange.Error()
+		}
+		limit = n
+	}
+	var cursor *entity.TaskCursor
+	if td.Cursor != "" {
+		c, ok := entity.ParseTaskCursor(td.Cursor)
+		if !ok {
+			msgErr["cursor"] = ErrServicesInvalidCursor.Error()
+		}
+		cursor = c
+	}
+	var from, to, before time.Time
+	if td.From != "" {
+		date, err := time.Parse(model.DateFormat, td.From)
+		if err != nil {
+			msgErr["from"] = ErrServicesInvalidDate.Error()
+		}
+		from = date
+	}
+	if td.To != "" {
+		date, err := time.Parse(model.DateFormat, td.To)
+		if err != nil {
+			msgErr["to"] = ErrServicesInvalidDate.Error()
+		} else if !from.IsZero() && date.Before(from) {
+			msgErr["to"] = ErrServicesWindowBeforeStart.Error()
+		}
+		to = date
+	}
+	if td.Overdue != "" {
+		overdue, err := strconv.ParseBool(td.Overdue)
+		if err != nil {
+			msgErr["overdue"] = ErrServicesUnknownValue.Error()
+		} else if overdue {
+			before = common.ReduceTimeToDayUTC(td.today)
+		}
+	}
+	checkValue(msgErr, "kind", td.Kind, entity.KindRecurring, entity.KindOnce)
+	checkValue(msgErr, "in", td.In, entity.FieldTitle, entity.FieldComment)
+	checkValue(msgErr, "sort", td.Sort, entity.SortDate, entity.SortTitle, entity.SortID)
+	checkValue(msgErr, "order", td.Order, "asc", "desc")
+	if len(msgErr) != 0 {
+		return fmt.Errorf("tasklistdecode: error - %s", msgErr.String())
+	}
+	filter := entity.NewTaskFilter(from, to, before, td.Kind, td.In, td.Sort, td.Order == "desc")
+	td.property = entity.NewTaskProperty(td.Search, uint(limit)).After(cursor).Filter(filter).Query(search)
+	return nil
+}
+
+// checkValue - not empty 'value' of param 'key' is one of 'allowed', othercase add error to 'msgErr'
+func checkValue(msgErr common.Message, key, value string, allowed ...string) {
+	if value != "" && !slices.Contains(allowed, value) {
+		msgErr[key] = ErrServicesUnknownValue.Error()
+	}
+}
@@ -0,0 +1,29 @@
+// clocksetting - describes change of debug clock (/api/debug/clock)
+package entity
+
+import "time"
+
+type ClockSetting struct {
+	// freeze - "now" stops at this moment, zero - not frozen
+	freeze time.Time
+
+	// offset - "now" is moved from real time, used only without 'freeze'
+	offset time.Duration
+}
+
+// NewClockSetting - zero 'freeze' and 'offset' -> reset of clock
+func NewClockSetting(freeze time.Time, offset time.Duration) *ClockSetting {
+	return &ClockSetting{freeze: freeze, offset: offset}
+}
+
+func (c *ClockSetting) IsFreeze() bool {
+	return !c.freeze.IsZero()
+}
+
+func (c *ClockSetting) PassFreeze() time.Time {
+	return c.freeze
+}
+
+func (c *ClockSetting) PassOffset() time.Duration {
+	return c.offset
+}
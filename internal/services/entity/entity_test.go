@@ -1,22 +1,201 @@
 package entity
 
 import (
+	"encoding/base64"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
 )
 
 func TestNewTaskProperty(t *testing.T) {
-	property := NewTaskProperty("word", 123)
+	property := NewTaskProperty("word", 0)
 	assert.True(t, property.IsWord())
 	assert.False(t, property.IsDate())
-	assert.GreaterOrEqual(t, property.limit, uint(minLimit))
-	assert.LessOrEqual(t, property.limit, uint(maxLimit))
+	assert.Equal(t, uint(DefaultTaskLimit), property.PassLimit(), "without limit")
+	assert.Nil(t, property.PassCursor(), "first page")
 
 	property = NewTaskProperty(time.Now().UTC().Format(dateFormatFromParam), 123)
 	assert.True(t, property.IsDate())
 	assert.False(t, property.IsWord())
-	assert.GreaterOrEqual(t, property.limit, uint(minLimit))
-	assert.LessOrEqual(t, property.limit, uint(maxLimit))
+	assert.Equal(t, uint(MaxTaskLimit), property.PassLimit(), "limit more than max")
+
+	assert.Equal(t, uint(7), NewTaskProperty("", 7).PassLimit())
+}
+
+func TestTaskCursor(t *testing.T) {
+	task := model.TaskModel{ID: 12, Date: "20240101", Time: "09:30", Title: "buy bread, milk", Comment: "shop"}
+	cursor := NewTaskCursor(task)
+
+	parsed, ok := ParseTaskCursor(cursor.Encode())
+	assert.True(t, ok)
+	assert.Equal(t, cursor, parsed, "encode and parse, title with comma")
+	assert.Equal(t, model.TaskModel{ID: 12, Date: "20240101", Time: "09:30", Title: "buy bread, milk"}, parsed.PassTask())
+
+	property := NewTaskProperty("", 0).After(cursor)
+	assert.False(t, property.IsAfterCursor(task), "same task")
+	assert.True(t, property.IsAfterCursor(model.TaskModel{ID: 13, Date: "20240101", Time: "09:30"}), "next ID")
+	assert.True(t, property.IsAfterCursor(model.TaskModel{ID: 1, Date: "20240101", Time: "10:00"}), "later time")
+	assert.False(t, property.IsAfterCursor(model.TaskModel{ID: 13, Date: "20240101"}), "whole day is first")
+	assert.True(t, property.IsAfterCursor(model.TaskModel{ID: 1, Date: "20240102"}), "next day")
+
+	for _, wrong := range []string{
+		"",
+		"not base64!",
+		base64.RawURLEncoding.EncodeToString([]byte("20240101,")),
+		base64.RawURLEncoding.EncodeToString([]byte("2024-01-01,,1,")),
+		base64.RawURLEncoding.EncodeToString([]byte("20240101,25:00,1,")),
+		base64.RawURLEncoding.EncodeToString([]byte("20240101,,-1,")),
+		base64.RawURLEncoding.EncodeToString([]byte("20240101,,x,")),
+	} {
+		_, ok := ParseTaskCursor(wrong)
+		assert.False(t, ok, wrong)
+	}
+	_, ok = ParseTaskCursor(base64.RawURLEncoding.EncodeToString([]byte("20240101,,1,")))
+	assert.True(t, ok, "task for whole day with empty title")
+	_, ok = ParseTaskCursor(base64.RawURLEncoding.EncodeToString([]byte("20240101,,1")))
+	assert.True(t, ok, "cursor without title")
+}
+
+func TestTaskProperty_Filter(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC)
+	}
+	once := model.TaskModel{ID: 1, Date: "20240105", Title: "b"}
+	recurring := model.TaskModel{ID: 2, Date: "20240110", Title: "a", Repeat: "d 1"}
+
+	property := NewTaskProperty("", 0)
+	assert.True(t, property.Matches(once), "without filter")
+	assert.True(t, property.Less(once, recurring), "by date")
+	assert.Equal(t, SortDate, property.SortKey())
+
+	for _, test := range []struct {
+		filter    *TaskFilter
+		once      bool
+		recurring bool
+		msg       string
+	}{
+		{NewTaskFilter(day(6), time.Time{}, time.Time{}, "", "", "", false), false, true, "from"},
+		{NewTaskFilter(time.Time{}, day(5), time.Time{}, "", "", "", false), true, false, "to include"},
+		{NewTaskFilter(day(1), day(31), time.Time{}, "", "", "", false), true, true, "window"},
+		{NewTaskFilter(time.Time{}, time.Time{}, day(10), "", "", "", false), true, false, "overdue"},
+		{NewTaskFilter(time.Time{}, time.Time{}, time.Time{}, KindRecurring, "", "", false), false, true, "recurring"},
+		{NewTaskFilter(time.Time{}, time.Time{}, time.Time{}, KindOnce, "", "", false), true, false, "once"},
+	} {
+		property := NewTaskProperty("", 0).Filter(test.filter)
+		assert.Equal(t, test.once, property.Matches(once), test.msg)
+		assert.Equal(t, test.recurring, property.Matches(recurring), test.msg)
+	}
+
+	filter := NewTaskFilter(time.Time{}, time.Time{}, time.Time{}, "", "", SortTitle, false)
+	property = NewTaskProperty("", 0).Filter(filter)
+	assert.True(t, property.Less(recurring, once), "by title")
+	assert.True(t, property.Less(once, model.TaskModel{ID: 3, Title: "b"}), "same title by id")
+
+	filter = NewTaskFilter(time.Time{}, time.Time{}, time.Time{}, "", "", SortID, true)
+	property = NewTaskProperty("", 0).Filter(filter).After(NewTaskCursor(recurring))
+	assert.True(t, property.Less(recurring, once), "by id desc")
+	assert.True(t, property.IsAfterCursor(once))
+	assert.False(t, property.IsAfterCursor(model.TaskModel{ID: 3}))
+
+	property = NewTaskProperty("word", 0).After(NewTaskCursor(recurring))
+	assert.True(t, property.IsRelevance())
+	assert.True(t, property.IsAfterCursor(recurring), "relevance has no pages")
+	assert.False(t, property.Filter(filter).IsRelevance(), "search by word with key")
+	assert.False(t, property.IsAfterCursor(recurring), "page of search by word with key")
+}
+
+func TestParseTaskQuery(t *testing.T) {
+	for _, plain := range []string{"", "buy milk", "02.01.2025", "re:meeting", "10:30", "-", `5" screen`} {
+		query, err := ParseTaskQuery(plain)
+		assert.NoError(t, err, plain)
+		assert.Nil(t, query, "plain search "+plain)
+	}
+
+	query, err := ParseTaskQuery(`title:report repeat:w before:2025-06-01 -draft "buy milk" -comment:"big box" after:20250101 after:02.01.2025`)
+	assert.NoError(t, err)
+	assert.Equal(t, []TaskTerm{
+		{word: "report", field: FieldTitle},
+		{word: "draft", negative: true},
+		{word: "buy milk"},
+		{word: "big box", field: FieldComment, negative: true},
+	}, query.PassTerms())
+	assert.Equal(t, "w", query.PassRepeat())
+	assert.Equal(t, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), query.PassBefore())
+	assert.Equal(t, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), query.PassAfter(), "narrowest window")
+
+	for _, test := range []struct {
+		search string
+		pos    int
+		reason string
+	}{
+		{`title:`, 6, "empty value of key 'title'"},
+		{`report -titel:x`, 8, "unknown key 'titel', use quotes for word with ':'"},
+		{`repeat:x`, 7, "unknown repeat 'x'"},
+		{`repeat:d repeat:w`, 9, "key 'repeat' is used twice"},
+		{`-repeat:d`, 0, "key 'repeat' can't be negative"},
+		{`before:2025-13-01`, 7, "invalid date"},
+		{`-draft ""`, 7, "empty term"},
+		{`title:"big box -draft`, 6, "quote without pair"},
+	} {
+		query, err := ParseTaskQuery(test.search)
+		assert.Nil(t, query, test.search)
+		assert.ErrorIs(t, err, ErrEntityInvalidQuery, test.search)
+		assert.Equal(t, &QueryError{Pos: test.pos, Reason: test.reason}, err, test.search)
+	}
+}
+
+func TestTaskProperty_Query(t *testing.T) {
+	query, err := ParseTaskQuery("title:report -draft repeat:w after:2025-01-01 before:2025-06-01")
+	assert.NoError(t, err)
+	property := NewTaskProperty("title:report", 0).Query(query)
+	assert.False(t, property.IsWord(), "structured search instead of word")
+	assert.Same(t, query, property.PassQuery())
+
+	task := model.TaskModel{Date: "20250301", Title: "week report", Comment: "send", Repeat: "w 1"}
+	assert.True(t, property.Matches(task))
+	assert.True(t, query.MatchesTerms(task, strings.Contains))
+
+	for _, wrong := range []model.TaskModel{
+		{Date: "20250301", Title: "week report", Repeat: "d 7"},
+		{Date: "20250601", Title: "week report", Repeat: "w 1"},
+		{Date: "20250101", Title: "week report", Repeat: "w 1"},
+	} {
+		assert.False(t, property.Matches(wrong), wrong)
+	}
+	assert.False(t, query.MatchesTerms(model.TaskModel{Title: "report", Comment: "draft"}, strings.Contains), "negative term")
+	assert.False(t, query.MatchesTerms(model.TaskModel{Comment: "report"}, strings.Contains), "word only in title")
+
+	assert.Nil(t, NewTaskProperty("word", 0).Query(nil).PassQuery(), "plain search")
+}
+
+func TestTaskQuery_RepeatFreq(t *testing.T) {
+	query, err := ParseTaskQuery("repeat:w")
+	assert.NoError(t, err)
+	assert.Equal(t, "WEEKLY", query.PassFreq())
+	property := NewTaskProperty("repeat:w", 0).Query(query)
+	for _, repeat := range []string{"w 1,3", "FREQ=WEEKLY;BYDAY=MO", "rrule:interval=2;freq=weekly"} {
+		assert.True(t, property.Matches(model.TaskModel{Date: "20250301", Repeat: repeat}), repeat)
+	}
+	for _, repeat := range []string{"FREQ=DAILY", "m 1", "RRULE:FREQ=MONTHLY;BYDAY=MO"} {
+		assert.False(t, property.Matches(model.TaskModel{Date: "20250301", Repeat: repeat}), repeat)
+	}
+
+	query, err = ParseTaskQuery("repeat:b")
+	assert.NoError(t, err)
+	assert.Empty(t, query.PassFreq(), "flag without FREQ")
+}
+
+func TestTaskAgenda_Days(t *testing.T) {
+	from := time.Date(2024, 2, 28, 0, 0, 0, 0, time.UTC)
+	agenda := NewTaskAgenda(from, from.AddDate(0, 0, 2))
+	days := agenda.Days()
+	assert.Len(t, days, 3)
+	assert.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), days[1])
+	assert.Equal(t, agenda.PassTo(), days[2])
+
+	assert.Len(t, NewTaskAgenda(from, from).Days(), 1)
 }
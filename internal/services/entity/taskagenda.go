@@ -0,0 +1,33 @@
+// taskagenda - describes window of days for agenda of tasks (/api/agenda?from=20240101&to=20240107)
+package entity
+
+import "time"
+
+type TaskAgenda struct {
+	// first day of window
+	from time.Time
+
+	// last day of window (include)
+	to time.Time
+}
+
+func NewTaskAgenda(from, to time.Time) *TaskAgenda {
+	return &TaskAgenda{from: from, to: to}
+}
+
+func (t *TaskAgenda) PassFrom() time.Time {
+	return t.from
+}
+
+func (t *TaskAgenda) PassTo() time.Time {
+	return t.to
+}
+
+// Days - all days of window from first to last
+func (t *TaskAgenda) Days() []time.Time {
+	var days []time.Time
+	for day := t.from; !day.After(t.to); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+	return days
+}
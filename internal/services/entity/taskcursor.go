@@ -0,0 +1,91 @@
+// taskcursor - describes position of next page in list of tasks (/api/tasks?cursor=)
+package entity
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+)
+
+// TaskCursor - keys of last task of page in order of list (date, time, title, id)
+// next page contains tasks after it, so new or deleted tasks don't shift pages
+// order by relevance has no key and no cursor: relevance of task depends on all tasks (see 'TaskProperty.IsRelevance')
+type TaskCursor struct {
+	date  string
+	time  string
+	title string
+	id    uint
+}
+
+func NewTaskCursor(task model.TaskModel) *TaskCursor {
+	return &TaskCursor{
+		date:  task.Date,
+		time:  task.Time,
+		title: task.Title,
+		id:    task.ID,
+	}
+}
+
+// ParseTaskCursor - cursor from string of 'Encode', wrong string -> false
+func ParseTaskCursor(cursor string) (*TaskCursor, bool) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, false
+	}
+	// title is last, it can contain ',', cursor without title - "date,time,id"
+	fields := strings.SplitN(string(data), ",", 4)
+	if len(fields) < 3 {
+		return nil, false
+	}
+	title := ""
+	if len(fields) == 4 {
+		title = fields[3]
+	}
+	if _, err := time.Parse(model.DateFormat, fields[0]); err != nil {
+		return nil, false
+	}
+	if _, err := time.Parse(model.TimeFormat, fields[1]); err != nil && fields[1] != "" {
+		return nil, false
+	}
+	id, err := strconv.ParseUint(fields[2], 10, 0)
+	if err != nil {
+		return nil, false
+	}
+	return &TaskCursor{
+		date:  fields[0],
+		time:  fields[1],
+		title: title,
+		id:    uint(id),
+	}, true
+}
+
+// Encode - opaque string for response: base64 (URL) of "date,time,id,title"
+func (c *TaskCursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString(
+		[]byte(fmt.Sprintf("%s,%s,%d,%s", c.date, c.time, c.id, c.title)))
+}
+
+// PassTask - keys of last task of page for compare with tasks of list see 'TaskProperty.Less'
+func (c *TaskCursor) PassTask() model.TaskModel {
+	return model.TaskModel{ID: c.id, Date: c.date, Time: c.time, Title: c.title}
+}
+
+func (c *TaskCursor) PassDate() string {
+	return c.date
+}
+
+func (c *TaskCursor) PassTime() string {
+	return c.time
+}
+
+func (c *TaskCursor) PassTitle() string {
+	return c.title
+}
+
+func (c *TaskCursor) PassID() uint {
+	return c.id
+}
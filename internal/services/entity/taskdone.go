@@ -0,0 +1,30 @@
+// taskdone - describes completion of task and its state after done (/api/task/done)
+package entity
+
+import "github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+
+// TaskDone - record of history and new data of task, saved together by store
+type TaskDone struct {
+	completion model.CompletionModel
+
+	// next - task with next date, nil - task is done and deleted
+	next *model.TaskModel
+}
+
+func NewTaskDone(completion model.CompletionModel, next *model.TaskModel) *TaskDone {
+	return &TaskDone{completion: completion, next: next}
+}
+
+func (d *TaskDone) PassCompletion() model.CompletionModel {
+	return d.completion
+}
+
+// PassNext - new data of task, nil - delete task
+func (d *TaskDone) PassNext() *model.TaskModel {
+	return d.next
+}
+
+// IsLast - task is done and deleted
+func (d *TaskDone) IsLast() bool {
+	return d.next == nil
+}
@@ -0,0 +1,89 @@
+// taskfilter - describes filters and order of list of tasks
+// (/api/tasks?from=20240101&to=20240131&kind=once&overdue=true&in=title&sort=title&order=desc)
+package entity
+
+import "time"
+
+// kinds of task by repeat
+const (
+	KindRecurring = "recurring"
+	KindOnce      = "once"
+)
+
+// fields of task for search by word
+const (
+	FieldTitle   = "title"
+	FieldComment = "comment"
+)
+
+// keys of order of list, tasks with same key are ordered by id
+const (
+	// SortDate - date, time (task for whole day is first), id
+	SortDate  = "date"
+	SortTitle = "title"
+	SortID    = "id"
+)
+
+// TaskFilter - conditions combined with search of 'TaskProperty' and order of list
+// zero value - without conditions, order by date (search by word - by relevance)
+type TaskFilter struct {
+	// window of date (include), zero - without bound
+	from time.Time
+	to   time.Time
+
+	// overdue tasks - date before this day, zero - all tasks
+	before time.Time
+
+	// KindRecurring or KindOnce, empty - all tasks
+	kind string
+
+	// FieldTitle or FieldComment, empty - search by word in both
+	field string
+
+	// SortDate, SortTitle or SortID, empty - default order
+	sort string
+	desc bool
+}
+
+func NewTaskFilter(
+	from, to, before time.Time,
+	kind, field, sort string,
+	desc bool) *TaskFilter {
+	return &TaskFilter{
+		from:   from,
+		to:     to,
+		before: before,
+		kind:   kind,
+		field:  field,
+		sort:   sort,
+		desc:   desc,
+	}
+}
+
+func (f *TaskFilter) PassFrom() time.Time {
+	return f.from
+}
+
+func (f *TaskFilter) PassTo() time.Time {
+	return f.to
+}
+
+func (f *TaskFilter) PassBefore() time.Time {
+	return f.before
+}
+
+func (f *TaskFilter) PassKind() string {
+	return f.kind
+}
+
+func (f *TaskFilter) PassField() string {
+	return f.field
+}
+
+func (f *TaskFilter) PassSort() string {
+	return f.sort
+}
+
+func (f *TaskFilter) IsDesc() bool {
+	return f.desc
+}
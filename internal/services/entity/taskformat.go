@@ -0,0 +1,31 @@
+// taskformat - describes optional fields of task in response (/api/task?id=1&rrule=true&describe=en)
+package entity
+
+import "strconv"
+
+type TaskFormat struct {
+	// add repeat rule in RRULE syntax (RFC 5545) to response
+	rrule bool
+
+	// language of description of repeat rule ("en", "ru"), empty - without description
+	describe string
+}
+
+// NewTaskFormat - not valid value of param -> field is not added
+func NewTaskFormat(rrule, describe string) *TaskFormat {
+	taskFormat := &TaskFormat{describe: describe}
+	taskFormat.rrule, _ = strconv.ParseBool(rrule)
+	return taskFormat
+}
+
+func (t *TaskFormat) IsRRule() bool {
+	return t != nil && t.rrule
+}
+
+func (t *TaskFormat) IsDescribe() bool {
+	return t != nil && t.describe != ""
+}
+
+func (t *TaskFormat) PassLanguage() string {
+	return t.describe
+}
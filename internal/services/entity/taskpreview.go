@@ -0,0 +1,72 @@
+// taskpreview - describes rules for find dates of series (/api/nextdates?id=1&n=5)
+package entity
+
+import (
+	"time"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+)
+
+type TaskPreview struct {
+	// id - stored task, zero - rule from 'task'
+	id uint
+
+	// task - ad-hoc rule: Date, Repeat, Algorithm
+	task model.TaskModel
+
+	// dates not before 'now'
+	now time.Time
+
+	// end of window (include), zero - without window
+	to time.Time
+
+	// max number of dates
+	limit int
+
+	// language of description of repeat rule, empty - without description
+	describe string
+}
+
+func NewTaskPreview(
+	id uint,
+	task model.TaskModel,
+	now, to time.Time,
+	limit int,
+	describe string) *TaskPreview {
+	return &TaskPreview{
+		id:       id,
+		task:     task,
+		now:      now,
+		to:       to,
+		limit:    limit,
+		describe: describe,
+	}
+}
+
+func (t *TaskPreview) IsTask() bool {
+	return t.id != 0
+}
+
+func (t *TaskPreview) PassID() uint {
+	return t.id
+}
+
+func (t *TaskPreview) PassTask() model.TaskModel {
+	return t.task
+}
+
+func (t *TaskPreview) PassNow() time.Time {
+	return t.now
+}
+
+func (t *TaskPreview) PassTo() time.Time {
+	return t.to
+}
+
+func (t *TaskPreview) PassLimit() int {
+	return t.limit
+}
+
+func (t *TaskPreview) PassLanguage() string {
+	return t.describe
+}
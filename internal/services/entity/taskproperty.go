@@ -4,16 +4,19 @@ package entity
 import (
 	"time"
 
-	"golang.org/x/exp/rand"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
 )
 
 // dateFormatFromParam - valid format date from param (/api/tasks?search=02.01.2006)
 const dateFormatFromParam = "02.01.2006"
 
-// range of limit field ”TaskProperty.limit"
+// limit of field 'TaskProperty.limit'
 const (
-	minLimit = 10
-	maxLimit = 50
+	// DefaultTaskLimit - number of tasks on page without limit
+	DefaultTaskLimit = 50
+
+	// MaxTaskLimit - max number of tasks on page
+	MaxTaskLimit = 100
 )
 
 type TaskProperty struct {
@@ -25,6 +28,15 @@ type TaskProperty struct {
 
 	// use 'LIMIT' when searching in database
 	limit uint
+
+	// page after cursor, nil - first page
+	cursor *TaskCursor
+
+	// conditions and order of list see (taskfilter.go)
+	filter TaskFilter
+
+	// structured search instead of word see (taskquery.go), nil - plain search
+	query *TaskQuery
 }
 
 func NewTaskProperty(property string, limit uint) *TaskProperty {
@@ -34,13 +46,35 @@ func NewTaskProperty(property string, limit uint) *TaskProperty {
 	return taskProperty
 }
 
-// setLimit - check limit and if not in [10,50] -> create random limit
+// After - same property for page after 'cursor', nil - first page
+func (t *TaskProperty) After(cursor *TaskCursor) *TaskProperty {
+	t.cursor = cursor
+	return t
+}
+
+// Filter - same property with conditions and order of 'filter', nil - without conditions
+func (t *TaskProperty) Filter(filter *TaskFilter) *TaskProperty {
+	if filter != nil {
+		t.filter = *filter
+	}
+	return t
+}
+
+// Query - same property with structured search 'query' instead of word, nil - plain search
+func (t *TaskProperty) Query(query *TaskQuery) *TaskProperty {
+	if query != nil {
+		t.query = query
+		t.word = ""
+	}
+	return t
+}
+
+// setLimit - zero -> DefaultTaskLimit, more than MaxTaskLimit -> MaxTaskLimit
 func (t *TaskProperty) setLimit(limit uint) {
-	if limit < minLimit || limit > maxLimit {
-		rand.Seed(uint64(time.Now().UTC().UnixNano()))
-		limit = uint(rand.Intn(maxLimit-minLimit) + minLimit) // (40)+10 -> [10,50]
+	if limit == 0 {
+		limit = DefaultTaskLimit
 	}
-	t.limit = limit
+	t.limit = min(limit, MaxTaskLimit)
 }
 
 // parseProperty - if property not empty - create 'date' or 'word'
@@ -75,3 +109,86 @@ func (t *TaskProperty) PassWord() string {
 func (t *TaskProperty) PassLimit() uint {
 	return t.limit
 }
+
+// PassCursor - position of page, nil - first page
+func (t *TaskProperty) PassCursor() *TaskCursor {
+	return t.cursor
+}
+
+func (t *TaskProperty) PassFilter() *TaskFilter {
+	return &t.filter
+}
+
+// PassQuery - structured search, nil - plain search
+func (t *TaskProperty) PassQuery() *TaskQuery {
+	return t.query
+}
+
+// IsRelevance - search by word without key of order, most relevant tasks are first
+// relevance of task depends on all tasks (bm25 of full-text search), so order is not stable between calls:
+// list has only first page, cursor is ignored, pages of search by word - with key of order (sort=date)
+func (t *TaskProperty) IsRelevance() bool {
+	return t.IsWord() && t.filter.sort == ""
+}
+
+// SortKey - key of order of list, default - SortDate
+// order by relevance is known only by database, othercase it is SortDate
+func (t *TaskProperty) SortKey() string {
+	if t.filter.sort == "" {
+		return SortDate
+	}
+	return t.filter.sort
+}
+
+// Less - task 'a' is before task 'b' in order of list by 'SortKey' and direction
+func (t *TaskProperty) Less(a, b model.TaskModel) bool {
+	if t.filter.desc {
+		a, b = b, a
+	}
+	switch t.SortKey() {
+	case SortTitle:
+		if a.Title != b.Title {
+			return a.Title < b.Title
+		}
+	case SortDate:
+		if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		if a.Time != b.Time {
+			return a.Time < b.Time
+		}
+	}
+	return a.ID < b.ID
+}
+
+// IsAfterCursor - 'task' is on page after cursor, first page - true
+// order by relevance has no pages - true
+func (t *TaskProperty) IsAfterCursor(task model.TaskModel) bool {
+	if t.cursor == nil || t.IsRelevance() {
+		return true
+	}
+	return t.Less(t.cursor.PassTask(), task)
+}
+
+// Matches - 'task' fits date of search, conditions of filter, repeat and dates of structured search
+// search by word and words of structured search are checked by store
+func (t *TaskProperty) Matches(task model.TaskModel) bool {
+	f := t.filter
+	switch {
+	case t.query != nil && !t.query.matches(task):
+		return false
+	case t.IsDate() && task.Date != t.date.Format(model.DateFormat):
+		return false
+	case !f.from.IsZero() && task.Date < f.from.Format(model.DateFormat):
+		return false
+	case !f.to.IsZero() && task.Date > f.to.Format(model.DateFormat):
+		return false
+	case !f.before.IsZero() && task.Date >= f.before.Format(model.DateFormat):
+		return false
+	case f.kind == KindRecurring && task.Repeat == "":
+		return false
+	case f.kind == KindOnce && task.Repeat != "":
+		return false
+	}
+	return true
+}
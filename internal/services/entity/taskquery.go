@@ -0,0 +1,296 @@
+// taskquery - structured search of task list (/api/tasks?search=title:report repeat:w before:2025-06-01 -draft)
+//
+// query is list of terms separated by spaces, all terms are required:
+//
+//	word          - word in title or comment
+//	-word         - tasks without word in title and comment
+//	title:word    - word in title, -title:word - without word in title
+//	comment:word  - word in comment, -comment:word - without word in comment
+//	repeat:flag   - flag of rule (d, b, w, m, n, y, h), repeat:any - recurring tasks, repeat:none - tasks without repeat
+//	                RRULE fits flag by FREQ: d - DAILY, w - WEEKLY, m - MONTHLY, y - YEARLY
+//	before:date   - date of task before 'date', after:date - after 'date' (2006-01-02, 20060102 or 02.01.2006)
+//
+// "quoted words" are one term, "re: meeting" is not a key
+// search without keys and negation is plain word, see 'TaskProperty'
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+)
+
+// ErrEntityInvalidQuery - wrong structured search, see 'QueryError'
+var ErrEntityInvalidQuery = errors.New("invalid search query")
+
+// QueryError - wrong term of structured search with position (byte from 0) and reason
+// errors.Is(err, ErrEntityInvalidQuery) - true
+type QueryError struct {
+	Pos    int
+	Reason string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("%s at position %d: %s", ErrEntityInvalidQuery, e.Pos, e.Reason)
+}
+
+func (e *QueryError) Unwrap() error {
+	return ErrEntityInvalidQuery
+}
+
+func queryError(pos int, reason string) error {
+	return &QueryError{Pos: pos, Reason: reason}
+}
+
+// values of key 'repeat' besides flag of rule
+const (
+	RepeatAny  = "any"
+	RepeatNone = "none"
+)
+
+// repeatFlags - flags of rule of !_repeat_! (lib/nextdate/nextdate.go)
+const repeatFlags = "dbwmnyh"
+
+// repeatFreqs - FREQ of RRULE for flag of rule, other flags have no FREQ
+var repeatFreqs = map[string]string{"d": "DAILY", "w": "WEEKLY", "m": "MONTHLY", "y": "YEARLY"}
+
+// queryDateFormats - formats of date of keys 'before' and 'after'
+var queryDateFormats = []string{"2006-01-02", model.DateFormat, dateFormatFromParam}
+
+// TaskTerm - word of structured search
+type TaskTerm struct {
+	word string
+
+	// FieldTitle or FieldComment, empty - title or comment
+	field string
+
+	// negative - task must not contain word
+	negative bool
+}
+
+func (t TaskTerm) PassWord() string {
+	return t.word
+}
+
+func (t TaskTerm) PassField() string {
+	return t.field
+}
+
+func (t TaskTerm) IsNegative() bool {
+	return t.negative
+}
+
+// TaskQuery - structured search compiled by 'ParseTaskQuery', conditions of 'FindTaskList' with 'TaskFilter'
+type TaskQuery struct {
+	terms []TaskTerm
+
+	// flag of rule, RepeatAny or RepeatNone, empty - all tasks
+	repeat string
+
+	// date of task is after 'after' and before 'before', zero - without bound
+	after  time.Time
+	before time.Time
+}
+
+// queryKeys - keys of structured search
+var queryKeys = []string{FieldTitle, FieldComment, "repeat", "before", "after"}
+
+// ParseTaskQuery - compile structured search, first wrong term -> *QueryError
+// plain word or date of search (without keys and negation) -> nil, nil, it is never error
+func ParseTaskQuery(search string) (*TaskQuery, error) {
+	if !slices.ContainsFunc(strings.Fields(search), isStructured) {
+		return nil, nil
+	}
+	terms, err := splitQuery(search)
+	if err != nil {
+		return nil, err
+	}
+	query := &TaskQuery{}
+	for _, term := range terms {
+		text, pos := term.text, term.pos
+		negative := len(text) > 1 && text[0] == '-'
+		if negative {
+			text, pos = text[1:], pos+1
+		}
+		key, value, ok := cutKey(text)
+		if !ok {
+			word := unquote(text)
+			if word == "" {
+				return nil, queryError(pos, "empty term")
+			}
+			query.terms = append(query.terms, TaskTerm{word: word, negative: negative})
+			continue
+		}
+		if !slices.Contains(queryKeys, key) {
+			return nil, queryError(pos, fmt.Sprintf("unknown key '%s', use quotes for word with ':'", key))
+		}
+		valuePos := pos + len(key) + 1
+		value = unquote(value)
+		if value == "" {
+			return nil, queryError(valuePos, fmt.Sprintf("empty value of key '%s'", key))
+		}
+		if negative && key != FieldTitle && key != FieldComment {
+			return nil, queryError(term.pos, fmt.Sprintf("key '%s' can't be negative", key))
+		}
+		switch key {
+		case FieldTitle, FieldComment:
+			query.terms = append(query.terms, TaskTerm{word: value, field: key, negative: negative})
+		case "repeat":
+			if query.repeat != "" {
+				return nil, queryError(pos, "key 'repeat' is used twice")
+			}
+			if value != RepeatAny && value != RepeatNone && (len(value) != 1 || !strings.Contains(repeatFlags, value)) {
+				return nil, queryError(valuePos, fmt.Sprintf("unknown repeat '%s'", value))
+			}
+			query.repeat = value
+		case "before", "after":
+			date, ok := parseQueryDate(value)
+			if !ok {
+				return nil, queryError(valuePos, "invalid date")
+			}
+			// several bounds -> narrowest window
+			if key == "before" && (query.before.IsZero() || date.Before(query.before)) {
+				query.before = date
+			} else if key == "after" && date.After(query.after) {
+				query.after = date
+			}
+		}
+	}
+	return query, nil
+}
+
+// isStructured - term of search is negation or known key
+func isStructured(term string) bool {
+	if len(term) > 1 && term[0] == '-' {
+		return true
+	}
+	key, _, ok := cutKey(term)
+	return ok && slices.Contains(queryKeys, key)
+}
+
+// queryTerm - part of search between spaces outside quotes
+type queryTerm struct {
+	text string
+	pos  int
+}
+
+// splitQuery - terms of search, quote without pair -> *QueryError
+func splitQuery(search string) ([]queryTerm, error) {
+	var terms []queryTerm
+	start, quote := -1, -1
+	for i := 0; i <= len(search); i++ {
+		end := i == len(search)
+		if !end && search[i] == '"' {
+			if quote == -1 {
+				quote = i
+			} else {
+				quote = -1
+			}
+		}
+		if !end && (quote != -1 || (search[i] != ' ' && search[i] != '\t')) {
+			if start == -1 {
+				start = i
+			}
+			continue
+		}
+		if end && quote != -1 {
+			return nil, queryError(quote, "quote without pair")
+		}
+		if start != -1 {
+			terms = append(terms, queryTerm{text: search[start:i], pos: start})
+			start = -1
+		}
+	}
+	return terms, nil
+}
+
+// cutKey - "key:value" where key is ASCII letters and key is not inside quotes
+func cutKey(text string) (string, string, bool) {
+	key, value, ok := strings.Cut(text, ":")
+	if !ok || key == "" {
+		return "", "", false
+	}
+	for i := 0; i < len(key); i++ {
+		if c := key[i]; (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			return "", "", false
+		}
+	}
+	return key, value, true
+}
+
+// unquote - term without quotes, "buy milk" -> buy milk
+func unquote(text string) string {
+	return strings.ReplaceAll(text, `"`, "")
+}
+
+func parseQueryDate(value string) (time.Time, bool) {
+	for _, layout := range queryDateFormats {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (q *TaskQuery) PassTerms() []TaskTerm {
+	return q.terms
+}
+
+func (q *TaskQuery) PassRepeat() string {
+	return q.repeat
+}
+
+// PassFreq - FREQ of RRULE for flag of key 'repeat', no FREQ -> empty
+func (q *TaskQuery) PassFreq() string {
+	return repeatFreqs[q.repeat]
+}
+
+func (q *TaskQuery) PassAfter() time.Time {
+	return q.after
+}
+
+func (q *TaskQuery) PassBefore() time.Time {
+	return q.before
+}
+
+// MatchesTerms - 'task' fits all terms, 'contains' - search of word in text by rules of store
+func (q *TaskQuery) MatchesTerms(task model.TaskModel, contains func(text, word string) bool) bool {
+	for _, term := range q.terms {
+		found := (term.field != FieldComment && contains(task.Title, term.word)) ||
+			(term.field != FieldTitle && contains(task.Comment, term.word))
+		if found == term.negative {
+			return false
+		}
+	}
+	return true
+}
+
+// matches - 'task' fits repeat and dates of query
+func (q *TaskQuery) matches(task model.TaskModel) bool {
+	switch {
+	case q.repeat == RepeatAny && task.Repeat == "":
+		return false
+	case q.repeat == RepeatNone && task.Repeat != "":
+		return false
+	case len(q.repeat) == 1 && !q.matchesRepeat(task.Repeat):
+		return false
+	case !q.after.IsZero() && task.Date <= q.after.Format(model.DateFormat):
+		return false
+	case !q.before.IsZero() && task.Date >= q.before.Format(model.DateFormat):
+		return false
+	}
+	return true
+}
+
+// matchesRepeat - rule 'repeat' starts with flag of query or RRULE has FREQ of flag
+func (q *TaskQuery) matchesRepeat(repeat string) bool {
+	if strings.HasPrefix(repeat, q.repeat) {
+		return true
+	}
+	freq := q.PassFreq()
+	return freq != "" && strings.Contains(strings.ToUpper(repeat), "FREQ="+freq)
+}
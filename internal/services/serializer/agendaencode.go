@@ -0,0 +1,41 @@
+// agendaencode - rules for encode agenda of tasks by days
+package serializer
+
+import "github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+
+// AgendaResponse - days of agenda for writing to http.ResponseWriter
+type AgendaResponse struct {
+	Days []AgendaDayResponse `json:"days"`
+}
+
+// AgendaDayResponse - occurrences of tasks in one day, day without tasks - empty list
+type AgendaDayResponse struct {
+	Date  string         `json:"date"`
+	Tasks []TaskResponse `json:"tasks"`
+}
+
+type AgendaEncode struct {
+	// Dates - all days of agenda in order
+	Dates []string
+
+	// Tasks - occurrences of tasks by day, date of task is date of occurrence
+	Tasks map[string][]model.TaskModel
+
+	// Stored - stored date of task by ID, occurrence at other date is projected
+	Stored map[uint]string
+}
+
+// create a AgendaResponse, occurrence by rule is marked "projected":true
+func (ae AgendaEncode) Response() *AgendaResponse {
+	days := make([]AgendaDayResponse, 0, len(ae.Dates))
+	for _, date := range ae.Dates {
+		day := AgendaDayResponse{Date: date, Tasks: make([]TaskResponse, 0, len(ae.Tasks[date]))}
+		for _, task := range ae.Tasks[date] {
+			taskResponse := TaskEncode{TaskModel: task}.Response()
+			taskResponse.Projected = ae.Stored[task.ID] != date
+			day.Tasks = append(day.Tasks, *taskResponse)
+		}
+		days = append(days, day)
+	}
+	return &AgendaResponse{Days: days}
+}
@@ -0,0 +1,32 @@
+// clockencode - rules for encode state of debug clock
+package serializer
+
+import (
+	"time"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+)
+
+// ClockResponse - "now" of server for writing to http.ResponseWriter
+type ClockResponse struct {
+	// Now - date and time in time zone of request: "20060102 15:04"
+	Now    string `json:"now"`
+	Frozen bool   `json:"frozen"`
+
+	// Offset - from real time in format of time.Duration, without offset - "0s"
+	Offset string `json:"offset"`
+}
+
+type ClockEncode struct {
+	Now    time.Time
+	Frozen bool
+	Offset time.Duration
+}
+
+func (ce ClockEncode) Response() *ClockResponse {
+	return &ClockResponse{
+		Now:    ce.Now.Format(model.DateFormat + " " + model.TimeFormat),
+		Frozen: ce.Frozen,
+		Offset: ce.Offset.String(),
+	}
+}
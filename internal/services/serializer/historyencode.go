@@ -0,0 +1,47 @@
+// historyencode - rules for encode history of completions of task (/api/task/history)
+package serializer
+
+import (
+	"strconv"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+)
+
+// CompletionResponse - one completion of task
+type CompletionResponse struct {
+	ID   string `json:"id"`
+	Date string `json:"date"`
+	Time string `json:"time,omitempty"` // empty - task for whole day
+
+	// DoneAt - time of completion in UTC (RFC3339)
+	DoneAt string `json:"done_at"`
+	Note   string `json:"note,omitempty"`
+}
+
+// HistoryResponse - completions of task for writing to http.ResponseWriter, first completion is first
+type HistoryResponse struct {
+	TaskID      string               `json:"task_id"`
+	Completions []CompletionResponse `json:"completions"`
+}
+
+type HistoryEncode struct {
+	TaskID      uint
+	Completions []model.CompletionModel
+}
+
+func (he HistoryEncode) Response() *HistoryResponse {
+	completions := make([]CompletionResponse, 0, len(he.Completions))
+	for _, completion := range he.Completions {
+		completions = append(completions, CompletionResponse{
+			ID:     strconv.FormatUint(uint64(completion.ID), 10),
+			Date:   completion.Date,
+			Time:   completion.Time,
+			DoneAt: completion.DoneAt,
+			Note:   completion.Note,
+		})
+	}
+	return &HistoryResponse{
+		TaskID:      strconv.FormatUint(uint64(he.TaskID), 10),
+		Completions: completions,
+	}
+}
@@ -0,0 +1,32 @@
+// previewencode - rules for encode dates of series
+package serializer
+
+import "strconv"
+
+// TaskPreviewResponse - dates of series for writing to http.ResponseWriter
+type TaskPreviewResponse struct {
+	// ID - stored task, ad-hoc rule - empty
+	ID    string   `json:"id,omitempty"`
+	Dates []string `json:"dates"`
+
+	// Description - repeat rule as sentence, only by param "describe"
+	Description string `json:"description,omitempty"`
+}
+
+type TaskPreviewEncode struct {
+	ID          uint
+	Dates       []string
+	Description string
+}
+
+// create a TaskPreviewResponse, empty list of dates is written as []
+func (tpe TaskPreviewEncode) Response() *TaskPreviewResponse {
+	taskPreviewResponse := TaskPreviewResponse{Dates: tpe.Dates, Description: tpe.Description}
+	if tpe.ID != 0 {
+		taskPreviewResponse.ID = strconv.FormatUint(uint64(tpe.ID), 10)
+	}
+	if taskPreviewResponse.Dates == nil {
+		taskPreviewResponse.Dates = []string{}
+	}
+	return &taskPreviewResponse
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/config"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/lib/jwtsign"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/services/entity"
 )
 
 func TestTokenEncode_Response(t *testing.T) {
@@ -46,6 +47,27 @@ func TestTaskEncode_Response(t *testing.T) {
 	}, *response)
 }
 
+func TestTaskEncode_Response_RRule(t *testing.T) {
+	serialize := TaskEncode{TaskModel: newTask(), Format: entity.NewTaskFormat("true", "")}
+	response := serialize.Response()
+	assert.Equal(t, "FREQ=DAILY", response.RRule)
+
+	serialize.Repeat = "b 1"
+	response = serialize.Response()
+	assert.Empty(t, response.RRule, "rule without RRULE form")
+}
+
+func TestTaskEncode_Response_Describe(t *testing.T) {
+	serialize := TaskEncode{TaskModel: newTask(), Format: entity.NewTaskFormat("", "ru")}
+	response := serialize.Response()
+	assert.Equal(t, "каждый день", response.Description)
+	assert.Empty(t, response.RRule)
+
+	serialize.Format = entity.NewTaskFormat("", "de")
+	response = serialize.Response()
+	assert.Empty(t, response.Description, "unknown language")
+}
+
 func TestTaskListEncode_Response(t *testing.T) {
 	serialize := TaskListEncode{Tasks: []model.TaskModel{newTask(), newTask(), newTask()}}
 	response := serialize.Response()
@@ -75,3 +97,42 @@ func TestTaskListEncode_Response(t *testing.T) {
 		},
 	}, *response)
 }
+
+func TestAgendaEncode_Response(t *testing.T) {
+	task := newTask()
+	occurrence := newTask()
+	occurrence.Date = "20251004"
+	serialize := AgendaEncode{
+		Dates:  []string{"20251003", "20251004", "20251005"},
+		Tasks:  map[string][]model.TaskModel{"20251003": {task}, "20251004": {occurrence}},
+		Stored: map[uint]string{123: "20251003"},
+	}
+	response := serialize.Response()
+	assert.Len(t, response.Days, 3)
+	assert.Equal(t, "20251003", response.Days[0].Date)
+	assert.False(t, response.Days[0].Tasks[0].Projected, "stored date of task")
+	assert.Equal(t, "20251004", response.Days[1].Tasks[0].Date)
+	assert.True(t, response.Days[1].Tasks[0].Projected, "occurrence by rule")
+	assert.NotNil(t, response.Days[2].Tasks, "empty day is written as []")
+	assert.Empty(t, response.Days[2].Tasks)
+}
+
+func TestHistoryEncode_Response(t *testing.T) {
+	serialize := HistoryEncode{
+		TaskID: 123,
+		Completions: []model.CompletionModel{
+			{ID: 1, TaskID: 123, Date: "20251003", DoneAt: "2025-10-03T18:00:00Z", Note: "done"},
+			{ID: 4, TaskID: 123, Date: "20251004", Time: "09:00", DoneAt: "2025-10-04T09:30:00Z"},
+		},
+	}
+	response := serialize.Response()
+	assert.Equal(t, "123", response.TaskID)
+	assert.Equal(t, []CompletionResponse{
+		{ID: "1", Date: "20251003", DoneAt: "2025-10-03T18:00:00Z", Note: "done"},
+		{ID: "4", Date: "20251004", Time: "09:00", DoneAt: "2025-10-04T09:30:00Z"},
+	}, response.Completions)
+
+	response = HistoryEncode{TaskID: 7}.Response()
+	assert.NotNil(t, response.Completions, "empty history is written as []")
+	assert.Empty(t, response.Completions)
+}
@@ -4,20 +4,44 @@ package serializer
 import (
 	"strconv"
 
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/lib/nextdate"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/services/entity"
 )
 
 // TaskResponse - task properties for writing to http.ResponseWriter
 type TaskResponse struct {
 	ID      string `json:"id"` // need "-" in my opinion
 	Date    string `json:"date"`
+	Time    string `json:"time,omitempty"` // empty - task for whole day
 	Title   string `json:"title"`
 	Comment string `json:"comment"` // need omitempty
 	Repeat  string `json:"repeat"`  // need omitempty
+
+	// Algorithm - name of algorithm for repeat, task without repeat - empty
+	Algorithm string `json:"algorithm,omitempty"`
+
+	// Mode - mode of repeat, task without repeat - empty
+	Mode string `json:"mode,omitempty"`
+
+	// Exdates - exception dates of series, without exceptions - empty
+	Exdates []string `json:"exdates,omitempty"`
+
+	// RRule - normalized repeat rule in RRULE syntax, only by 'entity.TaskFormat'
+	RRule string `json:"rrule,omitempty"`
+
+	// Description - repeat rule as sentence in language of 'entity.TaskFormat'
+	Description string `json:"description,omitempty"`
+
+	// Projected - occurrence of recurring task found by rule, not stored date of task
+	Projected bool `json:"projected,omitempty"`
 }
 
 type TaskEncode struct {
 	model.TaskModel
+
+	// Format - optional fields, can be nil
+	Format *entity.TaskFormat
 }
 
 // create a TaskResponse according to given rules
@@ -25,9 +49,22 @@ func (te TaskEncode) Response() *TaskResponse {
 	taskResponse := TaskResponse{
 		ID:      strconv.FormatUint(uint64(te.ID), 10),
 		Date:    te.Date,
+		Time:    te.Time,
 		Title:   te.Title,
 		Comment: te.Comment,
 		Repeat:  te.Repeat,
+
+		Algorithm: te.Algorithm,
+		Mode:      te.Mode,
+		Exdates:   nextdate.SplitExdates(te.Exdates),
+	}
+	if te.Format.IsRRule() && te.Repeat != "" {
+		// rule without RRULE form -> field is skipped
+		taskResponse.RRule, _ = nextdate.ToRRule(te.Repeat)
+	}
+	if te.Format.IsDescribe() && te.Repeat != "" {
+		// unknown language -> field is skipped
+		taskResponse.Description, _ = nextdate.Describe(te.Repeat, te.Format.PassLanguage())
 	}
 	return &taskResponse
 }
@@ -51,17 +88,28 @@ func (tIDe TaskIDEncode) Response() *TaskIDResponse {
 
 type TaslListResponse struct {
 	TasksResp []TaskResponse `json:"tasks"`
+
+	// NextCursor - param 'cursor' for next page, empty - last page
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 type TaskListEncode struct {
 	Tasks []model.TaskModel
+
+	// Projected - ID of tasks with projected occurrence, can be nil
+	Projected map[uint]bool
+
+	// NextCursor - encoded cursor of next page (entity/taskcursor.go), empty - last page
+	NextCursor string
 }
 
 // create a 'taskResponse' list
 func (tle TaskListEncode) Response() *TaslListResponse {
 	arrTaskResponse := make([]TaskResponse, 0, len(tle.Tasks))
 	for _, task := range tle.Tasks {
-		arrTaskResponse = append(arrTaskResponse, *TaskEncode{task}.Response())
+		taskResponse := TaskEncode{TaskModel: task}.Response()
+		taskResponse.Projected = tle.Projected[task.ID]
+		arrTaskResponse = append(arrTaskResponse, *taskResponse)
 	}
-	return &TaslListResponse{TasksResp: arrTaskResponse}
+	return &TaslListResponse{TasksResp: arrTaskResponse, NextCursor: tle.NextCursor}
 }
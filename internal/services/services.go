@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/services/entity"
@@ -29,7 +30,8 @@ type (
 	TaskReadCase interface {
 		ReadTask(
 			ctx context.Context,
-			id uint) (*serializer.TaskResponse, error)
+			id uint,
+			format *entity.TaskFormat) (*serializer.TaskResponse, error)
 		ReadTaskList(
 			ctx context.Context,
 			property *entity.TaskProperty) (*serializer.TaslListResponse, error)
@@ -45,9 +47,51 @@ type (
 		DeleteTask(ctx context.Context, id uint) error
 	}
 
-	// TaskDoneCase - logic for task marked Done
+	// TaskDoneCase - logic for task marked Done, 'note' - optional note of completion
 	TaskDoneCase interface {
-		DoneTask(ctx context.Context, id uint) error
+		DoneTask(ctx context.Context, id uint, note string) error
+	}
+
+	// TaskHistoryCase - logic of read completions of task
+	TaskHistoryCase interface {
+		ReadTaskHistory(ctx context.Context, id uint) (*serializer.HistoryResponse, error)
+	}
+
+	// TaskAlgorithmCase - name of default algorithm of repeat from config (ALGORITHM_TASK_DATE)
+	TaskAlgorithmCase interface {
+		DefaultAlgorithm() string
+	}
+
+	// TaskSkipCase - logic of skip current occurrence of recurring task
+	TaskSkipCase interface {
+		SkipTask(ctx context.Context, id uint) error
+	}
+
+	// TaskPreviewCase - logic of dates of series for stored task or ad-hoc rule
+	TaskPreviewCase interface {
+		PreviewTask(
+			ctx context.Context,
+			preview *entity.TaskPreview) (*serializer.TaskPreviewResponse, error)
+	}
+
+	// TaskAgendaCase - logic of occurrences of tasks by days of window
+	TaskAgendaCase interface {
+		AgendaTask(
+			ctx context.Context,
+			agenda *entity.TaskAgenda) (*serializer.AgendaResponse, error)
+	}
+
+	// ClockCase - "now" of application in time zone of request
+	ClockCase interface {
+		Now(ctx context.Context) time.Time
+	}
+
+	// ClockDebugCase - logic of debug clock: read, freeze, move or reset "now" of server
+	ClockDebugCase interface {
+		ReadClock(ctx context.Context) (*serializer.ClockResponse, error)
+		ChangeClock(
+			ctx context.Context,
+			setting *entity.ClockSetting) (*serializer.ClockResponse, error)
 	}
 
 	// LoginValidPasswordCase - logic of login fro application
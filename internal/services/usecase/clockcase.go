@@ -0,0 +1,64 @@
+// clockcase - logic of "now" of application and debug clock
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/lib/clock"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/lib/timezone"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/services"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/services/entity"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/services/serializer"
+)
+
+// ErrCaseClockNotDebug - clock of application can't be changed (TODO_DEBUG_CLOCK is off)
+var ErrCaseClockNotDebug = errors.New("debug clock is off")
+
+type ClockService interface {
+	services.ClockCase
+	services.ClockDebugCase
+}
+
+type clockService struct {
+	clock clock.Clock
+}
+
+// NewClockService - 'clk' must be the same as in 'NewTaskService'
+func NewClockService(clk clock.Clock) ClockService {
+	return clockService{clock: clk}
+}
+
+// Now - time of clock in time zone of request
+func (c clockService) Now(ctx context.Context) time.Time {
+	return timezone.In(ctx, c.clock.Now())
+}
+
+// ReadClock - "now" of application and state of debug clock
+func (c clockService) ReadClock(ctx context.Context) (*serializer.ClockResponse, error) {
+	serialize := serializer.ClockEncode{Now: c.Now(ctx)}
+	if debug, ok := c.clock.(*clock.Debug); ok {
+		serialize.Frozen, serialize.Offset = debug.State()
+	}
+	return serialize.Response(), nil
+}
+
+// ChangeClock - rules:
+// 1. clock is not debug -> ErrCaseClockNotDebug
+// 2. freeze is set -> freeze clock
+// 3. othercase move clock by offset (zero offset - reset)
+func (c clockService) ChangeClock(
+	ctx context.Context,
+	setting *entity.ClockSetting) (*serializer.ClockResponse, error) {
+	debug, ok := c.clock.(*clock.Debug)
+	if !ok {
+		return nil, ErrCaseClockNotDebug
+	}
+	if setting.IsFreeze() {
+		debug.Freeze(setting.PassFreeze())
+	} else {
+		debug.Shift(setting.PassOffset())
+	}
+	return c.ReadClock(ctx)
+}
@@ -4,11 +4,15 @@ package usecase
 import (
 	"context"
 	"errors"
+	"slices"
+	"sort"
 	"time"
 
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/config"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/database"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/lib/clock"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/lib/nextdate"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/lib/timezone"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/services"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/services/entity"
@@ -28,6 +32,9 @@ var (
 
 	// ErrCaselAlgorithmNextDateIsNULL - nextDate is nil in 'TaskService'
 	ErrCaselAlgorithmNextDateIsNULL = errors.New("algorithm not selected")
+
+	// ErrCaseTaskNotRecurring - skip of task without repeat
+	ErrCaseTaskNotRecurring = errors.New("task is not recurring")
 )
 
 // contain all business logic of task
@@ -37,6 +44,11 @@ type TaskService interface {
 	services.TaskUpdateCase
 	services.TaskDeleteCase
 	services.TaskDoneCase
+	services.TaskSkipCase
+	services.TaskPreviewCase
+	services.TaskAgendaCase
+	services.TaskHistoryCase
+	services.TaskAlgorithmCase
 }
 
 // multiTask - contain all TaskModel interfaces
@@ -45,41 +57,89 @@ type MultiTask interface {
 	model.TaskRead
 	model.TaskUpdate
 	model.TaskDelete
+	model.TaskComplete
+	model.TaskHistory
 }
 
 type taskService struct {
 	// taskRepo - work with store
 	taskRepo MultiTask
 
-	// nextDate - algorithm for create next date to task
-	nextDate nextdate.NextDateFunc
+	// algorithm - name of default algorithm for create next date to task
+	algorithm string
+
+	// clock - source of "now" for dates of task (lib/clock/clock.go)
+	clock clock.Clock
 }
 
-func NewTaskService(cfg *config.Config, store MultiTask) (TaskService, error) {
-	nextDate, err := setNextDate(cfg.TaskNextDate)
-	if err != nil {
+func NewTaskService(cfg *config.Config, store MultiTask, clk clock.Clock) (TaskService, error) {
+	if _, err := setNextDate(cfg.TaskNextDate); err != nil {
 		return nil, err
 	}
 	return taskService{
-		taskRepo: store,
-		nextDate: nextDate,
+		taskRepo:  store,
+		algorithm: cfg.TaskNextDate,
+		clock:     clk,
 	}, nil
 }
 
-// return - algotithm by name
+// DefaultAlgorithm - name of algorithm for repeat without algorithm and not in RRULE syntax,
+// decoders check repeat by same algorithm as 'taskAlgorithm'
+func (ts taskService) DefaultAlgorithm() string {
+	return ts.algorithm
+}
+
+// now - time of clock in time zone of request (lib/timezone/timezone.go)
+func (ts taskService) now(ctx context.Context) time.Time {
+	return timezone.In(ctx, ts.clock.Now())
+}
+
+// return - algotithm by name from registry (lib/nextdate/registry.go)
 func setNextDate(name string) (nextdate.NextDateFunc, error) {
-	if name == "nextdate" {
-		return nextdate.NextDate, nil
+	nextDate, err := nextdate.Lookup(name)
+	if err != nil {
+		return nil, ErrCaselAlgorithmNextDateIsNULL
 	}
-	return nil, ErrCaselAlgorithmNextDateIsNULL
+	return nextDate, nil
+}
+
+// taskAlgorithm - set name of algorithm and mode of repeat to task and return algorithm
+//
+// 1. repeat is empty -> algorithm, mode and exception dates not needed, they are empty
+// 2. name from task
+// 3. name by syntax of repeat (RRULE) or default from config
+// 4. mode from task or "schedule"
+// 5. algorithm skips exception dates of task (lib/nextdate/exdate.go)
+func (ts taskService) taskAlgorithm(task *model.TaskModel) (nextdate.NextDateFunc, error) {
+	if task.Repeat == "" {
+		task.Algorithm = ""
+		task.Mode = ""
+		task.Exdates = ""
+		return nil, nil
+	}
+	if task.Algorithm == "" {
+		task.Algorithm = nextdate.DetectAlgorithm(task.Repeat, ts.algorithm)
+	}
+	if task.Mode == "" {
+		task.Mode = nextdate.ModeSchedule
+	}
+	nextDate, err := nextdate.Lookup(task.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	return nextdate.Except(nextDate, task.Exdates), nil
 }
 
 // CreateTask - member of taskService
 //
 // 1. if create with ID -> check in database 'FindOneTask' -> ID exist -> error
-// 2. find execute date see below 'executeDate(date, repeat string) (string, error)'
-// 3. add in database task and get ID
-// 4. return TaskIDResponse
+// 2. set algorithm of task see below 'taskAlgorithm(task *model.TaskModel)'
+// 3. find time of task with rule 'h' see below 'executeTime(now, date, clock, repeat, algorithm string) (string, string, error)'
+// 4. find execute date see below 'executeDate(now, date, algorithm, repeat string, nextDateFunc) (string, error)'
+// 'now' - time of clock in time zone of request see below 'now(ctx)'
+// 5. date is exception date -> next occurrence see below 'executeExdate'
+// 6. add in database task and get ID
+// 7. return TaskIDResponse
 func (ts taskService) CreateTask(
 	ctx context.Context,
 	task model.TaskModel) (*serializer.TaskIDResponse, error) {
@@ -92,15 +152,29 @@ func (ts taskService) CreateTask(
 			return nil, services.ErrServicesInternalError
 		}
 	}
-	date, err := ts.executeDate(task.Date, task.Repeat)
+	nextDate, err := ts.taskAlgorithm(&task)
+	if err != nil {
+		return nil, err
+	}
+	now := ts.now(ctx)
+	date, clock, err := ts.executeTime(now, task.Date, task.Time, task.Repeat, task.Algorithm)
+	if err == nil {
+		date, err = ts.executeDate(now, date, task.Algorithm, task.Repeat, nextDate)
+	}
+	if err == nil {
+		date, clock, err = ts.executeExdate(task.Date, date, clock, task, nextDate)
+	}
 	if err != nil {
 		if errors.Is(err, nextdate.ErrNextDateInvalidDate) ||
-			errors.Is(err, nextdate.ErrNextDateWrongRepeat) {
+			errors.Is(err, nextdate.ErrNextDateInvalidTime) ||
+			errors.Is(err, nextdate.ErrNextDateWrongRepeat) ||
+			errors.Is(err, nextdate.ErrNextDateSeriesEnded) {
 			return nil, err
 		}
 		return nil, services.ErrServicesInternalError
 	}
 	task.Date = date
+	task.Time = clock
 	id, err := ts.taskRepo.SaveOneTask(ctx, task)
 	if err != nil {
 		return nil, services.ErrServicesInternalError
@@ -120,8 +194,15 @@ func (ts taskService) CreateTask(
 // 3. return td.Date
 //
 // 'nextDate' - selected algorithm - execute if 'date' less 'now' and 't.Repeat' not empty
-func (ts taskService) executeDate(date, repeat string) (string, error) {
-	now := common.ReduceTimeToDay(time.Now())
+// series without next date is valid only if 'date' is not less 'now' ('date' - last occurrence)
+// rule work only with working days -> 'date' not less 'now' is moved to workday (holiday calendar),
+// algorithm added by 'Register' has no working days
+func (ts taskService) executeDate(
+	now time.Time,
+	date, algorithm, repeat string,
+	nextDateFunc nextdate.NextDateFunc) (string, error) {
+	// day of 'now' in time zone of request, date of task has no time zone
+	now = common.ReduceTimeToDayUTC(now)
 	if date == "" {
 		date = now.Format(model.DateFormat)
 	}
@@ -130,7 +211,7 @@ func (ts taskService) executeDate(date, repeat string) (string, error) {
 		return "", nextdate.ErrNextDateInvalidDate
 	}
 	dateAfterNow := false
-	if dateToTime.UTC().Before(now.UTC()) {
+	if dateToTime.Before(now) {
 		dateAfterNow = true
 	}
 	if repeat == "" {
@@ -139,24 +220,87 @@ func (ts taskService) executeDate(date, repeat string) (string, error) {
 		}
 		return date, nil
 	}
-	nextDate, err := ts.nextDate(now, date, repeat)
-	if err != nil {
+	nextDate, err := nextDateFunc(now, date, repeat)
+	if err != nil && !(errors.Is(err, nextdate.ErrNextDateSeriesEnded) && !dateAfterNow) {
 		return "", err
 	}
 	if dateAfterNow {
-		date = nextDate
+		return nextDate, nil
+	}
+	if !nextdate.IsBuiltIn(algorithm) {
+		return date, nil
 	}
-	return date, nil
+	workday, err := nextdate.WorkdayDate(date, repeat)
+	if err != nil {
+		return "", err
+	}
+	// workday moved back before 'now'
+	if workday < now.Format(model.DateFormat) {
+		if nextDate == "" {
+			return date, nil
+		}
+		return nextDate, nil
+	}
+	return workday, nil
+}
+
+// isHourly - rule 'h' of custom grammar, date and time of task are found together (lib/nextdate/hourly.go)
+func isHourly(algorithm, repeat string) bool {
+	return algorithm == nextdate.AlgorithmNextDate && nextdate.IsHourly(repeat)
+}
+
+// executeTime - metod of taskService find time of task where task create or update
+//
+// 1. rule without hours -> 'clock' without change
+// 2. 'date' is not specified, 'now' is taken
+// 3. first occurrence of rule 'h' not before 'now': 'date' and 'clock' or next occurrence after 'now'
+// 'clock' is not specified -> start of window of rule or 00:00
+func (ts taskService) executeTime(now time.Time, date, clock, repeat, algorithm string) (string, string, error) {
+	if !isHourly(algorithm, repeat) {
+		return date, clock, nil
+	}
+	if date == "" {
+		date = common.ReduceTimeToDay(now).Format(model.DateFormat)
+	}
+	return nextdate.StartTime(now, date, clock, repeat)
+}
+
+// executeExdate - metod of taskService, date of task is exception date -> next occurrence of series
+//
+// rule 'h' -> first occurrence of next day without exception (lib/nextdate/exdate.go)
+// 'dstart' - start of series from which 'date' is found, empty -> 'date'
+// occurrence at exception date is not done -> end condition "count" is not checked for occurrence after it
+// 'nextDate' - algorithm with exception dates see 'taskAlgorithm'
+func (ts taskService) executeExdate(
+	dstart, date, clock string,
+	task model.TaskModel,
+	nextDateFunc nextdate.NextDateFunc) (string, string, error) {
+	if task.Repeat == "" || task.Exdates == "" {
+		return date, clock, nil
+	}
+	if isHourly(task.Algorithm, task.Repeat) {
+		return nextdate.ExceptTime(date, clock, task.Repeat, task.Exdates)
+	}
+	if dstart == "" {
+		dstart = date
+	}
+	repeat, err := nextdate.WithoutCountOf(task.Algorithm, task.Repeat)
+	if err != nil {
+		return "", "", err
+	}
+	date, err = nextdate.ExceptDate(nextDateFunc, dstart, date, repeat, task.Exdates)
+	return date, clock, err
 }
 
 // ReadTask - member of taskService
 //
 // 1. check ID by zero
 // 2. find task by ID
-// 3. create TaskResponse
+// 3. create TaskResponse with optional fields by 'format'
 func (ts taskService) ReadTask(
 	ctx context.Context,
-	id uint) (*serializer.TaskResponse, error) {
+	id uint,
+	format *entity.TaskFormat) (*serializer.TaskResponse, error) {
 	if id == 0 {
 		return nil, ErrCaseTaskZeroID
 	}
@@ -167,29 +311,46 @@ func (ts taskService) ReadTask(
 		}
 		return nil, services.ErrServicesInternalError
 	}
-	serialize := serializer.TaskEncode{TaskModel: task}
+	serialize := serializer.TaskEncode{TaskModel: task, Format: format}
 	return serialize.Response(), nil
 }
 
 // UpdateTask - member of taskService
 //
 // 1. check ID by zero
-// 2. find execute date use - 'executeDate'
-// 3. update task by ID in database
+// 2. set algorithm of task use - 'taskAlgorithm'
+// 3. find time of task with rule 'h' use - 'executeTime'
+// 4. find execute date use - 'executeDate'
+// 5. date is exception date -> next occurrence use - 'executeExdate'
+// 6. update task by ID in database
 func (ts taskService) UpdateTask(ctx context.Context, task model.TaskModel) error {
 	id := task.ID
 	if id == 0 {
 		return ErrCaseTaskZeroID
 	}
-	date, err := ts.executeDate(task.Date, task.Repeat)
+	nextDate, err := ts.taskAlgorithm(&task)
+	if err != nil {
+		return err
+	}
+	now := ts.now(ctx)
+	date, clock, err := ts.executeTime(now, task.Date, task.Time, task.Repeat, task.Algorithm)
+	if err == nil {
+		date, err = ts.executeDate(now, date, task.Algorithm, task.Repeat, nextDate)
+	}
+	if err == nil {
+		date, clock, err = ts.executeExdate(task.Date, date, clock, task, nextDate)
+	}
 	if err != nil {
 		if errors.Is(err, nextdate.ErrNextDateInvalidDate) ||
-			errors.Is(err, nextdate.ErrNextDateWrongRepeat) {
+			errors.Is(err, nextdate.ErrNextDateInvalidTime) ||
+			errors.Is(err, nextdate.ErrNextDateWrongRepeat) ||
+			errors.Is(err, nextdate.ErrNextDateSeriesEnded) {
 			return err
 		}
 		return services.ErrServicesInternalError
 	}
 	task.Date = date
+	task.Time = clock
 	if err := ts.taskRepo.NewDataTask(ctx, task); err != nil {
 		if errors.Is(err, database.ErrDataBaseNotFound) {
 			return ErrCaseTaskNotFound
@@ -222,11 +383,16 @@ func (ts taskService) DeleteTask(ctx context.Context, id uint) error {
 // 2. find task by ID
 // 3. processing the task
 //
-//	3.1 find execute date see bellow 'updateDateAfterDone(date, repeat string) (string, error)'
+//	3.1 find execute date by algorithm and mode of task see bellow 'updateDateAfterDone(now, date, repeat, mode string, nextDateFunc) (string, error)'
+//	    rule 'h' - date and time see bellow 'updateTimeAfterDone(now, date, clock, repeat, mode string) (string, string, error)'
+//	    'now' - time of clock in time zone of request see 'now(ctx)'
+//	    next occurrence at exception date -> occurrence after it see 'executeExdate'
 //
 // 3.2.1 task done -> delete task from database by ID
-// 3.2.2 update task by ID in database
-func (ts taskService) DoneTask(ctx context.Context, id uint) error {
+// 3.2.2 reduce end condition "count" of repeat and update task by ID in database
+//
+// 4. completion of scheduled date and time with 'note' is saved to history together with 3.2 see 'complete'
+func (ts taskService) DoneTask(ctx context.Context, id uint, note string) error {
 	if id == 0 {
 		return ErrCaseTaskZeroID
 	}
@@ -237,30 +403,100 @@ func (ts taskService) DoneTask(ctx context.Context, id uint) error {
 		}
 		return services.ErrServicesInternalError
 	}
-	date, err := ts.updateDateAfterDone(task.Date, task.Repeat)
+	nextDate, err := ts.taskAlgorithm(&task)
+	if err != nil {
+		return services.ErrServicesInternalError
+	}
+	now := ts.now(ctx)
+	date, clock := "", task.Time
+	if isHourly(task.Algorithm, task.Repeat) {
+		date, clock, err = ts.updateTimeAfterDone(now, task.Date, task.Time, task.Repeat, task.Mode)
+	} else {
+		date, err = ts.updateDateAfterDone(now, task.Date, task.Repeat, task.Mode, nextDate)
+	}
+	if err == nil {
+		// mode "completion" -> series is restarted from today see 'updateDateAfterDone'
+		dstart := task.Date
+		if task.Mode == nextdate.ModeCompletion {
+			dstart = common.ReduceTimeToDayUTC(now).Format(model.DateFormat)
+		}
+		date, clock, err = ts.executeExdate(dstart, date, clock, task, nextDate)
+		if errors.Is(err, nextdate.ErrNextDateSeriesEnded) {
+			err = model.ErrModelTaskDone
+		}
+	}
+	completion := model.CompletionModel{
+		TaskID: id,
+		Date:   task.Date,
+		Time:   task.Time,
+		DoneAt: now.UTC().Format(time.RFC3339),
+		Note:   note,
+	}
 	if err != nil {
 		if errors.Is(err, model.ErrModelTaskDone) {
-			if err := ts.taskRepo.ExpirationTask(ctx, id); err != nil {
-				return services.ErrServicesInternalError
-			}
-			return nil
+			return ts.complete(ctx, entity.NewTaskDone(completion, nil))
 		}
 		return services.ErrServicesInternalError
 	}
+	repeat, err := nextdate.ReduceCountOf(task.Algorithm, task.Repeat)
+	if err != nil {
+		return services.ErrServicesInternalError
+	}
 	task.Date = date
-	if err := ts.taskRepo.NewDataTask(ctx, task); err != nil {
+	task.Time = clock
+	task.Repeat = repeat
+	return ts.complete(ctx, entity.NewTaskDone(completion, &task))
+}
+
+// complete - metod of taskService used only in 'DoneTask'
+// save completion and new data of task (nil - delete task) in one transaction of store
+func (ts taskService) complete(ctx context.Context, done *entity.TaskDone) error {
+	if err := ts.taskRepo.CompleteTask(ctx, done); err != nil {
+		if errors.Is(err, database.ErrDataBaseNotFound) {
+			return ErrCaseTaskNotFound
+		}
 		return services.ErrServicesInternalError
 	}
 	return nil
 }
 
+// ReadTaskHistory - member of taskService
+//
+// 1. check ID by zero
+// 2. find completions of task in order of completion
+// 3. without completions -> task must exist, deleted task keeps its history
+// 4. create HistoryResponse
+func (ts taskService) ReadTaskHistory(ctx context.Context, id uint) (*serializer.HistoryResponse, error) {
+	if id == 0 {
+		return nil, ErrCaseTaskZeroID
+	}
+	completions, err := ts.taskRepo.FindCompletionList(ctx, id)
+	if err != nil {
+		return nil, services.ErrServicesInternalError
+	}
+	if len(completions) == 0 {
+		if _, err := ts.taskRepo.FindOneTask(ctx, id); err != nil {
+			if errors.Is(err, database.ErrDataBaseNotFound) {
+				return nil, ErrCaseTaskNotFound
+			}
+			return nil, services.ErrServicesInternalError
+		}
+	}
+	return serializer.HistoryEncode{TaskID: id, Completions: completions}.Response(), nil
+}
+
 //	updateDateAfterDone - metod of taskService used only in 'DoneTask'
 //
 // rules:
 // 1. repeat - empty -> task done -> delete
 // 2. update the date using 'nextDate' algorithm
-// 2.1 if now Before t.Date -> now = t.Date
-func (ts taskService) updateDateAfterDone(date, repeat string) (string, error) {
+// 2.1 mode "completion" -> series is restarted from today (lib/nextdate/mode.go)
+// 2.2 mode "schedule" -> if now Before t.Date -> now = t.Date
+// 2.3 series of repeat ended -> task done -> delete
+func (ts taskService) updateDateAfterDone(
+	now time.Time,
+	date, repeat, mode string,
+	nextDateFunc nextdate.NextDateFunc) (string, error) {
 	if repeat == "" {
 		return "", model.ErrModelTaskDone
 	}
@@ -268,17 +504,115 @@ func (ts taskService) updateDateAfterDone(date, repeat string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	now := common.ReduceTimeToDay(time.Now())
-	if now.UTC().Before(oldDate.UTC()) {
-		now = oldDate
+	now = common.ReduceTimeToDayUTC(now)
+	newDate := ""
+	if mode == nextdate.ModeCompletion {
+		newDate, err = nextdate.NextDateAfterDone(nextDateFunc, now, repeat)
+	} else {
+		if now.Before(oldDate) {
+			now = oldDate
+		}
+		newDate, err = nextDateFunc(now, date, repeat)
+	}
+	if err != nil && errors.Is(err, nextdate.ErrNextDateSeriesEnded) {
+		return "", model.ErrModelTaskDone
+	}
+	return newDate, err
+}
+
+//	updateTimeAfterDone - metod of taskService used only in 'DoneTask' for rule 'h'
+//
+// 1. mode "completion" -> next occurrence from now (lib/nextdate/hourly.go)
+// 2. mode "schedule" -> next occurrence of series after now or after occurrence of task
+// 3. series of repeat ended -> task done -> delete
+func (ts taskService) updateTimeAfterDone(now time.Time, date, clock, repeat, mode string) (string, string, error) {
+	var (
+		newDate, newTime string
+		err              error
+	)
+	if mode == nextdate.ModeCompletion {
+		newDate, newTime, err = nextdate.NextTimeAfterDone(now, repeat)
+	} else {
+		newDate, newTime, err = nextdate.NextTime(now, date, clock, repeat)
+	}
+	if err != nil && errors.Is(err, nextdate.ErrNextDateSeriesEnded) {
+		return "", "", model.ErrModelTaskDone
+	}
+	return newDate, newTime, err
+}
+
+// SkipTask - member of taskService
+//
+// 1. check ID by zero
+// 2. find task by ID, task without repeat -> error
+// 3. find occurrence of series after occurrence of task see bellow 'nextOccurrence', mode of repeat is not used
+// occurrence at exception date -> occurrence after it see 'executeExdate'
+// 4.1 series without next occurrence -> delete task from database by ID
+// 4.2 update task by ID in database, end condition "count" is not reduced - occurrence is not done
+func (ts taskService) SkipTask(ctx context.Context, id uint) error {
+	if id == 0 {
+		return ErrCaseTaskZeroID
+	}
+	task, err := ts.taskRepo.FindOneTask(ctx, id)
+	if err != nil {
+		if errors.Is(err, database.ErrDataBaseNotFound) {
+			return ErrCaseTaskNotFound
+		}
+		return services.ErrServicesInternalError
+	}
+	if task.Repeat == "" {
+		return ErrCaseTaskNotRecurring
+	}
+	nextDate, err := ts.taskAlgorithm(&task)
+	if err != nil {
+		return services.ErrServicesInternalError
+	}
+	date, clock, err := ts.nextOccurrence(task, nextDate)
+	if err == nil {
+		date, clock, err = ts.executeExdate(task.Date, date, clock, task, nextDate)
+	}
+	if err != nil {
+		if errors.Is(err, nextdate.ErrNextDateSeriesEnded) {
+			if err := ts.taskRepo.ExpirationTask(ctx, id); err != nil {
+				return services.ErrServicesInternalError
+			}
+			return nil
+		}
+		return services.ErrServicesInternalError
+	}
+	task.Date = date
+	task.Time = clock
+	if err := ts.taskRepo.NewDataTask(ctx, task); err != nil {
+		return services.ErrServicesInternalError
+	}
+	return nil
+}
+
+// nextOccurrence - metod of taskService used only in 'SkipTask'
+// date of series after date of task, rule 'h' - date and time after date and time of task
+func (ts taskService) nextOccurrence(
+	task model.TaskModel,
+	nextDateFunc nextdate.NextDateFunc) (string, string, error) {
+	occurrence, err := time.Parse(model.DateFormat, task.Date)
+	if err != nil {
+		return "", "", nextdate.ErrNextDateInvalidDate
+	}
+	if isHourly(task.Algorithm, task.Repeat) {
+		// 'now' before time of task -> next occurrence after date and time of task
+		return nextdate.NextTime(occurrence, task.Date, task.Time, task.Repeat)
 	}
-	return ts.nextDate(now, date, repeat)
+	date, err := nextDateFunc(occurrence, task.Date, task.Repeat)
+	return date, task.Time, err
 }
 
 // ReadTaskList - member of taskService
 //
 // 1. find task list by 'entity.TaskProperty' look (/internal/services/entity/taskproperty.go)
-// 2. create TaslListResponse
+// 2. search by date -> add recurring tasks with occurrence on this date see below 'projectTasks',
+// page is ordered by key of 'property' again
+// 3. full page -> cursor of next page from last task see (/internal/services/entity/taskcursor.go),
+// order by relevance is not stable between calls -> without cursor
+// 4. create TaslListResponse, projected tasks are marked
 func (ts taskService) ReadTaskList(
 	ctx context.Context,
 	property *entity.TaskProperty) (*serializer.TaslListResponse, error) {
@@ -287,5 +621,214 @@ func (ts taskService) ReadTaskList(
 		return nil, services.ErrServicesInternalError
 	}
 	serialize := serializer.TaskListEncode{Tasks: tasks}
+	if property.IsDate() {
+		projected, err := ts.projectTasks(ctx, property)
+		if err != nil {
+			return nil, services.ErrServicesInternalError
+		}
+		serialize.Projected = make(map[uint]bool, len(projected))
+		for _, task := range projected {
+			serialize.Projected[task.ID] = true
+		}
+		tasks = append(tasks, projected...)
+		// order of page is same as in database
+		sort.Slice(tasks, func(i, j int) bool {
+			return property.Less(tasks[i], tasks[j])
+		})
+		if limit := int(property.PassLimit()); len(tasks) > limit {
+			tasks = tasks[:limit]
+		}
+		serialize.Tasks = tasks
+	}
+	if len(tasks) > 0 && len(tasks) == int(property.PassLimit()) && !property.IsRelevance() {
+		serialize.NextCursor = entity.NewTaskCursor(tasks[len(tasks)-1]).Encode()
+	}
+	return serialize.Response(), nil
+}
+
+// projectTasks - metod of taskService used only in 'ReadTaskList'
+// recurring tasks with stored date before date of 'property' and occurrence on it,
+// date and time of task are replaced by this occurrence
+//
+// 1. task with mode "completion" is skipped - its next dates depend on completion
+// 2. rule 'h' -> first occurrence of day see (lib/nextdate/hourly.go), exception date is skipped
+// 3. othercase date of series see (lib/nextdate/occurrences.go)
+// task with invalid rule is skipped, occurrence out of filter or before cursor of page is skipped
+func (ts taskService) projectTasks(ctx context.Context, property *entity.TaskProperty) ([]model.TaskModel, error) {
+	tasks, err := ts.taskRepo.FindRepeatTaskList(ctx, property)
+	if err != nil {
+		return nil, err
+	}
+	day := common.ReduceTimeToDayUTC(property.PassDate())
+	date := day.Format(model.DateFormat)
+	projected := make([]model.TaskModel, 0, len(tasks))
+	for _, task := range tasks {
+		nextDate, err := ts.taskAlgorithm(&task)
+		if err != nil || task.Mode == nextdate.ModeCompletion {
+			continue
+		}
+		if isHourly(task.Algorithm, task.Repeat) {
+			clock, ok := hourlyOn(task, day)
+			if !ok {
+				continue
+			}
+			task.Time = clock
+		} else if occurs, err := nextdate.OccursOn(nextDate, task.Algorithm, task.Date, task.Repeat, day); err != nil || !occurs {
+			continue
+		}
+		task.Date = date
+		if !property.Matches(task) || !property.IsAfterCursor(task) {
+			continue
+		}
+		projected = append(projected, task)
+	}
+	return projected, nil
+}
+
+// hourlyOn - first occurrence of rule 'h' in 'day', exception date - false
+func hourlyOn(task model.TaskModel, day time.Time) (string, bool) {
+	date := day.Format(model.DateFormat)
+	if slices.Contains(nextdate.SplitExdates(task.Exdates), date) {
+		return "", false
+	}
+	// last minute of previous day -> first occurrence of 'day'
+	next, clock, err := nextdate.NextTime(day.Add(-time.Minute), task.Date, task.Time, task.Repeat)
+	if err != nil || next != date {
+		return "", false
+	}
+	return clock, true
+}
+
+// AgendaTask - member of taskService
+//
+// 1. find tasks of window and recurring tasks before end of window
+// 2. all occurrences of each task in window see below 'occurrencesOf'
+// 3. occurrences of day are ordered by time (task for whole day is first) and ID
+// 4. create AgendaResponse, occurrence at date other than stored date of task is projected
+func (ts taskService) AgendaTask(
+	ctx context.Context,
+	agenda *entity.TaskAgenda) (*serializer.AgendaResponse, error) {
+	tasks, err := ts.taskRepo.FindAgendaTaskList(ctx, agenda)
+	if err != nil {
+		return nil, services.ErrServicesInternalError
+	}
+	serialize := serializer.AgendaEncode{
+		Tasks:  make(map[string][]model.TaskModel),
+		Stored: make(map[uint]string, len(tasks)),
+	}
+	for _, day := range agenda.Days() {
+		serialize.Dates = append(serialize.Dates, day.Format(model.DateFormat))
+	}
+	for _, task := range tasks {
+		serialize.Stored[task.ID] = task.Date
+		for _, occurrence := range ts.occurrencesOf(task, agenda) {
+			serialize.Tasks[occurrence.Date] = append(serialize.Tasks[occurrence.Date], occurrence)
+		}
+	}
+	for _, occurrences := range serialize.Tasks {
+		sort.Slice(occurrences, func(i, j int) bool {
+			if occurrences[i].Time != occurrences[j].Time {
+				return occurrences[i].Time < occurrences[j].Time
+			}
+			return occurrences[i].ID < occurrences[j].ID
+		})
+	}
+	return serialize.Response(), nil
+}
+
+// occurrencesOf - metod of taskService used only in 'AgendaTask'
+// copies of task with date (and time) of each occurrence in window of 'agenda'
+//
+// 1. stored date of task in window is first occurrence
+// 2. task without repeat or with mode "completion" - only stored date, next dates depend on completion
+// 3. rule 'h' -> first occurrence of each day after stored date use - 'hourlyOn'
+// 4. othercase dates of series in window by algorithm of task see (lib/nextdate/occurrences.go)
+// task with invalid rule - only stored date
+func (ts taskService) occurrencesOf(task model.TaskModel, agenda *entity.TaskAgenda) []model.TaskModel {
+	from := agenda.PassFrom().Format(model.DateFormat)
+	to := agenda.PassTo().Format(model.DateFormat)
+	nextDate, err := ts.taskAlgorithm(&task)
+	var occurrences []model.TaskModel
+	if task.Date >= from && task.Date <= to {
+		occurrences = append(occurrences, task)
+	}
+	if err != nil || task.Repeat == "" || task.Mode == nextdate.ModeCompletion {
+		return occurrences
+	}
+	if isHourly(task.Algorithm, task.Repeat) {
+		for _, day := range agenda.Days() {
+			if day.Format(model.DateFormat) <= task.Date {
+				continue
+			}
+			if clock, ok := hourlyOn(task, day); ok {
+				occurrence := task
+				occurrence.Date = day.Format(model.DateFormat)
+				occurrence.Time = clock
+				occurrences = append(occurrences, occurrence)
+			}
+		}
+		return occurrences
+	}
+	dates, err := nextdate.Between(nextDate, task.Algorithm, task.Date, task.Repeat, agenda.PassFrom(), agenda.PassTo())
+	if err != nil {
+		return occurrences
+	}
+	for _, date := range dates {
+		if date == task.Date {
+			continue
+		}
+		occurrence := task
+		occurrence.Date = date
+		occurrences = append(occurrences, occurrence)
+	}
+	return occurrences
+}
+
+// PreviewTask - member of taskService
+//
+// 1. stored task -> find task by ID, othercase ad-hoc rule from 'preview'
+// 2. set algorithm of task use - 'taskAlgorithm'
+// 3. empty date of ad-hoc rule -> today
+// 4. find dates of series not before 'now' see (lib/nextdate/occurrences.go)
+// 5. create TaskPreviewResponse with description of rule if language is set
+func (ts taskService) PreviewTask(
+	ctx context.Context,
+	preview *entity.TaskPreview) (*serializer.TaskPreviewResponse, error) {
+	task := preview.PassTask()
+	if preview.IsTask() {
+		storeTask, err := ts.taskRepo.FindOneTask(ctx, preview.PassID())
+		if err != nil {
+			if errors.Is(err, database.ErrDataBaseNotFound) {
+				return nil, ErrCaseTaskNotFound
+			}
+			return nil, services.ErrServicesInternalError
+		}
+		task = storeTask
+	}
+	nextDate, err := ts.taskAlgorithm(&task)
+	if err != nil {
+		if preview.IsTask() {
+			return nil, services.ErrServicesInternalError
+		}
+		return nil, err
+	}
+	now := preview.PassNow()
+	if task.Date == "" {
+		task.Date = common.ReduceTimeToDay(now).Format(model.DateFormat)
+	}
+	dates, err := nextdate.Occurrences(nextDate, task.Algorithm, now, task.Date, task.Repeat, preview.PassLimit(), preview.PassTo())
+	if err != nil {
+		if !preview.IsTask() &&
+			(errors.Is(err, nextdate.ErrNextDateInvalidDate) ||
+				errors.Is(err, nextdate.ErrNextDateWrongRepeat)) {
+			return nil, err
+		}
+		return nil, services.ErrServicesInternalError
+	}
+	serialize := serializer.TaskPreviewEncode{ID: task.ID, Dates: dates}
+	if lang := preview.PassLanguage(); lang != "" && task.Repeat != "" {
+		// algorithm added by 'Register' has no description
+		serialize.Description, _ = nextdate.Describe(task.Repeat, lang)
+	}
 	return serialize.Response(), nil
 }
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"testing"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/config"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/database/mock"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/datauser"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/lib/clock"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/lib/jwtsign"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/lib/nextdate"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/model"
@@ -42,6 +44,8 @@ func Test_All_Usecase(t *testing.T) {
 	var ( // for compare type
 		nilPtrTaskIDResponse *serializer.TaskIDResponse = nil
 		nilPtrTaskResponse   *serializer.TaskResponse   = nil
+
+		nilPtrTaskPreviewResponse *serializer.TaskPreviewResponse = nil
 	)
 
 	var dataForTaskService = []struct {
@@ -116,14 +120,16 @@ func Test_All_Usecase(t *testing.T) {
 		{ // 5
 			description: `task Read no error`,
 			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
-				return ts.ReadTask(ctx, data.(uint))
+				return ts.ReadTask(ctx, data.(uint), nil)
 			},
 			ctxTimeOut: 100 * time.Second,
 			data:       uint(1),
 			expectedRes: &serializer.TaskResponse{
-				Title:   "first",
-				Comment: "ololo",
-				Repeat:  "d 1",
+				Title:     "first",
+				Comment:   "ololo",
+				Repeat:    "d 1",
+				Algorithm: "nextdate",
+				Mode:      "schedule",
 			},
 			err: nil,
 			msg: `should return *TaskResponse and error is nil`,
@@ -131,7 +137,7 @@ func Test_All_Usecase(t *testing.T) {
 		{ // 6
 			description: `task Read not found`,
 			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
-				return ts.ReadTask(ctx, data.(uint))
+				return ts.ReadTask(ctx, data.(uint), nil)
 			},
 			ctxTimeOut:  100 * time.Second,
 			data:        uint(1_000_000),
@@ -206,7 +212,7 @@ func Test_All_Usecase(t *testing.T) {
 		{ // 11
 			description: `task done valid`,
 			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
-				return nil, ts.DoneTask(ctx, data.(uint))
+				return nil, ts.DoneTask(ctx, data.(uint), "")
 			},
 			ctxTimeOut:  100 * time.Second,
 			data:        uint(1),
@@ -217,7 +223,7 @@ func Test_All_Usecase(t *testing.T) {
 		{ // 12
 			description: `task done not found`,
 			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
-				return nil, ts.DoneTask(ctx, data.(uint))
+				return nil, ts.DoneTask(ctx, data.(uint), "")
 			},
 			ctxTimeOut:  100 * time.Second,
 			data:        uint(1_000_000),
@@ -236,10 +242,12 @@ func Test_All_Usecase(t *testing.T) {
 				// ID,Date set from Response
 				TasksResp: []serializer.TaskResponse{
 					{
-						ID:      "1",
-						Title:   "fifth",
-						Comment: "abcd",
-						Repeat:  "w 3,4,5",
+						ID:        "1",
+						Title:     "fifth",
+						Comment:   "abcd",
+						Repeat:    "w 3,4,5",
+						Algorithm: "nextdate",
+						Mode:      "schedule",
 					},
 					{
 						ID:    "2",
@@ -253,7 +261,7 @@ func Test_All_Usecase(t *testing.T) {
 		{ // 14
 			description: `task done valid`,
 			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
-				return nil, ts.DoneTask(ctx, data.(uint))
+				return nil, ts.DoneTask(ctx, data.(uint), "")
 			},
 			ctxTimeOut:  100 * time.Second,
 			data:        uint(2),
@@ -294,11 +302,340 @@ func Test_All_Usecase(t *testing.T) {
 			err:         ErrCaseTaskNotFound,
 			msg:         `wrong delete task, res is nil and error - not found`,
 		},
+		{ // 18
+			description: `task create with end condition "count"`,
+			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
+				return ts.CreateTask(ctx, data.(model.TaskModel))
+			},
+			ctxTimeOut: 100 * time.Second,
+			data: model.TaskModel{
+				Title:  "seventh",
+				Repeat: "d 1 count 2",
+			},
+			expectedRes: &serializer.TaskIDResponse{ID: `^3$`},
+			err:         nil,
+			msg:         `should return *TaskResponse and error is nil`,
+		},
+		{ // 19
+			description: `task done with end condition "count"`,
+			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
+				return nil, ts.DoneTask(ctx, data.(uint), "")
+			},
+			ctxTimeOut:  100 * time.Second,
+			data:        uint(3),
+			expectedRes: nil,
+			err:         nil,
+			msg:         `should update date and count, res is nil and error nil`,
+		},
+		{ // 20
+			description: `task Read after done with "count"`,
+			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
+				return ts.ReadTask(ctx, data.(uint), nil)
+			},
+			ctxTimeOut: 100 * time.Second,
+			data:       uint(3),
+			expectedRes: &serializer.TaskResponse{
+				Title:     "seventh",
+				Repeat:    "d 1 count 1",
+				Algorithm: "nextdate",
+				Mode:      "schedule",
+			},
+			err: nil,
+			msg: `should return *TaskResponse with reduced count and error is nil`,
+		},
+		{ // 21
+			description: `task done last occurrence of series`,
+			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
+				return nil, ts.DoneTask(ctx, data.(uint), "")
+			},
+			ctxTimeOut:  100 * time.Second,
+			data:        uint(3),
+			expectedRes: nil,
+			err:         nil,
+			msg:         `series ended task delete, res is nil and error nil`,
+		},
+		{ // 22
+			description: `task Read after series ended`,
+			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
+				return ts.ReadTask(ctx, data.(uint), nil)
+			},
+			ctxTimeOut:  100 * time.Second,
+			data:        uint(3),
+			expectedRes: nilPtrTaskResponse,
+			err:         ErrCaseTaskNotFound,
+			msg:         `should return nil and error`,
+		},
+		{ // 23
+			description: `task create with ended series`,
+			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
+				return ts.CreateTask(ctx, data.(model.TaskModel))
+			},
+			ctxTimeOut: 100 * time.Second,
+			data: model.TaskModel{
+				Date:   "20240101",
+				Title:  "eighth",
+				Repeat: "d 1 until 20240105",
+			},
+			expectedRes: nilPtrTaskIDResponse,
+			err:         nextdate.ErrNextDateSeriesEnded,
+			msg:         `should return nil and error`,
+		},
+		{ // 24
+			description: `task create with RRULE`,
+			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
+				return ts.CreateTask(ctx, data.(model.TaskModel))
+			},
+			ctxTimeOut: 100 * time.Second,
+			data: model.TaskModel{
+				Date:   "20240101",
+				Title:  "ninth",
+				Repeat: "FREQ=WEEKLY;BYDAY=MO",
+			},
+			expectedRes: &serializer.TaskIDResponse{ID: `^[0-9]+$`},
+			err:         nil,
+			msg:         `should return *TaskIDResponse and error is nil`,
+		},
+		{ // 25
+			description: `task Read with RRULE form`,
+			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
+				return ts.ReadTask(ctx, data.(uint), entity.NewTaskFormat("true", ""))
+			},
+			ctxTimeOut: 100 * time.Second,
+			data:       uint(4),
+			expectedRes: &serializer.TaskResponse{
+				Title:     "ninth",
+				Repeat:    "FREQ=WEEKLY;BYDAY=MO",
+				Algorithm: "rrule",
+				Mode:      "schedule",
+				RRule:     "FREQ=WEEKLY;BYDAY=MO",
+			},
+			err: nil,
+			msg: `should return *TaskResponse with rrule and error is nil`,
+		},
+		{ // 26
+			description: `task create with unknown algorithm`,
+			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
+				return ts.CreateTask(ctx, data.(model.TaskModel))
+			},
+			ctxTimeOut: 100 * time.Second,
+			data: model.TaskModel{
+				Title:     "tenth",
+				Repeat:    "d 1",
+				Algorithm: "alien",
+			},
+			expectedRes: nilPtrTaskIDResponse,
+			err:         nextdate.ErrNextDateUnknownAlgorithm,
+			msg:         `should return nil and error`,
+		},
+		{ // 27
+			description: `task create with algorithm not for syntax of repeat`,
+			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
+				return ts.CreateTask(ctx, data.(model.TaskModel))
+			},
+			ctxTimeOut: 100 * time.Second,
+			data: model.TaskModel{
+				Date:      "20240101",
+				Title:     "tenth",
+				Repeat:    "d 1",
+				Algorithm: nextdate.AlgorithmRRule,
+			},
+			expectedRes: nilPtrTaskIDResponse,
+			err:         nextdate.ErrNextDateWrongRepeat,
+			msg:         `should return nil and error`,
+		},
+		{ // 28
+			description: `preview dates of ad-hoc rule`,
+			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
+				return ts.PreviewTask(ctx, data.(*entity.TaskPreview))
+			},
+			ctxTimeOut: 100 * time.Second,
+			data: entity.NewTaskPreview(0,
+				model.TaskModel{Date: "20240131", Repeat: "m -1"},
+				time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), time.Time{}, 3, ""),
+			expectedRes: &serializer.TaskPreviewResponse{Dates: []string{"20240131", "20240229", "20240331"}},
+			err:         nil,
+			msg:         `should return *TaskPreviewResponse and error is nil`,
+		},
+		{ // 29
+			description: `preview dates of stored task`,
+			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
+				return ts.PreviewTask(ctx, data.(*entity.TaskPreview))
+			},
+			ctxTimeOut: 100 * time.Second,
+			data: entity.NewTaskPreview(4, model.TaskModel{},
+				time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{}, 2, "en"),
+			expectedRes: &serializer.TaskPreviewResponse{
+				ID:          "4",
+				Dates:       []string{"20990105", "20990112"},
+				Description: "every Monday",
+			},
+			err: nil,
+			msg: `should return *TaskPreviewResponse with ID and error is nil`,
+		},
+		{ // 30
+			description: `preview dates of task not found`,
+			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
+				return ts.PreviewTask(ctx, data.(*entity.TaskPreview))
+			},
+			ctxTimeOut:  100 * time.Second,
+			data:        entity.NewTaskPreview(99, model.TaskModel{}, time.Now(), time.Time{}, 2, ""),
+			expectedRes: nilPtrTaskPreviewResponse,
+			err:         ErrCaseTaskNotFound,
+			msg:         `should return nil and error`,
+		},
+		{ // 31
+			description: `preview dates of rule with unknown algorithm`,
+			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
+				return ts.PreviewTask(ctx, data.(*entity.TaskPreview))
+			},
+			ctxTimeOut: 100 * time.Second,
+			data: entity.NewTaskPreview(0,
+				model.TaskModel{Repeat: "d 1", Algorithm: "alien"}, time.Now(), time.Time{}, 2, ""),
+			expectedRes: nilPtrTaskPreviewResponse,
+			err:         nextdate.ErrNextDateUnknownAlgorithm,
+			msg:         `should return nil and error`,
+		},
+		{ // 32
+			description: `task create with repeat from completion`,
+			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
+				return ts.CreateTask(ctx, data.(model.TaskModel))
+			},
+			ctxTimeOut: 100 * time.Second,
+			data: model.TaskModel{
+				Date:   "20240101",
+				Title:  "water plants",
+				Repeat: "d 7",
+				Mode:   nextdate.ModeCompletion,
+			},
+			expectedRes: &serializer.TaskIDResponse{ID: `^5$`},
+			err:         nil,
+			msg:         `should return *TaskIDResponse and error is nil`,
+		},
+		{ // 33
+			description: `task done with repeat from completion`,
+			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
+				return nil, ts.DoneTask(ctx, data.(uint), "")
+			},
+			ctxTimeOut:  100 * time.Second,
+			data:        uint(5),
+			expectedRes: nil,
+			err:         nil,
+			msg:         `should update date res is nil and error nil`,
+		},
+		{ // 34
+			description: `task Read with repeat from completion`,
+			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
+				return ts.ReadTask(ctx, data.(uint), nil)
+			},
+			ctxTimeOut: 100 * time.Second,
+			data:       uint(5),
+			expectedRes: &serializer.TaskResponse{
+				Title:     "water plants",
+				Repeat:    "d 7",
+				Algorithm: "nextdate",
+				Mode:      "completion",
+			},
+			err: nil,
+			msg: `should return *TaskResponse with mode and error is nil`,
+		},
+		{ // 35
+			description: `task create with hourly repeat`,
+			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
+				return ts.CreateTask(ctx, data.(model.TaskModel))
+			},
+			ctxTimeOut: 100 * time.Second,
+			data: model.TaskModel{
+				Date:   "20990101",
+				Title:  "drink water",
+				Repeat: "h 4 09:00-18:00",
+			},
+			expectedRes: &serializer.TaskIDResponse{ID: `^6$`},
+			err:         nil,
+			msg:         `should return *TaskIDResponse and error is nil`,
+		},
+		{ // 36
+			description: `task Read with time from window of repeat`,
+			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
+				return ts.ReadTask(ctx, data.(uint), nil)
+			},
+			ctxTimeOut: 100 * time.Second,
+			data:       uint(6),
+			expectedRes: &serializer.TaskResponse{
+				Time:      "09:00",
+				Title:     "drink water",
+				Repeat:    "h 4 09:00-18:00",
+				Algorithm: "nextdate",
+				Mode:      "schedule",
+			},
+			err: nil,
+			msg: `should return *TaskResponse with time and error is nil`,
+		},
+		{ // 37
+			description: `task done with hourly repeat`,
+			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
+				return nil, ts.DoneTask(ctx, data.(uint), "")
+			},
+			ctxTimeOut:  100 * time.Second,
+			data:        uint(6),
+			expectedRes: nil,
+			err:         nil,
+			msg:         `should update date and time res is nil and error nil`,
+		},
+		{ // 38
+			description: `task Read after done with hourly repeat`,
+			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
+				return ts.ReadTask(ctx, data.(uint), nil)
+			},
+			ctxTimeOut: 100 * time.Second,
+			data:       uint(6),
+			expectedRes: &serializer.TaskResponse{
+				Time:      "13:00",
+				Title:     "drink water",
+				Repeat:    "h 4 09:00-18:00",
+				Algorithm: "nextdate",
+				Mode:      "schedule",
+			},
+			err: nil,
+			msg: `should return *TaskResponse with next time and error is nil`,
+		},
+		{ // 39
+			description: `task create with time of day`,
+			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
+				return ts.CreateTask(ctx, data.(model.TaskModel))
+			},
+			ctxTimeOut: 100 * time.Second,
+			data: model.TaskModel{
+				Date:   "20240101",
+				Time:   "08:30",
+				Title:  "stretch",
+				Repeat: "d 1",
+			},
+			expectedRes: &serializer.TaskIDResponse{ID: `^7$`},
+			err:         nil,
+			msg:         `should return *TaskIDResponse and error is nil`,
+		},
+		{ // 40
+			description: `task Read with time of day`,
+			init: func(ctx context.Context, ts TaskService, data any) (any, error) {
+				return ts.ReadTask(ctx, data.(uint), nil)
+			},
+			ctxTimeOut: 100 * time.Second,
+			data:       uint(7),
+			expectedRes: &serializer.TaskResponse{
+				Time:      "08:30",
+				Title:     "stretch",
+				Repeat:    "d 1",
+				Algorithm: "nextdate",
+				Mode:      "schedule",
+			},
+			err: nil,
+			msg: `should return *TaskResponse with time and error is nil`,
+		},
 	}
 
 	ctx := context.Background()
 
-	taskService, err := NewTaskService(cfg, mock.NewMockTaskStore())
+	taskService, err := NewTaskService(cfg, mock.NewMockTaskStore(), clock.System{})
 	requires.NoError(err, fmt.Sprintf("usecase_test: task service error - %v - should be no error", err))
 
 	for i, test := range dataForTaskService {
@@ -309,7 +646,7 @@ func Test_All_Usecase(t *testing.T) {
 
 		res, err := test.init(ctx, taskService, test.data)
 
-		asserts.ErrorIs(test.err, err, "erros no equal "+test.msg)
+		asserts.ErrorIs(err, test.err, "erros no equal "+test.msg)
 
 		switch v := res.(type) {
 		case *serializer.TaskIDResponse:
@@ -351,6 +688,15 @@ func Test_All_Usecase(t *testing.T) {
 				}
 				asserts.Equal(*expectedTasks, *v, "compare TaslListResponse - faild "+test.msg)
 			}
+		case *serializer.TaskPreviewResponse:
+			expectedDates, ok := test.expectedRes.(*serializer.TaskPreviewResponse)
+			requires.True(ok, "false!!! "+test.msg)
+			if expectedDates == nil {
+				asserts.Nil(v, "should be nil "+test.msg)
+			} else {
+				requires.NotNil(v, "should be not nil "+test.msg)
+				asserts.Equal(*expectedDates, *v, "TaskPreviewResponse not equal "+test.msg)
+			}
 		case nil:
 			asserts.Nil(test.expectedRes, "should be nil "+test.msg)
 		default:
@@ -507,3 +853,587 @@ func Test_All_Usecase(t *testing.T) {
 		asserts.ErrorIs(test.err, err, "unexpected error "+test.msg)
 	}
 }
+
+func Test_updateDateAfterDone(t *testing.T) {
+	asserts := assert.New(t)
+
+	// 20240110 01:30 in UTC+3 - in UTC it is still 20240109
+	now := time.Date(2024, 1, 10, 1, 30, 0, 0, time.FixedZone("UTC+3", 3*60*60))
+	ts := taskService{algorithm: nextdate.AlgorithmNextDate}
+
+	dataForAfterDone := []struct {
+		description string
+		date        string
+		repeat      string
+		mode        string
+		expected    string
+		err         error
+	}{
+		{
+			description: `schedule - next date from calendar of task`,
+			date:        "20240107",
+			repeat:      "d 7",
+			mode:        nextdate.ModeSchedule,
+			expected:    "20240114",
+		},
+		{
+			description: `empty mode is schedule`,
+			date:        "20240107",
+			repeat:      "d 7",
+			expected:    "20240114",
+		},
+		{
+			description: `schedule - "today" in time zone of request`,
+			date:        "20240109",
+			repeat:      "d 1",
+			mode:        nextdate.ModeSchedule,
+			expected:    "20240111",
+		},
+		{
+			description: `completion - next date from today`,
+			date:        "20240107",
+			repeat:      "d 7",
+			mode:        nextdate.ModeCompletion,
+			expected:    "20240117",
+		},
+		{
+			description: `completion - done before date of task`,
+			date:        "20240112",
+			repeat:      "d 7",
+			mode:        nextdate.ModeCompletion,
+			expected:    "20240117",
+		},
+		{
+			description: `completion - done after until`,
+			date:        "20240107",
+			repeat:      "d 7 until 20240109",
+			mode:        nextdate.ModeCompletion,
+			err:         model.ErrModelTaskDone,
+		},
+		{
+			description: `task without repeat`,
+			date:        "20240110",
+			mode:        nextdate.ModeCompletion,
+			err:         model.ErrModelTaskDone,
+		},
+	}
+
+	for i, test := range dataForAfterDone {
+		log.Printf("\t%d %s", i+1, test.description)
+
+		date, err := ts.updateDateAfterDone(now, test.date, test.repeat, test.mode, nextdate.NextDate)
+		asserts.ErrorIs(err, test.err, test.description)
+		asserts.Equal(test.expected, date, test.description)
+	}
+
+	// date of new task - "today" in time zone of request
+	date, err := ts.executeDate(now, "", "", "", nil)
+	asserts.NoError(err)
+	asserts.Equal("20240110", date)
+
+	date, err = ts.executeDate(now, "20240109", nextdate.AlgorithmNextDate, "d 3", nextdate.NextDate)
+	asserts.NoError(err)
+	asserts.Equal("20240112", date)
+}
+
+func Test_taskService_Clock(t *testing.T) {
+	asserts := assert.New(t)
+	requires := require.New(t)
+
+	// last day of february in leap year
+	clk := clock.Fixed(time.Date(2024, 2, 29, 10, 0, 0, 0, time.UTC))
+	cfg := &config.Config{TaskNextDate: nextdate.AlgorithmNextDate}
+
+	taskService, err := NewTaskService(cfg, mock.NewMockTaskStore(), clk)
+	requires.NoError(err)
+
+	dataForClock := []struct {
+		description string
+		task        model.TaskModel
+		date        string
+		dateDone    string
+	}{
+		{
+			description: `end of month - date before now`,
+			task:        model.TaskModel{Date: "20240131", Title: "rent", Repeat: "m 31"},
+			date:        "20240331",
+			dateDone:    "20240531",
+		},
+		{
+			description: `leap year - date is today`,
+			task:        model.TaskModel{Date: "20240229", Title: "birthday", Repeat: "y"},
+			date:        "20240229",
+			dateDone:    "20250301",
+		},
+		{
+			description: `empty date - today`,
+			task:        model.TaskModel{Title: "call", Repeat: "d 1"},
+			date:        "20240229",
+			dateDone:    "20240301",
+		},
+	}
+
+	ctx := context.Background()
+
+	for i, test := range dataForClock {
+		log.Printf("\t%d %s", i+1, test.description)
+
+		taskID, err := taskService.CreateTask(ctx, test.task)
+		requires.NoError(err, test.description)
+		id, err := strconv.ParseUint(taskID.ID, 10, 64)
+		requires.NoError(err, test.description)
+
+		task, err := taskService.ReadTask(ctx, uint(id), nil)
+		requires.NoError(err, test.description)
+		asserts.Equal(test.date, task.Date, test.description)
+
+		requires.NoError(taskService.DoneTask(ctx, uint(id), ""), test.description)
+		task, err = taskService.ReadTask(ctx, uint(id), nil)
+		requires.NoError(err, test.description)
+		asserts.Equal(test.dateDone, task.Date, "after done "+test.description)
+	}
+}
+
+func Test_taskService_Exdates(t *testing.T) {
+	asserts := assert.New(t)
+	requires := require.New(t)
+
+	clk := clock.Fixed(time.Date(2024, 12, 25, 10, 0, 0, 0, time.UTC)) // Wednesday
+	cfg := &config.Config{TaskNextDate: nextdate.AlgorithmNextDate}
+
+	taskService, err := NewTaskService(cfg, mock.NewMockTaskStore(), clk)
+	requires.NoError(err)
+
+	ctx := context.Background()
+
+	read := func(id uint) *serializer.TaskResponse {
+		task, err := taskService.ReadTask(ctx, id, nil)
+		requires.NoError(err)
+		return task
+	}
+
+	// 1. date of new task is exception date
+	taskID, err := taskService.CreateTask(ctx, model.TaskModel{
+		Date:    "20241231",
+		Title:   "backup",
+		Repeat:  "w 2",
+		Exdates: "20241231",
+	})
+	requires.NoError(err)
+	asserts.Equal("20250107", read(1).Date, "create - exception date is skipped")
+	asserts.Equal([]string{"20241231"}, read(1).Exdates)
+
+	// 2. done - next occurrence is exception date
+	requires.NoError(taskService.UpdateTask(ctx, model.TaskModel{
+		ID:      1,
+		Date:    "20250107",
+		Title:   "backup",
+		Repeat:  "w 2 count 3",
+		Exdates: "20250114",
+	}))
+	requires.NoError(taskService.DoneTask(ctx, 1, ""))
+	asserts.Equal("20250121", read(1).Date, "done - exception date is skipped")
+	asserts.Equal("w 2 count 2", read(1).Repeat)
+
+	// 3. skip - "count" is not reduced
+	requires.NoError(taskService.SkipTask(ctx, 1))
+	task := read(1)
+	asserts.Equal("20250128", task.Date, "skip - next occurrence")
+	asserts.Equal("w 2 count 2", task.Repeat, "skip - occurrence is not done")
+
+	// 4. skip last occurrence - task is deleted
+	requires.NoError(taskService.UpdateTask(ctx, model.TaskModel{
+		ID:     1,
+		Date:   "20250128",
+		Title:  "backup",
+		Repeat: "w 2 until 20250130",
+	}))
+	requires.NoError(taskService.SkipTask(ctx, 1))
+	_, err = taskService.ReadTask(ctx, 1, nil)
+	asserts.ErrorIs(err, ErrCaseTaskNotFound, "skip last occurrence")
+
+	// 5. rule 'h' - skip next occurrence, exception date skips all day
+	taskID, err = taskService.CreateTask(ctx, model.TaskModel{
+		Date:    "20241226",
+		Time:    "17:00",
+		Title:   "water",
+		Repeat:  "h 4 09:00-18:00",
+		Exdates: "20241227",
+	})
+	requires.NoError(err)
+	requires.Equal("2", taskID.ID)
+	requires.NoError(taskService.SkipTask(ctx, 2))
+	task = read(2)
+	asserts.Equal("20241228", task.Date, "skip - exception date of rule 'h'")
+	asserts.Equal("09:00", task.Time)
+
+	// 6. task without repeat
+	taskID, err = taskService.CreateTask(ctx, model.TaskModel{Title: "call", Exdates: "20241231"})
+	requires.NoError(err)
+	asserts.Empty(read(3).Exdates, "exception dates only with repeat")
+	asserts.ErrorIs(taskService.SkipTask(ctx, 3), ErrCaseTaskNotRecurring)
+	asserts.ErrorIs(taskService.SkipTask(ctx, 0), ErrCaseTaskZeroID)
+	asserts.ErrorIs(taskService.SkipTask(ctx, 42), ErrCaseTaskNotFound)
+
+	// 7. exception date is skipped in phase of start of series
+	taskID, err = taskService.CreateTask(ctx, model.TaskModel{
+		Date:    "20241219",
+		Title:   "pills",
+		Repeat:  "d 3",
+		Exdates: "20241228",
+	})
+	requires.NoError(err)
+	requires.Equal("4", taskID.ID)
+	asserts.Equal("20241231", read(4).Date, "d 3 from start of series")
+
+	// 8. first occurrence is exception date - "count" is not used up
+	taskID, err = taskService.CreateTask(ctx, model.TaskModel{
+		Date:    "20241231",
+		Title:   "report",
+		Repeat:  "d 3 count 1",
+		Exdates: "20241231",
+	})
+	requires.NoError(err)
+	requires.Equal("5", taskID.ID)
+	task = read(5)
+	asserts.Equal("20250103", task.Date, "count 1 - exception date is not done")
+	asserts.Equal("d 3 count 1", task.Repeat)
+	requires.NoError(taskService.DoneTask(ctx, 5, ""))
+	_, err = taskService.ReadTask(ctx, 5, nil)
+	asserts.ErrorIs(err, ErrCaseTaskNotFound, "last occurrence is done")
+}
+
+func Test_taskService_ProjectedTasks(t *testing.T) {
+	asserts := assert.New(t)
+	requires := require.New(t)
+
+	clk := clock.Fixed(time.Date(2024, 12, 25, 10, 0, 0, 0, time.UTC)) // Wednesday
+	cfg := &config.Config{TaskNextDate: nextdate.AlgorithmNextDate}
+
+	taskService, err := NewTaskService(cfg, mock.NewMockTaskStore(), clk)
+	requires.NoError(err)
+
+	ctx := context.Background()
+
+	for _, task := range []model.TaskModel{
+		{Date: "20250107", Title: "stored"},                                                   // 1
+		{Date: "20241231", Title: "weekly", Repeat: "w 2"},                                    // 2
+		{Date: "20241226", Title: "every 5 days", Repeat: "d 5"},                              // 3
+		{Date: "20241231", Title: "ended", Repeat: "w 2 count 1"},                             // 4
+		{Date: "20241231", Title: "exception", Repeat: "w 2", Exdates: "20250107"},            // 5
+		{Date: "20241226", Time: "09:00", Title: "water", Repeat: "h 4 09:00-18:00"},          // 6
+		{Date: "20241226", Title: "after done", Repeat: "d 1", Mode: nextdate.ModeCompletion}, // 7
+	} {
+		_, err := taskService.CreateTask(ctx, task)
+		requires.NoError(err, task.Title)
+	}
+
+	list, err := taskService.ReadTaskList(ctx, entity.NewTaskProperty("07.01.2025", 10))
+	requires.NoError(err)
+	requires.Len(list.TasksResp, 3)
+
+	asserts.Equal("1", list.TasksResp[0].ID)
+	asserts.False(list.TasksResp[0].Projected, "stored date of task")
+	for i, id := range []string{"2", "6"} {
+		task := list.TasksResp[i+1]
+		asserts.Equal(id, task.ID)
+		asserts.Equal("20250107", task.Date, "date of occurrence")
+		asserts.True(task.Projected, "occurrence by rule")
+	}
+	asserts.Equal("09:00", list.TasksResp[2].Time, "first occurrence of day")
+
+	// stored task is not changed
+	task, err := taskService.ReadTask(ctx, 2, nil)
+	requires.NoError(err)
+	asserts.Equal("20241231", task.Date)
+
+	// search by word - without projection
+	list, err = taskService.ReadTaskList(ctx, entity.NewTaskProperty("weekly", 10))
+	requires.NoError(err)
+	requires.Len(list.TasksResp, 1)
+	asserts.False(list.TasksResp[0].Projected)
+}
+
+func Test_taskService_Pages(t *testing.T) {
+	asserts := assert.New(t)
+	requires := require.New(t)
+
+	clk := clock.Fixed(time.Date(2024, 12, 25, 10, 0, 0, 0, time.UTC))
+	cfg := &config.Config{TaskNextDate: nextdate.AlgorithmNextDate}
+
+	taskService, err := NewTaskService(cfg, mock.NewMockTaskStore(), clk)
+	requires.NoError(err)
+
+	ctx := context.Background()
+
+	for _, task := range []model.TaskModel{
+		{Date: "20250107", Title: "stored"},                                          // 1
+		{Date: "20241231", Title: "weekly", Repeat: "w 2"},                           // 2
+		{Date: "20241226", Time: "09:00", Title: "water", Repeat: "h 4 09:00-18:00"}, // 3
+		{Date: "20250107", Time: "08:00", Title: "stored with time"},                 // 4
+		{Date: "20250108", Title: "next day"},                                        // 5
+	} {
+		_, err := taskService.CreateTask(ctx, task)
+		requires.NoError(err, task.Title)
+	}
+
+	// walk - ID of tasks on all pages and number of pages
+	walk := func(search string, limit uint, filter *entity.TaskFilter) ([]string, int) {
+		var ids []string
+		cursor := (*entity.TaskCursor)(nil)
+		for pages := 1; pages < 10; pages++ {
+			list, err := taskService.ReadTaskList(ctx, entity.NewTaskProperty(search, limit).After(cursor).Filter(filter))
+			requires.NoError(err)
+			for _, task := range list.TasksResp {
+				ids = append(ids, task.ID)
+			}
+			if list.NextCursor == "" {
+				return ids, pages
+			}
+			var ok bool
+			cursor, ok = entity.ParseTaskCursor(list.NextCursor)
+			requires.True(ok, "cursor of response")
+		}
+		requires.FailNow("too many pages")
+		return nil, 0
+	}
+
+	// stored and projected tasks of date: whole day (1, 2), then by time (4 - 08:00, 3 - 09:00)
+	ids, pages := walk("07.01.2025", 2, nil)
+	asserts.Equal([]string{"1", "2", "4", "3"}, ids, "tasks of date")
+	asserts.Equal(3, pages, "last full page -> next page is empty")
+
+	ids, pages = walk("07.01.2025", 3, nil)
+	asserts.Equal([]string{"1", "2", "4", "3"}, ids, "tasks of date")
+	asserts.Equal(2, pages)
+
+	ids, pages = walk("", 2, nil)
+	asserts.Equal([]string{"3", "2", "1", "4", "5"}, ids, "stored tasks by date, time and ID")
+	asserts.Equal(3, pages)
+
+	ids, pages = walk("stored", 1, nil)
+	asserts.Equal([]string{"1"}, ids, "search by word - order by relevance has no next page")
+	asserts.Equal(1, pages)
+
+	byDate := entity.NewTaskFilter(time.Time{}, time.Time{}, time.Time{}, "", "", entity.SortDate, false)
+	ids, _ = walk("stored", 1, byDate)
+	asserts.Equal([]string{"1", "4"}, ids, "search by word by pages of date")
+}
+
+func Test_taskService_Filters(t *testing.T) {
+	asserts := assert.New(t)
+	requires := require.New(t)
+
+	clk := clock.Fixed(time.Date(2024, 12, 25, 10, 0, 0, 0, time.UTC))
+	cfg := &config.Config{TaskNextDate: nextdate.AlgorithmNextDate}
+
+	taskService, err := NewTaskService(cfg, mock.NewMockTaskStore(), clk)
+	requires.NoError(err)
+
+	ctx := context.Background()
+
+	for _, task := range []model.TaskModel{
+		{Date: "20250107", Title: "stored"},                                          // 1
+		{Date: "20241231", Title: "weekly", Repeat: "w 2"},                           // 2
+		{Date: "20241226", Time: "09:00", Title: "water", Repeat: "h 4 09:00-18:00"}, // 3
+		{Date: "20250107", Time: "08:00", Title: "stored with time"},                 // 4
+	} {
+		_, err := taskService.CreateTask(ctx, task)
+		requires.NoError(err, task.Title)
+	}
+
+	// walk - ID of tasks of date on all pages of two tasks, projected tasks are filtered and ordered as stored
+	walk := func(filter *entity.TaskFilter) []string {
+		var ids []string
+		cursor := (*entity.TaskCursor)(nil)
+		for pages := 1; pages < 10; pages++ {
+			property := entity.NewTaskProperty("07.01.2025", 2).After(cursor).Filter(filter)
+			list, err := taskService.ReadTaskList(ctx, property)
+			requires.NoError(err)
+			for _, task := range list.TasksResp {
+				ids = append(ids, task.ID)
+			}
+			if list.NextCursor == "" {
+				return ids
+			}
+			var ok bool
+			cursor, ok = entity.ParseTaskCursor(list.NextCursor)
+			requires.True(ok, "cursor of response")
+		}
+		requires.FailNow("too many pages")
+		return nil
+	}
+	none := time.Time{}
+
+	asserts.Equal([]string{"2", "3", "4", "1"},
+		walk(entity.NewTaskFilter(none, none, none, "", "", entity.SortTitle, true)), "by title desc")
+	asserts.Equal([]string{"1", "4"},
+		walk(entity.NewTaskFilter(none, none, none, entity.KindOnce, "", "", false)), "once")
+	asserts.Equal([]string{"3", "2"},
+		walk(entity.NewTaskFilter(none, none, none, entity.KindRecurring, "", entity.SortID, true)), "recurring by id desc")
+	asserts.Empty(walk(entity.NewTaskFilter(none, none, clk.Now(), "", "", "", false)), "date is not overdue")
+}
+
+func Test_taskService_History(t *testing.T) {
+	asserts := assert.New(t)
+	requires := require.New(t)
+
+	clk := clock.Fixed(time.Date(2024, 12, 25, 10, 0, 0, 0, time.UTC))
+	cfg := &config.Config{TaskNextDate: nextdate.AlgorithmNextDate}
+
+	taskService, err := NewTaskService(cfg, mock.NewMockTaskStore(), clk)
+	requires.NoError(err)
+
+	ctx := context.Background()
+
+	for _, task := range []model.TaskModel{
+		{Date: "20241225", Time: "07:00", Title: "run", Repeat: "d 1"}, // 1
+		{Date: "20241220", Title: "report"},                            // 2
+		{Date: "20241230", Title: "not done"},                          // 3
+	} {
+		_, err := taskService.CreateTask(ctx, task)
+		requires.NoError(err, task.Title)
+	}
+
+	requires.NoError(taskService.DoneTask(ctx, 1, "5 km"))
+	requires.NoError(taskService.DoneTask(ctx, 1, ""))
+	requires.NoError(taskService.DoneTask(ctx, 2, "sent"))
+
+	history, err := taskService.ReadTaskHistory(ctx, 1)
+	requires.NoError(err)
+	asserts.Equal("1", history.TaskID)
+	requires.Len(history.Completions, 2)
+	asserts.Equal(serializer.CompletionResponse{
+		ID: "1", Date: "20241225", Time: "07:00", DoneAt: "2024-12-25T10:00:00Z", Note: "5 km",
+	}, history.Completions[0], "scheduled date before done")
+	asserts.Equal("20241226", history.Completions[1].Date, "next date after first done")
+
+	history, err = taskService.ReadTaskHistory(ctx, 2)
+	requires.NoError(err, "deleted task keeps history")
+	requires.Len(history.Completions, 1)
+	asserts.Equal("sent", history.Completions[0].Note)
+
+	history, err = taskService.ReadTaskHistory(ctx, 3)
+	requires.NoError(err)
+	asserts.Empty(history.Completions, "task without completions")
+
+	_, err = taskService.ReadTaskHistory(ctx, 99)
+	asserts.ErrorIs(err, ErrCaseTaskNotFound)
+	_, err = taskService.ReadTaskHistory(ctx, 0)
+	asserts.ErrorIs(err, ErrCaseTaskZeroID)
+	asserts.ErrorIs(taskService.DoneTask(ctx, 2, ""), ErrCaseTaskNotFound, "deleted task")
+}
+
+func Test_taskService_Agenda(t *testing.T) {
+	asserts := assert.New(t)
+	requires := require.New(t)
+
+	clk := clock.Fixed(time.Date(2024, 12, 25, 10, 0, 0, 0, time.UTC)) // Wednesday
+	cfg := &config.Config{TaskNextDate: nextdate.AlgorithmNextDate}
+
+	taskService, err := NewTaskService(cfg, mock.NewMockTaskStore(), clk)
+	requires.NoError(err)
+
+	ctx := context.Background()
+
+	for _, task := range []model.TaskModel{
+		{Date: "20241227", Title: "once"},                                                     // 1
+		{Date: "20241226", Title: "every 2 days", Repeat: "d 2", Exdates: "20241230"},         // 2
+		{Date: "20241226", Time: "17:00", Title: "water", Repeat: "h 4 09:00-18:00"},          // 3
+		{Date: "20241226", Title: "after done", Repeat: "d 1", Mode: nextdate.ModeCompletion}, // 4
+		{Date: "20241226", Title: "twice", Repeat: "d 1 count 2"},                             // 5
+		{Date: "20250110", Title: "later"},                                                    // 6
+	} {
+		_, err := taskService.CreateTask(ctx, task)
+		requires.NoError(err, task.Title)
+	}
+
+	from := time.Date(2024, 12, 26, 0, 0, 0, 0, time.UTC)
+	agenda, err := taskService.AgendaTask(ctx, entity.NewTaskAgenda(from, from.AddDate(0, 0, 6)))
+	requires.NoError(err)
+	requires.Len(agenda.Days, 7)
+
+	ids := func(day serializer.AgendaDayResponse) []string {
+		var arrID []string
+		for _, task := range day.Tasks {
+			arrID = append(arrID, task.ID)
+		}
+		return arrID
+	}
+	asserts.Equal([]string{"2", "4", "5", "3"}, ids(agenda.Days[0]), "20241226 - stored dates")
+	asserts.Equal([]string{"1", "5", "3"}, ids(agenda.Days[1]), "20241227 - last of count")
+	asserts.Equal([]string{"2", "3"}, ids(agenda.Days[2]), "20241228")
+	asserts.Equal([]string{"3"}, ids(agenda.Days[4]), "20241230 - exception date")
+	asserts.Equal([]string{"2", "3"}, ids(agenda.Days[6]), "20250101")
+
+	asserts.Equal("17:00", agenda.Days[0].Tasks[3].Time)
+	asserts.False(agenda.Days[0].Tasks[3].Projected, "stored date of task")
+	asserts.Equal("09:00", agenda.Days[1].Tasks[2].Time, "first occurrence of day")
+	asserts.True(agenda.Days[1].Tasks[2].Projected, "occurrence by rule")
+	asserts.False(agenda.Days[1].Tasks[0].Projected)
+	asserts.Empty(agenda.Days[2].Tasks[0].Time, "task for whole day")
+}
+
+// task of algorithm added by 'nextdate.Register' - repeat is not custom grammar or RRULE
+func Test_taskService_RegisteredAlgorithm(t *testing.T) {
+	asserts := assert.New(t)
+	requires := require.New(t)
+
+	fortnight := func(now time.Time, dstart string, repeat string) (string, error) {
+		return nextdate.NextDate(now, dstart, "d 14")
+	}
+	if err := nextdate.Register("fortnight", fortnight); err != nil {
+		requires.ErrorIs(err, nextdate.ErrNextDateAlgorithmExist)
+	}
+
+	clk := clock.Fixed(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC))
+	cfg := &config.Config{TaskNextDate: nextdate.AlgorithmNextDate}
+
+	taskService, err := NewTaskService(cfg, mock.NewMockTaskStore(), clk)
+	requires.NoError(err)
+
+	ctx := context.Background()
+
+	taskID, err := taskService.CreateTask(ctx, model.TaskModel{
+		Date:      "20240101",
+		Title:     "clean",
+		Repeat:    "every other week",
+		Algorithm: "fortnight",
+	})
+	requires.NoError(err)
+	id, err := strconv.ParseUint(taskID.ID, 10, 64)
+	requires.NoError(err)
+
+	preview, err := taskService.PreviewTask(ctx,
+		entity.NewTaskPreview(uint(id), model.TaskModel{}, clk.Now(), time.Time{}, 3, ""))
+	requires.NoError(err)
+	asserts.Equal([]string{"20240101", "20240115", "20240129"}, preview.Dates)
+
+	requires.NoError(taskService.DoneTask(ctx, uint(id), ""))
+	task, err := taskService.ReadTask(ctx, uint(id), nil)
+	requires.NoError(err)
+	asserts.Equal("20240115", task.Date)
+	asserts.Equal("every other week", task.Repeat)
+}
+
+// RRULE without date -> ErrNextDateWrongRepeat (400), not internal error
+func Test_taskService_RRuleWithoutDate(t *testing.T) {
+	requires := require.New(t)
+
+	clk := clock.Fixed(time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC))
+	cfg := &config.Config{TaskNextDate: nextdate.AlgorithmNextDate}
+
+	taskService, err := NewTaskService(cfg, mock.NewMockTaskStore(), clk)
+	requires.NoError(err)
+
+	ctx := context.Background()
+
+	for _, repeat := range []string{
+		"FREQ=MONTHLY;BYMONTHDAY=1;BYDAY=-1MO",
+		"FREQ=MONTHLY;INTERVAL=12;BYMONTH=3",
+	} {
+		taskID, err := taskService.CreateTask(ctx, model.TaskModel{Date: "20240105", Title: "never", Repeat: repeat})
+		requires.ErrorIs(err, nextdate.ErrNextDateWrongRepeat, repeat)
+		requires.Nil(taskID, repeat)
+	}
+}
@@ -18,13 +18,25 @@ func (h HandlerModel) apiRoutes(sheduler shedulerCase) *http.ServeMux {
 	mux.HandleFunc("POST /signin", Login(sheduler))
 
 	mux.HandleFunc("GET /task", AuthZ(sheduler, TaskRetrieve(sheduler)))
-	mux.HandleFunc("POST /task", AuthZ(sheduler, TaskNew(sheduler)))
-	mux.HandleFunc("PUT /task", AuthZ(sheduler, TaskChange(sheduler)))
+	mux.HandleFunc("POST /task", AuthZ(sheduler, TaskNew(sheduler, sheduler)))
+	mux.HandleFunc("PUT /task", AuthZ(sheduler, TaskChange(sheduler, sheduler)))
 	mux.HandleFunc("DELETE /task", AuthZ(sheduler, TaskRemove(sheduler)))
 	mux.HandleFunc("POST /task/done", AuthZ(sheduler, TaskDone(sheduler)))
-
-	mux.HandleFunc("GET /tasks", AuthZ(sheduler, TaskRetriveList(sheduler)))
-
-	mux.HandleFunc("GET /nextdate", TestNextDate)
+	mux.HandleFunc("POST /task/skip", AuthZ(sheduler, TaskSkip(sheduler)))
+	mux.HandleFunc("GET /task/history", AuthZ(sheduler, TaskHistory(sheduler)))
+
+	mux.HandleFunc("GET /tasks", AuthZ(sheduler, TaskRetriveList(sheduler, sheduler)))
+	mux.HandleFunc("GET /agenda", AuthZ(sheduler, TaskAgenda(sheduler, sheduler)))
+
+	mux.HandleFunc("GET /nextdate", TestNextDate(sheduler))
+	mux.HandleFunc("GET /nextdate/describe", DescribeRepeat)
+	mux.HandleFunc("GET /nextdates", AuthZ(sheduler, TaskPreview(sheduler, sheduler, sheduler)))
+
+	// only with TODO_DEBUG_CLOCK=true, othercase - 404
+	if debugClock {
+		mux.HandleFunc("GET /debug/clock", AuthZ(sheduler, ClockRetrieve(sheduler)))
+		mux.HandleFunc("PUT /debug/clock", AuthZ(sheduler, ClockChange(sheduler)))
+		mux.HandleFunc("DELETE /debug/clock", AuthZ(sheduler, ClockReset(sheduler)))
+	}
 	return mux
 }
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/lib/timezone"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/services"
 
 	"github.com/Ekvo/yandex-practicum-go-final-project/pkg/common"
@@ -56,3 +57,27 @@ func AuthZ(authCase rulesForAuthZ, next http.HandlerFunc) http.HandlerFunc {
 		next(w, r)
 	}
 }
+
+// Timezone - time zone of request for "today" and "now" of tasks (lib/timezone/timezone.go)
+//
+// param "tz" or header "X-Timezone": IANA name ("Europe/Moscow") or offset ("+03:00")
+// not specified -> default time zone from config
+// unknown time zone -> status 400
+func Timezone(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get(timezone.Param)
+		if name == "" {
+			name = r.Header.Get(timezone.Header)
+		}
+		if name == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		loc, err := timezone.Load(name)
+		if err != nil {
+			common.EncodeJSON(w, http.StatusBadRequest, common.NewError(err))
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(timezone.WithLocation(r.Context(), loc)))
+	})
+}
@@ -49,9 +49,9 @@ func Login(loginService services.LoginValidPasswordCase) http.HandlerFunc {
 	}
 }
 
-func TaskNew(taskService services.TaskCreateCase) http.HandlerFunc {
+func TaskNew(taskService services.TaskCreateCase, algorithmService services.TaskAlgorithmCase) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		deserialize := deserializer.NewTaskDecode()
+		deserialize := deserializer.NewTaskDecode(algorithmService.DefaultAlgorithm())
 		if err := deserialize.Decode(r); err != nil {
 			common.EncodeJSON(w, http.StatusBadRequest, common.NewError(err))
 			return
@@ -80,7 +80,8 @@ func TaskRetrieve(taskService services.TaskReadCase) http.HandlerFunc {
 			common.EncodeJSON(w, http.StatusBadRequest, common.NewError(ErrTransportInvalidParam))
 			return
 		}
-		task, err := taskService.ReadTask(r.Context(), uint(id))
+		format := entity.NewTaskFormat(r.URL.Query().Get("rrule"), r.URL.Query().Get("describe"))
+		task, err := taskService.ReadTask(r.Context(), uint(id), format)
 		if err != nil {
 			code := 0
 			if errors.Is(err, usecase.ErrCaseTaskNotFound) {
@@ -97,9 +98,9 @@ func TaskRetrieve(taskService services.TaskReadCase) http.HandlerFunc {
 	}
 }
 
-func TaskChange(taskService services.TaskUpdateCase) http.HandlerFunc {
+func TaskChange(taskService services.TaskUpdateCase, algorithmService services.TaskAlgorithmCase) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		deserialize := deserializer.NewTaskDecode()
+		deserialize := deserializer.NewTaskDecode(algorithmService.DefaultAlgorithm())
 		if err := deserialize.Decode(r); err != nil {
 			common.EncodeJSON(w, http.StatusBadRequest, common.NewError(err))
 			return
@@ -143,6 +144,7 @@ func TaskRemove(taskService services.TaskDeleteCase) http.HandlerFunc {
 	}
 }
 
+// TaskDone - done task (/api/task/done?id=1), optional body {"note":"..."} is saved to history
 func TaskDone(taskService services.TaskDoneCase) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id, err := strconv.ParseUint(r.URL.Query().Get("id"), 10, 64)
@@ -150,7 +152,12 @@ func TaskDone(taskService services.TaskDoneCase) http.HandlerFunc {
 			common.EncodeJSON(w, http.StatusBadRequest, common.NewError(ErrTransportInvalidParam))
 			return
 		}
-		if err := taskService.DoneTask(r.Context(), uint(id)); err != nil {
+		deserialize := deserializer.NewDoneDecode()
+		if err := deserialize.Decode(r); err != nil {
+			common.EncodeJSON(w, http.StatusBadRequest, common.NewError(err))
+			return
+		}
+		if err := taskService.DoneTask(r.Context(), uint(id), deserialize.Note); err != nil {
 			code := 0
 			if errors.Is(err, usecase.ErrCaseTaskNotFound) {
 				code = http.StatusNotFound
@@ -166,11 +173,65 @@ func TaskDone(taskService services.TaskDoneCase) http.HandlerFunc {
 	}
 }
 
-func TaskRetriveList(taskService services.TaskReadCase) http.HandlerFunc {
+// TaskHistory - completions of task (/api/task/history?id=1), deleted task keeps its history
+func TaskHistory(taskService services.TaskHistoryCase) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		param := r.URL.Query().Get("search")
-		taskProperty := entity.NewTaskProperty(param, 123)
-		tasks, err := taskService.ReadTaskList(r.Context(), taskProperty)
+		id, err := strconv.ParseUint(r.URL.Query().Get("id"), 10, 64)
+		if err != nil {
+			common.EncodeJSON(w, http.StatusBadRequest, common.NewError(ErrTransportInvalidParam))
+			return
+		}
+		history, err := taskService.ReadTaskHistory(r.Context(), uint(id))
+		if err != nil {
+			code := 0
+			if errors.Is(err, usecase.ErrCaseTaskNotFound) {
+				code = http.StatusNotFound
+			} else if errors.Is(err, services.ErrServicesInternalError) {
+				code = http.StatusInternalServerError
+			} else {
+				code = http.StatusUnprocessableEntity
+			}
+			common.EncodeJSON(w, code, common.NewError(err))
+			return
+		}
+		common.EncodeJSON(w, http.StatusOK, history)
+	}
+}
+
+// TaskSkip - move recurring task past its current occurrence, occurrence is not done
+func TaskSkip(taskService services.TaskSkipCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseUint(r.URL.Query().Get("id"), 10, 64)
+		if err != nil {
+			common.EncodeJSON(w, http.StatusBadRequest, common.NewError(ErrTransportInvalidParam))
+			return
+		}
+		if err := taskService.SkipTask(r.Context(), uint(id)); err != nil {
+			code := 0
+			if errors.Is(err, usecase.ErrCaseTaskNotFound) {
+				code = http.StatusNotFound
+			} else if errors.Is(err, services.ErrServicesInternalError) {
+				code = http.StatusInternalServerError
+			} else {
+				code = http.StatusUnprocessableEntity
+			}
+			common.EncodeJSON(w, code, common.NewError(err))
+			return
+		}
+		common.EncodeJSON(w, http.StatusOK, common.Message{})
+	}
+}
+
+// TaskRetriveList - page of tasks (/api/tasks?search=word&limit=20&cursor=next_cursor&sort=title)
+// filters see (internal/services/deserializer/tasklistdecode.go), "today" of 'overdue' - by clock
+func TaskRetriveList(taskService services.TaskReadCase, clockService services.ClockCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		deserialize := deserializer.NewTaskListDecode(clockService.Now(r.Context()))
+		if err := deserialize.Decode(r); err != nil {
+			common.EncodeJSON(w, http.StatusBadRequest, common.NewError(err))
+			return
+		}
+		tasks, err := taskService.ReadTaskList(r.Context(), deserialize.Entity())
 		if err != nil {
 			common.EncodeJSON(w, http.StatusInternalServerError, common.NewError(err))
 			return
@@ -179,26 +240,146 @@ func TaskRetriveList(taskService services.TaskReadCase) http.HandlerFunc {
 	}
 }
 
-func TestNextDate(w http.ResponseWriter, r *http.Request) {
-	timeNowStr := r.URL.Query().Get("now")
-	dstart := r.URL.Query().Get("date")
+func TaskPreview(
+	taskService services.TaskPreviewCase,
+	clockService services.ClockCase,
+	algorithmService services.TaskAlgorithmCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		deserialize := deserializer.NewPreviewDecode(clockService.Now(r.Context()), algorithmService.DefaultAlgorithm())
+		if err := deserialize.Decode(r); err != nil {
+			common.EncodeJSON(w, http.StatusBadRequest, common.NewError(err))
+			return
+		}
+		dates, err := taskService.PreviewTask(r.Context(), deserialize.Entity())
+		if err != nil {
+			code := 0
+			if errors.Is(err, usecase.ErrCaseTaskNotFound) {
+				code = http.StatusNotFound
+			} else if errors.Is(err, services.ErrServicesInternalError) {
+				code = http.StatusInternalServerError
+			} else {
+				code = http.StatusUnprocessableEntity
+			}
+			common.EncodeJSON(w, code, common.NewError(err))
+			return
+		}
+		common.EncodeJSON(w, http.StatusOK, dates)
+	}
+}
+
+// TaskAgenda - occurrences of tasks by days (/api/agenda?from=20240101&to=20240107)
+func TaskAgenda(taskService services.TaskAgendaCase, clockService services.ClockCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		deserialize := deserializer.NewAgendaDecode(clockService.Now(r.Context()))
+		if err := deserialize.Decode(r); err != nil {
+			common.EncodeJSON(w, http.StatusBadRequest, common.NewError(err))
+			return
+		}
+		agenda, err := taskService.AgendaTask(r.Context(), deserialize.Entity())
+		if err != nil {
+			common.EncodeJSON(w, http.StatusInternalServerError, common.NewError(err))
+			return
+		}
+		common.EncodeJSON(w, http.StatusOK, agenda)
+	}
+}
+
+// TestNextDate - next date of repeat as text, empty param 'now' - "now" of clock
+func TestNextDate(clockService services.ClockCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		timeNowStr := r.URL.Query().Get("now")
+		dstart := r.URL.Query().Get("date")
+		repeat := r.URL.Query().Get("repeat")
+		now, err := time.Parse(model.DateFormat, timeNowStr)
+		if err != nil && timeNowStr != "" {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if timeNowStr == "" {
+			now = clockService.Now(r.Context())
+		}
+		newDate, err := nextdate.NextDate(now, dstart, repeat)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, err = w.Write([]byte(newDate))
+		if err != nil {
+			log.Printf("route: http.ResponseWriter.Write error - %v", err)
+		}
+	}
+}
+
+// DescribeRepeat - repeat rule as sentence, param 'lang' - "en" (default) or "ru"
+func DescribeRepeat(w http.ResponseWriter, r *http.Request) {
 	repeat := r.URL.Query().Get("repeat")
-	now, err := time.Parse(model.DateFormat, timeNowStr)
-	if err != nil && timeNowStr != "" {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+	lang := r.URL.Query().Get("lang")
+	if lang == "" {
+		lang = nextdate.LanguageEnglish
 	}
-	if timeNowStr == "" {
-		now = time.Now()
+	if !nextdate.IsLanguage(lang) {
+		http.Error(w, nextdate.ErrNextDateUnknownLanguage.Error(), http.StatusBadRequest)
+		return
 	}
-	newDate, err := nextdate.NextDate(now, dstart, repeat)
+	description, err := nextdate.Describe(repeat, lang)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
 		return
 	}
 	w.WriteHeader(http.StatusOK)
-	_, err = w.Write([]byte(newDate))
+	_, err = w.Write([]byte(description))
 	if err != nil {
 		log.Printf("route: http.ResponseWriter.Write error - %v", err)
 	}
 }
+
+// ClockRetrieve - "now" of server and state of debug clock (/api/debug/clock)
+func ClockRetrieve(clockService services.ClockDebugCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		state, err := clockService.ReadClock(r.Context())
+		if err != nil {
+			common.EncodeJSON(w, http.StatusInternalServerError, common.NewError(err))
+			return
+		}
+		common.EncodeJSON(w, http.StatusOK, state)
+	}
+}
+
+// ClockChange - freeze or move "now" of server, empty body '{}' - reset
+func ClockChange(clockService services.ClockDebugCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		deserialize := deserializer.NewClockDecode()
+		if err := deserialize.Decode(r); err != nil {
+			common.EncodeJSON(w, http.StatusBadRequest, common.NewError(err))
+			return
+		}
+		changeClock(w, r, clockService, deserialize.Entity())
+	}
+}
+
+// ClockReset - "now" of server is real time
+func ClockReset(clockService services.ClockDebugCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		changeClock(w, r, clockService, entity.NewClockSetting(time.Time{}, 0))
+	}
+}
+
+func changeClock(
+	w http.ResponseWriter,
+	r *http.Request,
+	clockService services.ClockDebugCase,
+	setting *entity.ClockSetting) {
+	state, err := clockService.ChangeClock(r.Context(), setting)
+	if err != nil {
+		code := 0
+		if errors.Is(err, usecase.ErrCaseClockNotDebug) {
+			code = http.StatusForbidden
+		} else {
+			code = http.StatusInternalServerError
+		}
+		common.EncodeJSON(w, code, common.NewError(err))
+		return
+	}
+	common.EncodeJSON(w, http.StatusOK, state)
+}
@@ -16,6 +16,7 @@ type Transport struct {
 
 func NewTransport(cfg *config.Config) Transport {
 	pathWeb = cfg.PathFilesWeb
+	debugClock = cfg.DebugClock
 	mux := http.NewServeMux()
 	return Transport{ServeMux: mux, Srv: server.InitSRV(cfg, mux)}
 }
@@ -24,10 +25,14 @@ func NewTransport(cfg *config.Config) Transport {
 // set with help config.Config
 var pathWeb = ""
 
+// routes of debug clock (/api/debug/clock) are added, set with help config.Config
+var debugClock = false
+
 type shedulerCase interface {
 	usecase.LoginService
 	usecase.AuthService
 	usecase.TaskService
+	usecase.ClockService
 }
 
 // Routes - logic of application routes
@@ -35,7 +40,7 @@ func (r Transport) Routes(sheduler shedulerCase) {
 	muxTask := NewHandlerModel().apiRoutes(sheduler)
 
 	r.Handle("/", http.FileServer(http.Dir(pathWeb)))
-	r.Handle("/api/", http.StripPrefix("/api", muxTask))
+	r.Handle("/api/", http.StripPrefix("/api", Timezone(muxTask)))
 }
 
 // Start - set all routes and 'ListenAndServe' see (/internal/server/server.go)
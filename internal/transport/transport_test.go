@@ -15,6 +15,7 @@ import (
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/config"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/database/mock"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/datauser"
+	"github.com/Ekvo/yandex-practicum-go-final-project/internal/lib/clock"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/lib/jwtsign"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/services"
 	"github.com/Ekvo/yandex-practicum-go-final-project/internal/services/usecase"
@@ -90,9 +91,9 @@ var dataForRequest = []struct {
 		method:      http.MethodPost,
 		url:         `/api/task`,
 		body:        `{"date":"20240203","title":"Summarize","comment":"","repeat": "k 1"}`,
-		resCode:     http.StatusUnprocessableEntity,
-		resRegexp:   `{"error":"invalid repeat data"}`,
-		msg:         `wrong task bad repeat, status 422, return error`,
+		resCode:     http.StatusBadRequest,
+		resRegexp:   `{"error":"taskdecode: error - {repeat:invalid repeat data at position 0: unknown flag 'k'}"}`,
+		msg:         `wrong task bad repeat, status 400, return error with position`,
 	},
 	{ //8
 		description: `new task invalid`,
@@ -109,7 +110,7 @@ var dataForRequest = []struct {
 		url:         `/api/task?id=1`,
 		body:        ``,
 		resCode:     http.StatusOK,
-		resRegexp:   `{"id":"1","date":"[0-9]{8}","title":"Summarize","comment":"my comment","repeat":"d 5"}`,
+		resRegexp:   `{"id":"1","date":"[0-9]{8}","title":"Summarize","comment":"my comment","repeat":"d 5","algorithm":"nextdate","mode":"schedule"}`,
 		msg:         `find task, status 200, return JSON TaskResponse`,
 	},
 	{ //10
@@ -154,7 +155,7 @@ var dataForRequest = []struct {
 		url:         `/api/task`,
 		body:        `{"date":"fff","title":"T","comment":"what it is","repeat":"p 765"}`,
 		resCode:     http.StatusBadRequest,
-		resRegexp:   `{"error":"taskdecode: error - {date:invalid date format}"}`,
+		resRegexp:   `{"error":"taskdecode: error - {date:invalid date format},{repeat:invalid repeat data at position 0: unknown flag 'p'}"}`,
 		msg:         `bad task, status 422, return JSON error`,
 	},
 	{ //15
@@ -180,9 +181,9 @@ var dataForRequest = []struct {
 		method:      http.MethodPut,
 		url:         `/api/task`,
 		body:        `{"id":"1","date": "20240201","title": "new title","comment": "change comment","repeat": "d 401"}`,
-		resCode:     http.StatusUnprocessableEntity,
-		resRegexp:   `{"error":"invalid repeat data"}`,
-		msg:         `wrong update task, status 422, return error`,
+		resCode:     http.StatusBadRequest,
+		resRegexp:   `{"error":"taskdecode: error - {repeat:invalid repeat data at position 2: number of days must be 1..400}"}`,
+		msg:         `wrong update task, status 400, return error with position`,
 	},
 	{ //18
 		description: `task list valid`,
@@ -256,6 +257,384 @@ var dataForRequest = []struct {
 		resRegexp:   `{"error":"invalid param"}`,
 		msg:         `wrong task delete, status 400, return JSON error`,
 	},
+	{ //26
+		description: `preview dates of rule valid`,
+		method:      http.MethodGet,
+		url:         `/api/nextdates?now=20240110&date=20240110&repeat=d+7&n=3`,
+		body:        ``,
+		resCode:     http.StatusOK,
+		resRegexp:   `^{"dates":\["20240110","20240117","20240124"\]}`,
+		msg:         `dates of ad-hoc rule, status 200, return JSON with dates`,
+	},
+	{ //27
+		description: `preview dates of rule in window valid`,
+		method:      http.MethodGet,
+		url:         `/api/nextdates?now=20240110&date=20240101&repeat=w+1&to=20240131`,
+		body:        ``,
+		resCode:     http.StatusOK,
+		resRegexp:   `^{"dates":\["20240115","20240122","20240129"\]}`,
+		msg:         `all dates of window, status 200, return JSON with dates`,
+	},
+	{ //28
+		description: `wrong preview (invalid params)`,
+		method:      http.MethodGet,
+		url:         `/api/nextdates?id=1&repeat=d+1&n=0`,
+		body:        ``,
+		resCode:     http.StatusBadRequest,
+		resRegexp:   `{"error":"previewdecode: error - {id:id and repeat can't be used together},{n:out of range}"}`,
+		msg:         `wrong params, status 400, return JSON error`,
+	},
+	{ //29
+		description: `preview task not found`,
+		method:      http.MethodGet,
+		url:         `/api/nextdates?id=1`,
+		body:        ``,
+		resCode:     http.StatusNotFound,
+		resRegexp:   `{"error":"task not found"}`,
+		msg:         `task not exist, status 404, return JSON error`,
+	},
+	{ //30
+		description: `new task valid`,
+		method:      http.MethodPost,
+		url:         `/api/task`,
+		body:        `{"date":"20990101","title":"Plan","comment":"","repeat":"y"}`,
+		resCode:     http.StatusCreated,
+		resRegexp:   `{"id":"3"}`,
+		msg:         `save new task, status 201, return ID`,
+	},
+	{ //31
+		description: `preview dates of task valid`,
+		method:      http.MethodGet,
+		url:         `/api/nextdates?id=3&n=3`,
+		body:        ``,
+		resCode:     http.StatusOK,
+		resRegexp:   `^{"id":"3","dates":\["20990101","21000101","21010101"\]}`,
+		msg:         `dates of stored task, status 200, return JSON with ID and dates`,
+	},
+	{ //32
+		description: `get task with description valid`,
+		method:      http.MethodGet,
+		url:         `/api/task?id=3&describe=ru`,
+		body:        ``,
+		resCode:     http.StatusOK,
+		resRegexp:   `{"id":"3","date":"20990101","title":"Plan","comment":"","repeat":"y","algorithm":"nextdate","mode":"schedule","description":"каждый год"}`,
+		msg:         `task with description of repeat, status 200, return JSON with task`,
+	},
+	{ //33
+		description: `describe repeat valid`,
+		method:      http.MethodGet,
+		url:         `/api/nextdate/describe?repeat=m+-1,15+1,4,7,10`,
+		body:        ``,
+		resCode:     http.StatusOK,
+		resRegexp:   `^on the 15th and the last day of Jan, Apr, Jul and Oct$`,
+		msg:         `description in english, status 200, return text`,
+	},
+	{ //34
+		description: `wrong describe repeat (unknown language)`,
+		method:      http.MethodGet,
+		url:         `/api/nextdate/describe?repeat=d+1&lang=de`,
+		body:        ``,
+		resCode:     http.StatusBadRequest,
+		resRegexp:   `unknown language`,
+		msg:         `unknown language, status 400, return text error`,
+	},
+	{ //35
+		description: `preview dates with description valid`,
+		method:      http.MethodGet,
+		url:         `/api/nextdates?now=20240101&date=20240101&repeat=w+1&n=1&describe=ru`,
+		body:        ``,
+		resCode:     http.StatusOK,
+		resRegexp:   `^{"dates":\["20240101"\],"description":"по понедельникам"}`,
+		msg:         `dates and description, status 200, return JSON`,
+	},
+	{ //36
+		description: `new task invalid mode`,
+		method:      http.MethodPost,
+		url:         `/api/task`,
+		body:        `{"date":"20240201","title":"Water plants","repeat":"d 7","mode":"sometimes"}`,
+		resCode:     http.StatusBadRequest,
+		resRegexp:   `{"error":"taskdecode: error - {mode:unknown mode}"}`,
+		msg:         `bad mode of repeat, status 400, return JSON error`,
+	},
+	{ //37
+		description: `new task with time valid`,
+		method:      http.MethodPost,
+		url:         `/api/task`,
+		body:        `{"date":"20990101","title":"Drink water","repeat":"h 4 09:00-18:00"}`,
+		resCode:     http.StatusCreated,
+		resRegexp:   `{"id":"4"}`,
+		msg:         `save new task, status 201, return ID`,
+	},
+	{ //38
+		description: `get task with time valid`,
+		method:      http.MethodGet,
+		url:         `/api/task?id=4`,
+		body:        ``,
+		resCode:     http.StatusOK,
+		resRegexp:   `{"id":"4","date":"20990101","time":"09:00","title":"Drink water","comment":"","repeat":"h 4 09:00-18:00","algorithm":"nextdate","mode":"schedule"}`,
+		msg:         `task with time, status 200, return JSON with task`,
+	},
+	{ //39
+		description: `new task invalid time`,
+		method:      http.MethodPost,
+		url:         `/api/task`,
+		body:        `{"date":"20240201","time":"25:00","title":"Drink water","repeat":"h 4"}`,
+		resCode:     http.StatusBadRequest,
+		resRegexp:   `{"error":"taskdecode: error - {time:invalid time format}"}`,
+		msg:         `bad time, status 400, return JSON error`,
+	},
+	{ //40
+		description: `next date in time zone of request valid`,
+		method:      http.MethodGet,
+		url:         `/api/nextdate?date=20240101&repeat=d+1&tz=Europe/Moscow`,
+		body:        ``,
+		resCode:     http.StatusOK,
+		resRegexp:   `^[0-9]{8}$`,
+		msg:         `next date, status 200, return date`,
+	},
+	{ //41
+		description: `wrong time zone of request`,
+		method:      http.MethodGet,
+		url:         `/api/nextdate?date=20240101&repeat=d+1&tz=Mars/Olympus`,
+		body:        ``,
+		resCode:     http.StatusBadRequest,
+		resRegexp:   `{"error":"unknown time zone"}`,
+		msg:         `unknown time zone, status 400, return JSON error`,
+	},
+	{ //42
+		description: `freeze debug clock valid`,
+		method:      http.MethodPut,
+		url:         `/api/debug/clock`,
+		body:        `{"now":"20240229 10:00"}`,
+		resCode:     http.StatusOK,
+		resRegexp:   `{"now":"20240229 10:00","frozen":true,"offset":"0s"}`,
+		msg:         `clock is frozen, status 200, return JSON with clock`,
+	},
+	{ //43
+		description: `next date by frozen clock valid`,
+		method:      http.MethodGet,
+		url:         `/api/nextdate?date=20240131&repeat=m+31`,
+		body:        ``,
+		resCode:     http.StatusOK,
+		resRegexp:   `^20240331$`,
+		msg:         `next date after frozen now, status 200, return date`,
+	},
+	{ //44
+		description: `preview by frozen clock valid`,
+		method:      http.MethodGet,
+		url:         `/api/nextdates?date=20240101&repeat=y&n=1`,
+		body:        ``,
+		resCode:     http.StatusOK,
+		resRegexp:   `{"dates":\["20250101"\]}`,
+		msg:         `dates after frozen now, status 200, return JSON with dates`,
+	},
+	{ //45
+		description: `freeze and move debug clock together`,
+		method:      http.MethodPut,
+		url:         `/api/debug/clock`,
+		body:        `{"now":"20240229","offset":"1h"}`,
+		resCode:     http.StatusBadRequest,
+		resRegexp:   `{"error":"clockdecode: error - {offset:now and offset can't be used together}"}`,
+		msg:         `now with offset, status 400, return JSON error`,
+	},
+	{ //46
+		description: `move debug clock valid`,
+		method:      http.MethodPut,
+		url:         `/api/debug/clock`,
+		body:        `{"offset":"48h"}`,
+		resCode:     http.StatusOK,
+		resRegexp:   `{"now":"[0-9]{8} [0-9]{2}:[0-9]{2}","frozen":false,"offset":"48h0m0s"}`,
+		msg:         `clock is moved, status 200, return JSON with clock`,
+	},
+	{ //47
+		description: `reset debug clock valid`,
+		method:      http.MethodDelete,
+		url:         `/api/debug/clock`,
+		body:        ``,
+		resCode:     http.StatusOK,
+		resRegexp:   `{"now":"[0-9]{8} [0-9]{2}:[0-9]{2}","frozen":false,"offset":"0s"}`,
+		msg:         `clock is real time, status 200, return JSON with clock`,
+	},
+	{ //48
+		description: `skip occurrence valid`,
+		method:      http.MethodPost,
+		url:         `/api/task/skip?id=4`,
+		body:        ``,
+		resCode:     http.StatusOK,
+		resRegexp:   `{}`,
+		msg:         `task is moved to next occurrence, status 200, return empty JSON`,
+	},
+	{ //49
+		description: `get task after skip valid`,
+		method:      http.MethodGet,
+		url:         `/api/task?id=4`,
+		body:        ``,
+		resCode:     http.StatusOK,
+		resRegexp:   `{"id":"4","date":"20990101","time":"13:00","title":"Drink water","comment":"","repeat":"h 4 09:00-18:00","algorithm":"nextdate","mode":"schedule"}`,
+		msg:         `next occurrence after skip, status 200, return JSON with task`,
+	},
+	{ //50
+		description: `skip task not found`,
+		method:      http.MethodPost,
+		url:         `/api/task/skip?id=99`,
+		body:        ``,
+		resCode:     http.StatusNotFound,
+		resRegexp:   `{"error":"task not found"}`,
+		msg:         `skip not exist task, status 404, return JSON error`,
+	},
+	{ //51
+		description: `new task with exception dates valid`,
+		method:      http.MethodPost,
+		url:         `/api/task`,
+		body:        `{"date":"20990105","title":"Backup","repeat":"d 1","exdates":["20990106","20990105"]}`,
+		resCode:     http.StatusCreated,
+		resRegexp:   `{"id":"5"}`,
+		msg:         `save new task, status 201, return ID`,
+	},
+	{ //52
+		description: `get task with exception dates valid`,
+		method:      http.MethodGet,
+		url:         `/api/task?id=5`,
+		body:        ``,
+		resCode:     http.StatusOK,
+		resRegexp:   `{"id":"5","date":"20990107","title":"Backup","comment":"","repeat":"d 1","algorithm":"nextdate","mode":"schedule","exdates":\["20990105","20990106"\]}`,
+		msg:         `exception dates are skipped, status 200, return JSON with task`,
+	},
+	{ //53
+		description: `new task invalid exception date`,
+		method:      http.MethodPost,
+		url:         `/api/task`,
+		body:        `{"date":"20990105","title":"Backup","repeat":"d 1","exdates":["2099-01-05"]}`,
+		resCode:     http.StatusBadRequest,
+		resRegexp:   `{"error":"taskdecode: error - {exdates:invalid exception date}"}`,
+		msg:         `bad exception date, status 400, return JSON error`,
+	},
+	{ //54
+		description: `task list by date with projected occurrence valid`,
+		method:      http.MethodGet,
+		url:         `/api/tasks?search=09.01.2099`,
+		body:        ``,
+		resCode:     http.StatusOK,
+		resRegexp:   `{"id":"5","date":"20990109","title":"Backup","comment":"","repeat":"d 1","algorithm":"nextdate","mode":"schedule","exdates":\["20990105","20990106"\],"projected":true}`,
+		msg:         `occurrence by rule of recurring task, status 200, return JSON with projected task`,
+	},
+	{ //55
+		description: `agenda valid`,
+		method:      http.MethodGet,
+		url:         `/api/agenda?from=20990107&to=20990108`,
+		body:        ``,
+		resCode:     http.StatusOK,
+		resRegexp:   `{"days":\[{"date":"20990107","tasks":\[{"id":"5","date":"20990107","title":"Backup","comment":"","repeat":"d 1","algorithm":"nextdate","mode":"schedule","exdates":\["20990105","20990106"\]},{"id":"4","date":"20990107","time":"09:00","title":"Drink water","comment":"","repeat":"h 4 09:00-18:00","algorithm":"nextdate","mode":"schedule","projected":true}\]},{"date":"20990108","tasks":\[{"id":"5","date":"20990108","title":"Backup","comment":"","repeat":"d 1","algorithm":"nextdate","mode":"schedule","exdates":\["20990105","20990106"\],"projected":true},{"id":"4","date":"20990108","time":"09:00","title":"Drink water","comment":"","repeat":"h 4 09:00-18:00","algorithm":"nextdate","mode":"schedule","projected":true}\]}\]}`,
+		msg:         `occurrences by days, status 200, return JSON with stored and projected tasks`,
+	},
+	{ //56
+		description: `agenda with too long window`,
+		method:      http.MethodGet,
+		url:         `/api/agenda?from=20990101&to=20991231`,
+		body:        ``,
+		resCode:     http.StatusBadRequest,
+		resRegexp:   `{"error":"agendadecode: error - {to:out of range}"}`,
+		msg:         `window more than limit, status 400, return JSON error`,
+	},
+	{ //57
+		description: `task list page valid`,
+		method:      http.MethodGet,
+		url:         `/api/tasks?limit=1`,
+		body:        ``,
+		resCode:     http.StatusOK,
+		resRegexp:   `^{"tasks":\[{"id":"\d+",.*}\],"next_cursor":"[\w-]+"}`,
+		msg:         `full page, status 200, return JSON with one task and cursor of next page`,
+	},
+	{ //58
+		description: `task list with wrong limit and cursor`,
+		method:      http.MethodGet,
+		url:         `/api/tasks?limit=1000&cursor=first`,
+		body:        ``,
+		resCode:     http.StatusBadRequest,
+		resRegexp:   `{"error":"tasklistdecode: error - {cursor:invalid cursor},{limit:out of range}"}`,
+		msg:         `limit more than max and not cursor of response, status 400, return JSON error`,
+	},
+	{ //59
+		description: `task list with filters valid`,
+		method:      http.MethodGet,
+		url:         `/api/tasks?from=20990107&to=20990107&kind=recurring&sort=title&order=desc`,
+		body:        ``,
+		resCode:     http.StatusOK,
+		resRegexp:   `^{"tasks":\[{"id":"5","date":"20990107","title":"Backup",.*}\]}`,
+		msg:         `recurring tasks of window by title, status 200, return JSON with one task`,
+	},
+	{ //60
+		description: `task list with unknown filters`,
+		method:      http.MethodGet,
+		url:         `/api/tasks?sort=time&order=up&overdue=1x`,
+		body:        ``,
+		resCode:     http.StatusBadRequest,
+		resRegexp:   `{"error":"tasklistdecode: error - {order:unknown value},{overdue:unknown value},{sort:unknown value}"}`,
+		msg:         `not allowed values of sort, order and overdue, status 400, return JSON error`,
+	},
+	{ //61
+		description: `task list with structured search valid`,
+		method:      http.MethodGet,
+		url:         `/api/tasks?search=title:Backup%20repeat:d%20after:2099-01-06%20-weekly`,
+		body:        ``,
+		resCode:     http.StatusOK,
+		resRegexp:   `^{"tasks":\[{"id":"5","date":"20990107","title":"Backup",.*}\]}`,
+		msg:         `recurring task by title and date, status 200, return JSON with one task`,
+	},
+	{ //62
+		description: `task list with wrong structured search`,
+		method:      http.MethodGet,
+		url:         `/api/tasks?search=repeat:every%20-draft`,
+		body:        ``,
+		resCode:     http.StatusBadRequest,
+		resRegexp:   `{"error":"tasklistdecode: error - {search:invalid search query at position 7: unknown repeat 'every'}"}`,
+		msg:         `unknown flag of rule, status 400, return JSON error`,
+	},
+	{ //63
+		description: `valid task done with note`,
+		method:      http.MethodPost,
+		url:         `/api/task/done?id=5`,
+		body:        `{"note":"backup checked"}`,
+		resCode:     http.StatusOK,
+		resRegexp:   `{}`,
+		msg:         `task is done, status 200, return empty JSON`,
+	},
+	{ //64
+		description: `task done with wrong body`,
+		method:      http.MethodPost,
+		url:         `/api/task/done?id=5`,
+		body:        `{"note":1}`,
+		resCode:     http.StatusBadRequest,
+		resRegexp:   `{"error":".+"}`,
+		msg:         `note is not string, status 400, return JSON error`,
+	},
+	{ //65
+		description: `history of task valid`,
+		method:      http.MethodGet,
+		url:         `/api/task/history?id=5`,
+		body:        ``,
+		resCode:     http.StatusOK,
+		resRegexp:   `^{"task_id":"5","completions":\[{"id":"\d+","date":"20990107","done_at":"[\dT:Z-]+","note":"backup checked"}\]}`,
+		msg:         `one completion with note, status 200, return JSON history`,
+	},
+	{ //66
+		description: `history of deleted task`,
+		method:      http.MethodGet,
+		url:         `/api/task/history?id=2`,
+		body:        ``,
+		resCode:     http.StatusOK,
+		resRegexp:   `^{"task_id":"2","completions":\[{"id":"\d+","date":"\d{8}","done_at":"[\dT:Z-]+"}\]}`,
+		msg:         `task is deleted after done, history is kept, status 200, return JSON history`,
+	},
+	{ //67
+		description: `history of unknown task`,
+		method:      http.MethodGet,
+		url:         `/api/task/history?id=99`,
+		body:        ``,
+		resCode:     http.StatusNotFound,
+		resRegexp:   `{"error":"task not found"}`,
+		msg:         `task not found, status 404, return JSON error`,
+	},
 }
 
 func TestRoutes(t *testing.T) {
@@ -277,6 +656,11 @@ func TestRoutes(t *testing.T) {
 			services.TaskUpdateCase
 			services.TaskDeleteCase
 			services.TaskDoneCase
+			services.TaskSkipCase
+			services.TaskPreviewCase
+			services.TaskAgendaCase
+			services.TaskHistoryCase
+			services.TaskAlgorithmCase
 		}
 
 		mockSheduler struct {
@@ -285,16 +669,23 @@ func TestRoutes(t *testing.T) {
 			services.AutorizationCase
 
 			services.LoginValidPasswordCase
+
+			usecase.ClockService
 		}
 	)
 
-	taskCase, err := usecase.NewTaskService(cfg, mock.NewMockTaskStore())
+	// routes of /api/debug/clock
+	cfg.DebugClock = true
+	clk := clock.NewDebug(clock.System{})
+
+	taskCase, err := usecase.NewTaskService(cfg, mock.NewMockTaskStore(), clk)
 	requires.NoError(err, fmt.Sprintf("transport_test: task service error - %v - should be no error", err))
 
 	sheduler := mockSheduler{
 		mockTaskCase:           taskCase,
 		AutorizationCase:       usecase.NewAuthService(),
 		LoginValidPasswordCase: usecase.NewLoginService(datauser.NewUserData(cfg)),
+		ClockService:           usecase.NewClockService(clk),
 	}
 
 	r := NewTransport(cfg)
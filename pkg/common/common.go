@@ -134,6 +134,12 @@ func ReduceTimeToDay(t time.Time) time.Time {
 	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
 }
 
+// ReduceTimeToDayUTC - yaer,month,day of 't' in own location as date in UTC
+// dates without time zone (parsed by time.Parse) are compared with it
+func ReduceTimeToDayUTC(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
 // HashPData - use 'sha256.Sum256' for hashing string line
 func HashData(line string) string {
 	hashLine := sha256.Sum256([]byte(line))
@@ -16,6 +16,11 @@ type Task struct {
 	Title   string `db:"title"`
 	Comment string `db:"comment"`
 	Repeat  string `db:"repeat"`
+
+	Algorithm string `db:"algorithm"`
+	Mode      string `db:"mode"`
+	Time      string `db:"time"`
+	Exdates   string `db:"exdates"`
 }
 
 func count(db *sqlx.DB) (int, error) {